@@ -19,6 +19,10 @@ import (
 // @license.url https://github.com/sguazu
 
 // @BasePath /v1
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
 func main() {
 	var DBConnection = database.NewDatabase()
 	err := server.Run(DBConnection)
@@ -1,20 +1,28 @@
 package server
 
 import (
+	"context"
+	"core_business/internals/core/domain"
 	"core_business/internals/core/ports"
 	"core_business/internals/core/services"
 	"core_business/internals/handlers"
 	"core_business/internals/repositories"
+	"core_business/pkg/broker"
 	"core_business/pkg/config"
+	"core_business/pkg/issuer"
 	"core_business/pkg/logger"
+	"core_business/pkg/sudo"
+	"core_business/pkg/webhook"
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
+	"time"
 )
 
 // Injection inject all dependencies
 func Injection() {
 	var logging *log.Logger
 	var cardRepository ports.ICardRepository = repositories.NewCardRepository(DBConnection)
+	var cardIssuers = newCardIssuers()
 
 	if config.Instance.Env == "development" {
 		logging = logger.NewLogger(log.New()).MakeLogger("logs/info", true)
@@ -42,8 +50,10 @@ func Injection() {
 		companyProfileService    = services.NewCompanyProfileService(companyProfileRepository, logging)
 		companyProfileHandler    = handlers.NewCompanyProfileHandler(companyProfileService, logging, "Company profile")
 
+		outboxRepository = repositories.NewOutboxRepository(DBConnection)
+
 		walletRepository = repositories.NewWalletRepository(DBConnection)
-		walletService    = services.NewWalletService(walletRepository, DBConnection, logging)
+		walletService    = services.NewWalletService(walletRepository, outboxRepository, DBConnection, logging)
 		walletHandler    = handlers.NewWalletHandler(walletService, logging, "Wallet")
 
 		expenseCategoryRepository = repositories.NewExpenseCategoryRepository(DBConnection)
@@ -52,26 +62,79 @@ func Injection() {
 
 		creditLimitRequestRepository = repositories.NewCreditLimitRequestRepository(DBConnection)
 
+		webhookRepository = repositories.NewWebhookRepository(DBConnection)
+		webhookService    = services.NewWebhookService(webhookRepository, webhook.NewSender(), logging)
+		webhookHandler    = handlers.NewWebhookHandler(webhookService, logging, "Webhook")
+
 		companyRepository = repositories.NewCompanyRepository(DBConnection)
-		companyService    = services.NewCompanyService(companyRepository, companyProfileRepository, walletRepository, creditLimitRequestRepository, logging)
+		companyService    = services.NewCompanyService(companyRepository, companyProfileRepository, walletRepository, creditLimitRequestRepository, webhookService, outboxRepository, DBConnection, logging)
 		companyHandler    = handlers.NewCompanyHandler(companyService, logging, "Company")
 
 		customerRepository = repositories.NewCustomerRepository(DBConnection)
 		feeRepository      = repositories.NewFeeRepository(DBConnection)
 		panRepository      = repositories.NewPANRepository(DBConnection)
 
+		receiptPolicyRepository = repositories.NewReceiptPolicyRepository(DBConnection)
+		receiptPolicyService    = services.NewReceiptPolicyService(receiptPolicyRepository, logging)
+		receiptPolicyHandler    = handlers.NewReceiptPolicyHandler(receiptPolicyService, logging, "Receipt policy")
+
+		tagRepository = repositories.NewTagRepository(DBConnection)
+		tagService    = services.NewTagService(tagRepository, logging)
+		tagHandler    = handlers.NewTagHandler(tagService, logging, "Tag")
+
+		accountingPeriodRepository = repositories.NewAccountingPeriodRepository(DBConnection)
+		accountingPeriodService    = services.NewAccountingPeriodService(accountingPeriodRepository, companyRepository, DBConnection, logging)
+		accountingPeriodHandler    = handlers.NewAccountingPeriodHandler(accountingPeriodService, logging, "Accounting period")
+
+		fraudRepository = repositories.NewFraudRepository(DBConnection)
+		fraudService    = services.NewFraudService(fraudRepository, cardRepository, cardIssuers, logging)
+		fraudHandler    = handlers.NewFraudHandler(fraudService, logging, "Fraud")
+
 		transactionRepository = repositories.NewTransactionRepository(DBConnection)
 		transactionService    = services.NewTransactionService(transactionRepository,
 			customerRepository, walletRepository, feeRepository,
-			companyRepository, cardRepository, walletService, logging)
+			companyRepository, cardRepository, receiptPolicyRepository,
+			tagRepository, walletService, fraudService,
+			accountingPeriodRepository, webhookService, outboxRepository,
+			cardIssuers, DBConnection, logging)
 		transactionHandler = handlers.NewTransactionHandler(transactionService, logging, "Transaction")
 
+		analyticsRepository = repositories.NewAnalyticsRepository(DBConnection)
+		analyticsService    = services.NewAnalyticsService(analyticsRepository, logging)
+		analyticsHandler    = handlers.NewAnalyticsHandler(analyticsService, logging, "Analytics")
+
+		disputeRepository = repositories.NewDisputeRepository(DBConnection)
+		disputeService    = services.NewDisputeService(disputeRepository, transactionRepository,
+			walletRepository, walletService, DBConnection, logging)
+		disputeHandler = handlers.NewDisputeHandler(disputeService, logging, "Dispute")
+
+		cardPolicyRepository = repositories.NewCardPolicyRepository(DBConnection)
+		cardPolicyService    = services.NewCardPolicyService(cardPolicyRepository, companyRepository, cardRepository, cardIssuers, logging)
+		cardPolicyHandler    = handlers.NewCardPolicyHandler(cardPolicyService, logging, "Card policy")
+
+		cardFulfillmentRepository = repositories.NewCardFulfillmentRepository(DBConnection)
+		cardFulfillmentService    = services.NewCardFulfillmentService(cardFulfillmentRepository, webhookService, logging)
+		cardFulfillmentHandler    = handlers.NewCardFulfillmentHandler(cardFulfillmentService, logging, "Card fulfillment")
+
 		cardService = services.NewCardService(cardRepository, customerRepository,
 			addressRepository, companyRepository, feeRepository,
 			walletService, transactionRepository, panRepository,
-			walletRepository, logging)
+			walletRepository, webhookService, outboxRepository,
+			cardIssuers, cardFulfillmentRepository, cardPolicyRepository, DBConnection, logging)
 
 		cardHandler = handlers.NewCardHandler(cardService, logging, "Card")
+
+		customerService = services.NewCustomerService(customerRepository, companyRepository, logging)
+		customerHandler = handlers.NewCustomerHandler(customerService, logging, "Customer")
+
+		cardRevealRepository = repositories.NewCardRevealRepository(DBConnection)
+		cardRevealService    = services.NewCardRevealService(cardRevealRepository, cardRepository, cardIssuers, logging)
+		cardRevealHandler    = handlers.NewCardRevealHandler(cardRevealService, logging, "Card reveal")
+
+		cardBatchRepository = repositories.NewCardBatchRepository(DBConnection)
+		cardBatchService    = services.NewCardBatchService(cardBatchRepository, companyRepository,
+			addressRepository, walletRepository, feeRepository, cardService, logging)
+		cardBatchHandler = handlers.NewCardBatchHandler(cardBatchService, logging, "Card batch")
 	)
 
 	v1 := ginRoutes.GROUP("v1")
@@ -120,7 +183,7 @@ func Injection() {
 	wallet := v1.Group("/wallet")
 	wallet.GET("/:id", walletHandler.GetWalletByID)
 	wallet.POST("/webhook", walletHandler.CreateWallet)
-	wallet.DELETE("/:id", walletHandler.DeleteWallet)
+	wallet.PATCH("/:id/close", walletHandler.CloseWallet)
 	wallet.PATCH("/:id", walletHandler.UpdateWallet)
 
 	expenseCategory := v1.Group("/expense_category")
@@ -135,23 +198,160 @@ func Injection() {
 	transaction.GET("/:id", transactionHandler.GetTransactionByID)
 	transaction.GET("/company/:id", transactionHandler.GetTransactionByCompanyID)
 	transaction.GET("/card/:id", transactionHandler.GetTransactionByCardID)
+	transaction.GET("/customer/:id", transactionHandler.GetTransactionByCustomerID)
+	transaction.GET("/customer/:id/missing_receipts", transactionHandler.GetMissingReceiptsByCustomerID)
 	transaction.POST("/webhook", transactionHandler.CreateTransaction)
 	transaction.PATCH("/:id", transactionHandler.UpdateTransaction)
-	transaction.DELETE("/:id", transactionHandler.DeleteTransaction)
+	transaction.POST("/:id/void", transactionHandler.VoidTransaction)
 	transaction.PATCH("/:id/lock", transactionHandler.LockTransaction)
+	transaction.POST("/:id/adjustments", transactionHandler.AdjustTransaction)
+	transaction.PATCH("/:id/splits", transactionHandler.SplitTransaction)
+	transaction.GET("/company/:id/categories", transactionHandler.GetCategoryTotalsByCompanyID)
+	transaction.GET("/company/:id/export", transactionHandler.ExportTransactionsByCompanyID)
+	transaction.PATCH("/:id/tags", transactionHandler.TagTransaction)
+	transaction.GET("/:id/comments", transactionHandler.GetCommentsByTransactionID)
+	transaction.POST("/:id/comments", handlers.Authenticate(config.Instance.JWTSecret), transactionHandler.CreateTransactionComment)
+
+	webhooks := v1.Group("/webhook")
+	webhooks.GET("/:id", webhookHandler.GetWebhookByID)
+	webhooks.GET("/company/:id", webhookHandler.GetWebhookByCompanyID)
+	webhooks.POST("/", webhookHandler.CreateWebhook)
+	webhooks.PATCH("/:id", webhookHandler.UpdateWebhook)
+	webhooks.DELETE("/:id", webhookHandler.DeleteWebhook)
+	webhooks.GET("/:id/deliveries", webhookHandler.GetDeliveriesByWebhookID)
+	webhooks.POST("/delivery/:id/redeliver", webhookHandler.RedeliverWebhook)
+
+	go func() {
+		for range time.Tick(time.Minute) {
+			webhookService.RetryDueDeliveries()
+		}
+	}()
+
+	// scheduled jobs run on whichever instance holds their lease, starting at startup
+	scheduler := services.NewJobScheduler(repositories.NewJobLeaseRepository(DBConnection), logging)
+
+	scheduler.Every("reconcile_card_locks", time.Hour, func() error {
+		_, err := cardService.ReconcileCardLocks(context.Background())
+		return err
+	})
+
+	scheduler.Every("renew_expiring_cards", 24*time.Hour, func() error {
+		if _, err := cardService.RenewExpiringCards(context.Background(), config.Instance.ExpiryNoticeDays); err != nil {
+			logging.Error(err)
+		}
+
+		_, err := cardService.ExpireCardsPastValidity(context.Background())
+		return err
+	})
+
+	go func() {
+		// rows a dead worker left claimed are taken over once the claim goes stale
+		ticker := time.NewTicker(domain.CardBatchClaimTTL)
+		for ; true; <-ticker.C {
+			cardBatchService.ResumeCardBatches()
+		}
+	}()
+
+	if config.Instance.RabbitMQURL != nil {
+		rabbitMQ, err := broker.NewRabbitMQBroker(*config.Instance.RabbitMQURL, "core_business.events")
+		if err != nil {
+			logging.Fatal(err)
+		}
+
+		outboxRelay := services.NewOutboxRelay(outboxRepository, rabbitMQ, logging)
+		scheduler.Every("relay_outbox", 5*time.Second, func() error {
+			// the relay logs its own failures and retries them on the next run
+			outboxRelay.RelayPending()
+			return nil
+		})
+	}
+
+	tag := v1.Group("/tag")
+	tag.GET("/:id", tagHandler.GetTagByID)
+	tag.GET("/company/:id", tagHandler.GetTagByCompanyID)
+	tag.POST("/", tagHandler.CreateTag)
+	tag.PATCH("/:id", tagHandler.UpdateTag)
+	tag.DELETE("/:id", tagHandler.DeleteTag)
+
+	receiptPolicy := v1.Group("/receipt_policy")
+	receiptPolicy.GET("/:id", receiptPolicyHandler.GetReceiptPolicyByID)
+	receiptPolicy.GET("/company/:id", receiptPolicyHandler.GetReceiptPolicyByCompanyID)
+	receiptPolicy.POST("/", receiptPolicyHandler.CreateReceiptPolicy)
+	receiptPolicy.PATCH("/:id", receiptPolicyHandler.UpdateReceiptPolicy)
+	receiptPolicy.DELETE("/:id", receiptPolicyHandler.DeleteReceiptPolicy)
+
+	analytics := v1.Group("/analytics")
+	analytics.GET("/company/:id/spend", analyticsHandler.GetSpendByCompanyID)
+
+	period := v1.Group("/period")
+	period.GET("/:id", accountingPeriodHandler.GetPeriodByID)
+	period.GET("/company/:id", accountingPeriodHandler.GetPeriodByCompanyID)
+	period.POST("/close", accountingPeriodHandler.ClosePeriod)
+	period.PATCH("/:id/reopen", handlers.Authenticate(config.Instance.JWTSecret), accountingPeriodHandler.ReopenPeriod)
+
+	fraud := v1.Group("/fraud")
+	fraud.GET("/rule/company/:id", fraudHandler.GetFraudRulesByCompanyID)
+	fraud.POST("/rule/", fraudHandler.CreateFraudRule)
+	fraud.PATCH("/rule/:id", fraudHandler.UpdateFraudRule)
+	fraud.DELETE("/rule/:id", fraudHandler.DeleteFraudRule)
+	fraud.GET("/alert/company/:id", fraudHandler.GetFraudAlertsByCompanyID)
+	fraud.PATCH("/alert/:id/review", fraudHandler.ReviewFraudAlert)
+
+	dispute := v1.Group("/dispute")
+	dispute.GET("/:id", disputeHandler.GetDisputeByID)
+	dispute.GET("/company/:id", disputeHandler.GetDisputeByCompanyID)
+	dispute.POST("/", disputeHandler.OpenDispute)
+	dispute.POST("/:id/evidence", disputeHandler.AddDisputeEvidence)
+	dispute.PATCH("/:id/status", disputeHandler.ChangeDisputeStatus)
 
 	card := v1.Group("/card")
 	card.GET("/", cardHandler.GetAllCard)
 	card.GET("/:id", cardHandler.GetCardByID)
 	card.GET("/company/:id", cardHandler.GetCardByCompanyID)
+	card.GET("/customer/:id", cardHandler.GetCardByCustomerID)
 	card.POST("/", cardHandler.CreateCard)
 	card.PATCH("/:id", cardHandler.UpdateCard)
 	card.PATCH("/:id/cancel", cardHandler.CancelCard)
 	card.PATCH("/:id/lock", cardHandler.LockCard)
-	card.PATCH("/:id/change-pin", cardHandler.ChangeCardPin)
+	card.PATCH("/:id/status", cardHandler.ChangeCardStatus)
+	card.GET("/:id/status_history", cardHandler.GetCardStatusHistory)
+	card.POST("/reconcile_locks", cardHandler.ReconcileCardLocks)
+	card.POST("/renew_expiring", cardHandler.RenewExpiringCards)
+	card.GET("/:id/recurring_merchants", cardHandler.GetCardRecurringMerchants)
+	card.PATCH("/:id/schedule", cardHandler.UpdateCardSchedule)
+	card.PATCH("/:id/change_pin", cardHandler.ChangeCardPin)
 	card.POST("/pan", cardHandler.AddPAN)
 	card.GET("/pan", cardHandler.GetSinglePAN)
 	card.DELETE("/pan/:id", cardHandler.DeletePAN)
+	card.POST("/:id/reveal_token", handlers.Authenticate(config.Instance.JWTSecret), cardRevealHandler.CreateRevealToken)
+	card.POST("/reveal", handlers.Authenticate(config.Instance.JWTSecret), cardRevealHandler.RevealCard)
+	card.GET("/:id/reveals", cardRevealHandler.GetCardReveals)
+
+	cardPolicy := v1.Group("/card_policy")
+	cardPolicy.GET("/:id", cardPolicyHandler.GetCardPolicyByID)
+	cardPolicy.GET("/company/:id", cardPolicyHandler.GetCardPolicyByCompanyID)
+	cardPolicy.POST("/", cardPolicyHandler.CreateCardPolicy)
+	cardPolicy.PATCH("/:id", cardPolicyHandler.UpdateCardPolicy)
+	cardPolicy.DELETE("/:id", cardPolicyHandler.DeleteCardPolicy)
+
+	fulfillment := v1.Group("/fulfillment")
+	fulfillment.GET("/:id", cardFulfillmentHandler.GetFulfillmentByID)
+	fulfillment.GET("/card/:id", cardFulfillmentHandler.GetFulfillmentByCardID)
+	fulfillment.GET("/company/:id", cardFulfillmentHandler.GetFulfillmentByCompanyID)
+	fulfillment.PATCH("/:id/status", cardFulfillmentHandler.ChangeFulfillmentStatus)
+	fulfillment.PATCH("/:id/address", cardFulfillmentHandler.UpdateShippingAddress)
+
+	customer := v1.Group("/customer")
+	customer.GET("/:id", customerHandler.GetCustomerByID)
+	customer.GET("/company/:id", customerHandler.GetCustomerByCompanyID)
+	customer.POST("/", customerHandler.CreateCustomer)
+	customer.PATCH("/:id", customerHandler.UpdateCustomer)
+
+	cardBatch := v1.Group("/card_batch")
+	cardBatch.GET("/:id", cardBatchHandler.GetCardBatchByID)
+	cardBatch.GET("/company/:id", cardBatchHandler.GetCardBatchByCompanyID)
+	cardBatch.POST("/", cardBatchHandler.CreateCardBatch)
+	cardBatch.GET("/:id/result", cardBatchHandler.DownloadCardBatchResult)
 
 	err := ginRoutes.SERVE()
 
@@ -160,3 +360,21 @@ func Injection() {
 	}
 
 }
+
+// newCardIssuers registers the issuers cards can be issued with, the in-memory issuer is not offered in production
+func newCardIssuers() ports.ICardIssuers {
+	issuers := []ports.ICardIssuer{
+		issuer.NewSudo(sudo.NewClient(config.Instance.SudoBaseURL, config.Instance.SudoAPIKey, sudo.DefaultTimeout),
+			config.Instance.FundingSource, config.Instance.Env == "development"),
+	}
+
+	if config.Instance.Env != "production" {
+		issuers = append(issuers, issuer.NewMemory())
+	}
+
+	fallback := issuer.SudoName
+	if config.Instance.CardIssuer != nil {
+		fallback = *config.Instance.CardIssuer
+	}
+	return issuer.NewRegistry(fallback, issuers...)
+}
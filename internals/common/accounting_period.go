@@ -0,0 +1,28 @@
+package common
+
+import (
+	uuid "github.com/satori/go.uuid"
+)
+
+// ClosePeriodRequest DTO to close a month of a company, its transactions become immutable
+type ClosePeriodRequest struct {
+	Company  uuid.UUID `json:"company" binding:"required"`
+	Year     int       `json:"year" binding:"required,min=2000"`
+	Month    int       `json:"month" binding:"required,min=1,max=12"`
+	ClosedBy string    `json:"closed_by" binding:"required"`
+	Reason   string    `json:"reason"`
+}
+
+// ReopenPeriodRequest DTO to reopen a closed period, only the company owner can reopen and the owner is the
+// authenticated user of the request
+type ReopenPeriodRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// AdjustTransactionRequest DTO to post an adjusting entry against a transaction
+type AdjustTransactionRequest struct {
+	Amount          float64 `json:"amount" binding:"required,gt=0"`
+	Entry           string  `json:"entry" binding:"required"` // DEBIT or CREDIT
+	ExpenseCategory string  `json:"expense_category"`
+	Note            string  `json:"note" binding:"required"`
+}
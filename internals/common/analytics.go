@@ -0,0 +1,13 @@
+package common
+
+import (
+	"time"
+)
+
+// SpendAnalyticsRequest DTO query of the spend analytics, the period defaults to the last 30 days
+type SpendAnalyticsRequest struct {
+	GroupBy string    `form:"group_by,default=category" binding:"oneof=category card cardholder merchant channel month"`
+	From    time.Time `form:"from" time_format:"2006-01-02"`
+	To      time.Time `form:"to" time_format:"2006-01-02"` // exclusive
+	Limit   int       `form:"limit,default=10" binding:"min=1,max=100"`
+}
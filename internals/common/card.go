@@ -43,81 +43,13 @@ type CreateCardRequest struct {
 	Brand            string           `json:"brand" binding:"required" form:"default:verve"`
 	Status           string           `json:"status" form:"default:'active'"`
 	Summary          string           `json:"summary"`
-	User             User             `json:"user" binding:"required"`
-	SpendingControls SpendingControls `json:"spendingControls" binding:"required"`
-}
-
-// CreateSudoCardRequest DTO
-type CreateSudoCardRequest struct {
-	Type             string           `json:"type"`
-	Brand            string           `json:"brand"`
-	Number           string           `json:"number,omitempty"`
-	Currency         string           `json:"currency"`
-	Status           string           `json:"status"`
-	CustomerID       string           `json:"customerId"`
-	FundingSourceID  string           `json:"fundingSourceId"`
+	Customer         *uuid.UUID       `json:"customer,omitempty"` // cardholder to issue to, user is used when not set
+	User             User             `json:"user"`
 	SpendingControls SpendingControls `json:"spendingControls"`
-}
-
-// Company DTO
-type Company struct {
-	Name string `json:"name"`
-}
-
-type Individual struct {
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-}
-
-// BillingAddress DTO
-type BillingAddress struct {
-	Line1      string `json:"line1"`
-	City       string `json:"city"`
-	State      string `json:"state"`
-	PostalCode string `json:"postalCode"`
-	Country    string `json:"country"`
-}
-
-// CreateCustomerRequest DTO
-type CreateCustomerRequest struct {
-	Type           string `json:"type"`
-	Company        `json:"company"`
-	Individual     `json:"individual"`
-	Status         string `json:"status"`
-	BillingAddress `json:"billingAddress"`
-	Name           string `json:"name"`
-	Phone          string `json:"phoneNumber"`
-	Email          string `json:"emailAddress"`
-}
-
-// CreateSudoCustomerResponse DTO
-type CreateSudoCustomerResponse struct {
-	StatusCode int         `json:"statusCode"`
-	Message    interface{} `json:"message"`
-	Data       struct {
-		Business    string `json:"business"`
-		Type        string `json:"type"`
-		Name        string `json:"name"`
-		PhoneNumber string `json:"phoneNumber"`
-		Status      string `json:"status"`
-		Company     struct {
-			Name string `json:"name"`
-			ID   string `json:"_id"`
-		} `json:"company"`
-		BillingAddress struct {
-			Line1      string `json:"line1"`
-			City       string `json:"city"`
-			State      string `json:"state"`
-			Country    string `json:"country"`
-			PostalCode string `json:"postalCode"`
-			ID         string `json:"_id"`
-		} `json:"billingAddress"`
-		IsDeleted bool      `json:"isDeleted"`
-		CreatedAt time.Time `json:"createdAt"`
-		UpdatedAt time.Time `json:"updatedAt"`
-		ID        string    `json:"_id"`
-		V         int       `json:"__v"`
-	} `json:"data"`
+	ShippingAddress  *ShippingAddress `json:"shipping_address,omitempty"` // physical cards only, defaults to the company address
+	Mode             string           `json:"mode"`                       // general, single_use or merchant_locked, virtual cards only
+	Policy           *uuid.UUID       `json:"policy,omitempty"`           // card policy to issue on, its spending controls replace the ones sent
+	Schedule         *CardSchedule    `json:"schedule,omitempty"`         // when the card can be used, replaces the schedule of the policy
 }
 
 // UpdateSudoCardRequest UPDATE card struct
@@ -140,20 +72,16 @@ type UpdateSudoCardRequest struct {
 
 // ActionOnCardRequest DTO lock card
 type ActionOnCardRequest struct {
-	Lock bool `json:"status"`
+	Lock   bool   `json:"status"`
+	Actor  string `json:"actor"`
+	Reason string `json:"reason"`
 }
 
-// ChangeCardStatusRequest DTO to change card status
+// ChangeCardStatusRequest DTO to change card status, actor and reason go to the card status history
 type ChangeCardStatusRequest struct {
 	Status string `json:"status" binding:"required"`
 	Reason string `json:"reason"`
-}
-
-// CancelCardRequest DTO to cancel card
-type CancelCardRequest struct {
-	Status           string `json:"status"`
-	Reason           string `json:"cancellationReason"`
-	SpendingControls `json:"spendingControls"`
+	Actor  string `json:"actor"`
 }
 
 // ChangeCardPinRequest DTO to Change card pin
@@ -162,50 +90,6 @@ type ChangeCardPinRequest struct {
 	NewPin string `json:"newPin"`
 }
 
-// CreateSudoCardResponse DTO response for create card
-type CreateSudoCardResponse struct {
-	StatusCode int         `json:"statusCode"`
-	Message    interface{} `json:"message"`
-	Error      string      `json:"error"`
-	Data       struct {
-		Business         string `json:"business"`
-		Customer         string `json:"customer"`
-		Account          string `json:"account"`
-		FundingSource    string `json:"fundingSource"`
-		Type             string `json:"type"`
-		Brand            string `json:"brand"`
-		Currency         string `json:"currency"`
-		MaskedPan        string `json:"maskedPan"`
-		ExpiryMonth      string `json:"expiryMonth"`
-		ExpiryYear       string `json:"expiryYear"`
-		Status           string `json:"status"`
-		SpendingControls struct {
-			Channels struct {
-				Atm    bool   `json:"atm"`
-				Pos    bool   `json:"pos"`
-				Web    bool   `json:"web"`
-				Mobile bool   `json:"mobile"`
-				ID     string `json:"_id"`
-			} `json:"channels"`
-			AllowedCategories []string `json:"allowedCategories"`
-			BlockedCategories []string `json:"blockedCategories"`
-			SpendingLimits    []struct {
-				Amount     int           `json:"amount"`
-				Interval   string        `json:"interval"`
-				Categories []interface{} `json:"categories"`
-				ID         string        `json:"_id"`
-			} `json:"spendingLimits"`
-			ID string `json:"_id"`
-		} `json:"spendingControls"`
-		Is2FAEnrolled bool      `json:"is2FAEnrolled"`
-		IsDeleted     bool      `json:"isDeleted"`
-		CreatedAt     time.Time `json:"createdAt"`
-		UpdatedAt     time.Time `json:"updatedAt"`
-		ID            string    `json:"_id"`
-		V             int       `json:"__v"`
-	} `json:"data"`
-}
-
 // GetCardResponse DTO response for get single card
 type GetCardResponse struct {
 	ID                uuid.UUID `json:"ID,omitempty"`
@@ -244,6 +128,7 @@ type GetCardResponse struct {
 	MaskedPan   string `json:"maskedPan"`
 	ExpiryMonth string `json:"expiryMonth"`
 	ExpiryYear  string `json:"expiryYear"`
+	Mode        string `json:"mode"`
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	DeletedAt   *time.Time
@@ -270,25 +155,38 @@ type GetSinglePANResponse struct {
 	} `json:"data"`
 }
 
-type ProcessCardUpdate struct {
-	StatusCode int         `json:"statusCode"`
-	Message    interface{} `json:"message"`
-	Error      string      `json:"error"`
-}
-
 type AddPANRequest struct {
 	Numbers []string `json:"numbers"`
 }
 
-type SudoPANNumber struct {
-	StatusCode int    `json:"statusCode"`
-	Message    string `json:"message"`
-	Data       struct {
-		Brand       string `json:"brand"`
+// RevealCardRequest DTO to reveal the details of a card with a reveal token
+type RevealCardRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// RevealTokenResponse DTO
+type RevealTokenResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+	Data    struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	} `json:"data"`
+}
+
+// RevealCardResponse DTO
+type RevealCardResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+	Data    struct {
 		Number      string `json:"number"`
-		ExpiryMonth string `json:"expiryMonth"`
-		ExpiryYear  string `json:"expiryYear"`
-		Cvv2        string `json:"cvv2"`
-		DefaultPin  string `json:"defaultPin"`
+		CVV         string `json:"cvv"`
+		ExpiryMonth string `json:"expiry_month"`
+		ExpiryYear  string `json:"expiry_year"`
 	} `json:"data"`
 }
+
+// RenewExpiringCardsRequest DTO to run the card expiry job, days defaults to 30
+type RenewExpiringCardsRequest struct {
+	Days int `form:"days" binding:"min=0"`
+}
@@ -0,0 +1,56 @@
+package common
+
+import (
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+// CreateCardBatchRequest DTO to issue cards from a CSV of employees, the CSV is sent as the file field
+type CreateCardBatchRequest struct {
+	Company string `form:"company" binding:"required"`
+}
+
+// GetCardBatchResponse DTO
+type GetCardBatchResponse struct {
+	ID       uuid.UUID `json:"id"`
+	Company  uuid.UUID `json:"company"`
+	Wallet   uuid.UUID `json:"wallet"`
+	Status   string    `json:"status"`
+	Total    int       `json:"total"`
+	Issued   int       `json:"issued"`
+	Failed   int       `json:"failed"`
+	TotalFee int64     `json:"total_fee"`
+	Rows     []struct {
+		ID        uuid.UUID  `json:"id"`
+		Line      int        `json:"line"`
+		FirstName string     `json:"first_name"`
+		LastName  string     `json:"last_name"`
+		Email     string     `json:"email"`
+		Phone     string     `json:"phone"`
+		Type      string     `json:"type"`
+		Brand     string     `json:"brand"`
+		Template  string     `json:"template"`
+		Status    string     `json:"status"`
+		Card      *uuid.UUID `json:"card"`
+		Error     string     `json:"error"`
+	} `json:"rows"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// GetCardBatchDataResponse returns card batch response
+type GetCardBatchDataResponse struct {
+	Success bool                 `json:"success"`
+	Message string               `json:"message"`
+	Data    GetCardBatchResponse `json:"data"`
+}
+
+// CardBatchValidationResponse returns the rows of the CSV that were rejected
+type CardBatchValidationResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+	Data    []struct {
+		Line  int    `json:"line"`
+		Error string `json:"error"`
+	} `json:"data"`
+}
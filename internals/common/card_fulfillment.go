@@ -0,0 +1,57 @@
+package common
+
+import (
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+// ShippingAddress DTO where a physical card is delivered
+type ShippingAddress struct {
+	Recipient  string `json:"recipient"`
+	Phone      string `json:"phone"`
+	Line1      string `json:"line1" binding:"required"`
+	Line2      string `json:"line2"`
+	City       string `json:"city" binding:"required"`
+	State      string `json:"state" binding:"required"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country" binding:"required"`
+}
+
+// ChangeFulfillmentStatusRequest DTO to move a fulfillment, courier and tracking reference are set when it is dispatched
+type ChangeFulfillmentStatusRequest struct {
+	Status            string `json:"status" binding:"required"`
+	Courier           string `json:"courier"`
+	TrackingReference string `json:"tracking_reference"`
+	Note              string `json:"note"`
+	Actor             string `json:"actor"`
+}
+
+// GetCardFulfillmentResponse DTO
+type GetCardFulfillmentResponse struct {
+	ID                uuid.UUID       `json:"id"`
+	Card              uuid.UUID       `json:"card"`
+	Company           uuid.UUID       `json:"company"`
+	Status            string          `json:"status"`
+	ShippingAddress   ShippingAddress `json:"shipping_address"`
+	Courier           string          `json:"courier"`
+	TrackingReference string          `json:"tracking_reference"`
+	DispatchedAt      *time.Time      `json:"dispatched_at"`
+	DeliveredAt       *time.Time      `json:"delivered_at"`
+	Events            []struct {
+		ID        uuid.UUID `json:"id"`
+		From      string    `json:"from"`
+		To        string    `json:"to"`
+		Actor     string    `json:"actor"`
+		Note      string    `json:"note"`
+		CreatedAt time.Time `json:"created_at"`
+	} `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// GetCardFulfillmentDataResponse returns card fulfillment response
+type GetCardFulfillmentDataResponse struct {
+	Success bool                       `json:"success"`
+	Message string                     `json:"message"`
+	Data    GetCardFulfillmentResponse `json:"data"`
+}
@@ -0,0 +1,61 @@
+package common
+
+import (
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+// CreateCardPolicyRequest DTO to create a card policy, a preset fills the fields the request leaves out
+type CreateCardPolicyRequest struct {
+	Company          uuid.UUID         `json:"company" binding:"required"`
+	Name             string            `json:"name"`
+	Description      string            `json:"description"`
+	Preset           string            `json:"preset"` // sales-travel, marketing-saas or fleet-fuel
+	SpendingControls *SpendingControls `json:"spendingControls,omitempty"`
+	ValidityDays     *int              `json:"validity_days,omitempty" binding:"omitempty,min=0"`
+	Schedule         *CardSchedule     `json:"schedule,omitempty"`
+}
+
+// UpdateCardPolicyRequest DTO to update a card policy, propagate applies the spending controls to the cards on it
+type UpdateCardPolicyRequest struct {
+	Name             *string           `json:"name,omitempty"`
+	Description      *string           `json:"description,omitempty"`
+	SpendingControls *SpendingControls `json:"spendingControls,omitempty"`
+	ValidityDays     *int              `json:"validity_days,omitempty" binding:"omitempty,min=0"`
+	Schedule         *CardSchedule     `json:"schedule,omitempty"` // a schedule without windows removes it
+	Propagate        bool              `json:"propagate"`
+}
+
+// GetCardPolicyResponse DTO
+type GetCardPolicyResponse struct {
+	ID               uuid.UUID        `json:"id"`
+	Company          uuid.UUID        `json:"company"`
+	Name             string           `json:"name"`
+	Description      string           `json:"description"`
+	SpendingControls SpendingControls `json:"spending_controls"`
+	ValidityDays     int              `json:"validity_days"`
+	Schedule         *CardSchedule    `json:"schedule"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at"`
+}
+
+// GetCardPolicyDataResponse returns card policy response
+type GetCardPolicyDataResponse struct {
+	Success bool                  `json:"success"`
+	Message string                `json:"message"`
+	Data    GetCardPolicyResponse `json:"data"`
+}
+
+// UpdateCardPolicyDataResponse returns the updated card policy and how its cards were changed
+type UpdateCardPolicyDataResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+	Data    struct {
+		Policy      GetCardPolicyResponse `json:"policy"`
+		Propagation *struct {
+			Cards   int         `json:"cards"`
+			Updated int         `json:"updated"`
+			Failed  []uuid.UUID `json:"failed"`
+		} `json:"propagation,omitempty"`
+	} `json:"data"`
+}
@@ -0,0 +1,19 @@
+package common
+
+// ScheduleWindow DTO days and the time of day a card can be used on them
+type ScheduleWindow struct {
+	Days  []string `json:"days"`                     // monday to sunday or mon to sun, every day when empty
+	Start string   `json:"start" binding:"required"` // HH:MM
+	End   string   `json:"end" binding:"required"`   // HH:MM, before start to run past midnight
+}
+
+// CardSchedule DTO when a card can be used, e.g. weekdays 08:00 to 20:00 in Africa/Lagos
+type CardSchedule struct {
+	TimeZone string           `json:"time_zone"` // Africa/Lagos when empty
+	Windows  []ScheduleWindow `json:"windows"`
+}
+
+// UpdateCardScheduleRequest DTO to set when a card can be used, a schedule without windows removes it
+type UpdateCardScheduleRequest struct {
+	Schedule CardSchedule `json:"schedule"`
+}
@@ -48,11 +48,13 @@ type GetCompany struct {
 
 // UpdateCompanyRequest DTO to update company
 type UpdateCompanyRequest struct {
-	Name          *string `json:"name,omitempty"`
-	Type          *string `json:"type,omitempty"`
-	Website       *string `json:"website,omitempty"`
-	FundingSource *string `json:"funding_source,omitempty"`
-	NoOfEmployee  *string `json:"no_of_employee,omitempty"`
+	Name            *string `json:"name,omitempty"`
+	Type            *string `json:"type,omitempty"`
+	Website         *string `json:"website,omitempty"`
+	FundingSource   *string `json:"funding_source,omitempty"`
+	NoOfEmployee    *string `json:"no_of_employee,omitempty"`
+	CardIssuer      *string `json:"card_issuer,omitempty"`
+	CardAutoRenewal *bool   `json:"card_auto_renewal,omitempty"`
 }
 
 // CreateCompanyResponse DTO get all companies
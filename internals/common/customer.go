@@ -0,0 +1,47 @@
+package common
+
+import (
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+// CreateCustomerRequest DTO to add a cardholder to a company
+type CreateCustomerRequest struct {
+	Company   uuid.UUID `json:"company" binding:"required"`
+	User      string    `json:"user"`
+	FirstName string    `json:"first_name" binding:"required"`
+	LastName  string    `json:"last_name" binding:"required"`
+	Email     string    `json:"email" binding:"required,email"`
+	Phone     string    `json:"phone" binding:"required"`
+}
+
+// UpdateCustomerRequest DTO to change the details of a cardholder
+type UpdateCustomerRequest struct {
+	User      *string `json:"user,omitempty"`
+	FirstName *string `json:"first_name,omitempty"`
+	LastName  *string `json:"last_name,omitempty"`
+	Email     *string `json:"email,omitempty" binding:"omitempty,email"`
+	Phone     *string `json:"phone,omitempty"`
+}
+
+// GetCustomerResponse DTO
+type GetCustomerResponse struct {
+	ID                uuid.UUID `json:"id"`
+	Company           uuid.UUID `json:"company"`
+	User              string    `json:"user"`
+	FirstName         string    `json:"first_name"`
+	LastName          string    `json:"last_name"`
+	Email             string    `json:"email"`
+	Phone             string    `json:"phone"`
+	PartnerCustomerID string    `json:"partner_customer_id"`
+	Partner           string    `json:"partner"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// GetCustomerDataResponse returns customer response
+type GetCustomerDataResponse struct {
+	Success bool                `json:"success"`
+	Message string              `json:"message"`
+	Data    GetCustomerResponse `json:"data"`
+}
@@ -0,0 +1,57 @@
+package common
+
+import (
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+// DisputeEvidenceRequest DTO to attach evidence to a dispute
+type DisputeEvidenceRequest struct {
+	URL         string `json:"url" binding:"required"`
+	Description string `json:"description"`
+}
+
+// OpenDisputeRequest DTO to dispute a card transaction
+type OpenDisputeRequest struct {
+	Transaction uuid.UUID                `json:"transaction" binding:"required"`
+	Reason      string                   `json:"reason" binding:"required"`
+	Explanation string                   `json:"explanation"`
+	Amount      *float64                 `json:"amount,omitempty"` // defaults to the full transaction amount
+	Evidence    []DisputeEvidenceRequest `json:"evidence"`
+}
+
+// ChangeDisputeStatusRequest DTO to move a dispute through its workflow
+type ChangeDisputeStatusRequest struct {
+	Status           string `json:"status" binding:"required"`
+	PartnerReference string `json:"partner_reference"`
+}
+
+// GetDisputeResponse DTO
+type GetDisputeResponse struct {
+	ID                uuid.UUID  `json:"id"`
+	Company           uuid.UUID  `json:"company"`
+	Wallet            uuid.UUID  `json:"wallet"`
+	Card              uuid.UUID  `json:"card"`
+	Transaction       uuid.UUID  `json:"transaction"`
+	Amount            float64    `json:"amount"`
+	Reason            string     `json:"reason"`
+	Explanation       string     `json:"explanation"`
+	Status            string     `json:"status"`
+	PartnerReference  string     `json:"partner_reference"`
+	ProvisionalCredit string     `json:"provisional_credit"`
+	ResolvedAt        *time.Time `json:"resolved_at"`
+	Evidence          []struct {
+		ID          uuid.UUID `json:"id"`
+		URL         string    `json:"url"`
+		Description string    `json:"description"`
+	} `json:"evidence"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// GetDisputeDataResponse returns dispute response
+type GetDisputeDataResponse struct {
+	Success bool               `json:"success"`
+	Message string             `json:"message"`
+	Data    GetDisputeResponse `json:"data"`
+}
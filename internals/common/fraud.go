@@ -0,0 +1,36 @@
+package common
+
+import (
+	uuid "github.com/satori/go.uuid"
+)
+
+// CreateFraudRuleRequest DTO to add a fraud rule to a company, once a company has rules the defaults stop running
+type CreateFraudRuleRequest struct {
+	Company       uuid.UUID `json:"company" binding:"required"`
+	Type          string    `json:"type" binding:"required"`
+	Action        string    `json:"action"` // ALERT, DECLINE or LOCK, defaults to ALERT
+	Threshold     int       `json:"threshold" binding:"min=0"`
+	WindowMinutes int       `json:"window_minutes" binding:"min=0"`
+	Multiplier    float64   `json:"multiplier" binding:"min=0"`
+	MinHistory    int       `json:"min_history" binding:"min=0"`
+	HomeCountry   string    `json:"home_country"`
+}
+
+// UpdateFraudRuleRequest DTO to update a fraud rule
+type UpdateFraudRuleRequest struct {
+	Action        *string  `json:"action,omitempty"`
+	Threshold     *int     `json:"threshold,omitempty"`
+	WindowMinutes *int     `json:"window_minutes,omitempty"`
+	Multiplier    *float64 `json:"multiplier,omitempty"`
+	MinHistory    *int     `json:"min_history,omitempty"`
+	HomeCountry   *string  `json:"home_country,omitempty"`
+	Active        *bool    `json:"active,omitempty"`
+}
+
+// ReviewFraudAlertRequest DTO to close a fraud alert, dismissing with unlock_card unlocks a card the alert locked
+type ReviewFraudAlertRequest struct {
+	Status     string `json:"status" binding:"required"` // CONFIRMED or DISMISSED
+	ReviewedBy string `json:"reviewed_by" binding:"required"`
+	Note       string `json:"note"`
+	UnlockCard bool   `json:"unlock_card"`
+}
@@ -0,0 +1,18 @@
+package common
+
+import (
+	"encoding/json"
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+// DomainEventMessage envelope of a domain event on the broker
+type DomainEventMessage struct {
+	ID          uuid.UUID       `json:"id"`
+	Type        string          `json:"type"`
+	Aggregate   string          `json:"aggregate"`
+	AggregateID uuid.UUID       `json:"aggregate_id"`
+	Company     uuid.UUID       `json:"company"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	Data        json.RawMessage `json:"data"`
+}
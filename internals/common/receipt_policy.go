@@ -0,0 +1,45 @@
+package common
+
+import (
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+// CreateReceiptPolicyRequest DTO to create receipt policy
+type CreateReceiptPolicyRequest struct {
+	Company            uuid.UUID `json:"company" binding:"required"`
+	AmountThreshold    float64   `json:"amount_threshold" binding:"min=0"`
+	ExemptCategories   []string  `json:"exempt_categories"`
+	GraceDays          int       `json:"grace_days" binding:"min=0"`
+	AutoLockCard       bool      `json:"auto_lock_card"`
+	MaxOverdueReceipts int       `json:"max_overdue_receipts" binding:"min=0"`
+}
+
+// UpdateReceiptPolicyRequest DTO to update receipt policy
+type UpdateReceiptPolicyRequest struct {
+	AmountThreshold    *float64  `json:"amount_threshold,omitempty"`
+	ExemptCategories   *[]string `json:"exempt_categories,omitempty"`
+	GraceDays          *int      `json:"grace_days,omitempty"`
+	AutoLockCard       *bool     `json:"auto_lock_card,omitempty"`
+	MaxOverdueReceipts *int      `json:"max_overdue_receipts,omitempty"`
+}
+
+// GetReceiptPolicyResponse DTO
+type GetReceiptPolicyResponse struct {
+	ID                 uuid.UUID `json:"id"`
+	Company            uuid.UUID `json:"company"`
+	AmountThreshold    float64   `json:"amount_threshold"`
+	ExemptCategories   []string  `json:"exempt_categories"`
+	GraceDays          int       `json:"grace_days"`
+	AutoLockCard       bool      `json:"auto_lock_card"`
+	MaxOverdueReceipts int       `json:"max_overdue_receipts"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// GetReceiptPolicyDataResponse returns receipt policy response
+type GetReceiptPolicyDataResponse struct {
+	Success bool                     `json:"success"`
+	Message string                   `json:"message"`
+	Data    GetReceiptPolicyResponse `json:"data"`
+}
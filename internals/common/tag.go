@@ -0,0 +1,46 @@
+package common
+
+import (
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+// CreateTagRequest DTO to create a company tag
+type CreateTagRequest struct {
+	Company uuid.UUID `json:"company" binding:"required"`
+	Name    string    `json:"name" binding:"required"`
+	Color   string    `json:"color"`
+}
+
+// UpdateTagRequest DTO to update a tag
+type UpdateTagRequest struct {
+	Name  *string `json:"name,omitempty"`
+	Color *string `json:"color,omitempty"`
+}
+
+// TagTransactionRequest DTO to set the tags of a transaction, an empty list removes all tags
+type TagTransactionRequest struct {
+	Tags []uuid.UUID `json:"tags"`
+}
+
+// CreateTransactionCommentRequest DTO to comment on a transaction
+type CreateTransactionCommentRequest struct {
+	Body string `json:"body" binding:"required"`
+}
+
+// GetTagResponse DTO
+type GetTagResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Company   uuid.UUID `json:"company"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// GetTagDataResponse returns tag response
+type GetTagDataResponse struct {
+	Success bool           `json:"success"`
+	Message string         `json:"message"`
+	Data    GetTagResponse `json:"data"`
+}
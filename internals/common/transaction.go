@@ -49,6 +49,30 @@ type UpdateTransactionRequest struct {
 	ExpenseCategory *string `json:"expenseCategory,omitempty"`
 }
 
+// VoidTransactionRequest DTO to void a transaction, transactions are reversed instead of deleted
+type VoidTransactionRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// TransactionSplitRequest DTO a line of a split transaction
+type TransactionSplitRequest struct {
+	Amount     float64 `json:"amount" binding:"required,gt=0"`
+	Category   string  `json:"category" binding:"required"`
+	Memo       string  `json:"memo"`
+	CostCenter string  `json:"cost_center"`
+}
+
+// SplitTransactionRequest DTO to split a transaction, the lines must sum to the transaction amount and an empty list removes the split
+type SplitTransactionRequest struct {
+	Splits []TransactionSplitRequest `json:"splits" binding:"dive"`
+}
+
+// ExportTransactionsRequest DTO query of the accounting export, the period defaults to the last 30 days
+type ExportTransactionsRequest struct {
+	From time.Time `form:"from" time_format:"2006-01-02"`
+	To   time.Time `form:"to" time_format:"2006-01-02"` // exclusive
+}
+
 type GetTransactionResponse struct {
 	ID                uuid.UUID          `json:"id"`
 	Company           uuid.UUID          `json:"company,omitempty"`
@@ -58,6 +82,13 @@ type GetTransactionResponse struct {
 	PartnerCustomerID string             `json:"partner_customer_id"`
 	Debit             float64            `json:"debit"`
 	Credit            float64            `json:"credit"`
+	Currency          string             `json:"currency"`
+	MerchantAmount    float64            `json:"merchant_amount"`
+	MerchantCurrency  string             `json:"merchant_currency"`
+	MerchantName      string             `json:"merchant_name"`
+	MerchantCategory  string             `json:"merchant_category"`
+	MerchantCountry   string             `json:"merchant_country"`
+	Vat               float64            `json:"vat"`
 	Note              string             `json:"note"`
 	ReferenceID       string             `json:"reference_id"`
 	PartnerFee        float64            `json:"partner_fee"`
@@ -69,6 +100,9 @@ type GetTransactionResponse struct {
 	Type              TransactionType    `json:"type"` // withdrawal, cashback, interest, shipping, cards, fee, refund
 	CardType          CardType           `json:"card_type"`
 	ParentID          string             `json:"parent_id"` //Parent id for the refund
+	AuthorizationID   string             `json:"authorization_id"`
+	AuthorizedAmount  float64            `json:"authorized_amount"`
+	RefundedAmount    float64            `json:"refunded_amount,omitempty"`
 	Lock              bool               `json:"lock"`
 	Receipt           string             `json:"receipt"`
 	ExpenseCategory   string             `json:"expense_category,omitempty"`
@@ -173,6 +207,7 @@ type CreateTransactionRequest struct {
 				UpdatedAt     time.Time `json:"updatedAt"`
 				V             int       `json:"__v"`
 			} `json:"card"`
+			Authorization       string        `json:"authorization"` // original authorization of a refund or reversal
 			Amount              int           `json:"amount"`
 			Fee                 int           `json:"fee"`
 			Vat                 int           `json:"vat"`
@@ -12,6 +12,12 @@ type UpdateWalletRequest struct {
 	CurrentSpending *int64  `json:"current_spending,omitempty"`
 	Payment         *int64  `json:"payment,omitempty"`
 	Entry           *string `json:"type,omitempty"`
+	Overdraw        bool    `json:"-"` // set by the services for charges that already happened, never by a request
+}
+
+// CloseWalletRequest DTO to close a wallet, wallets are closed instead of deleted
+type CloseWalletRequest struct {
+	Reason string `json:"reason" binding:"required"`
 }
 
 // GetWalletResponse DTO
@@ -0,0 +1,63 @@
+package common
+
+import (
+	"core_business/internals/core/domain"
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+// CreateWebhookRequest DTO to subscribe a company to events, a secret is generated when none is given
+type CreateWebhookRequest struct {
+	Company    uuid.UUID `json:"company" binding:"required"`
+	URL        string    `json:"url" binding:"required,url"`
+	EventTypes []string  `json:"event_types" binding:"required,min=1"`
+	Secret     string    `json:"secret"`
+}
+
+// UpdateWebhookRequest DTO to update a webhook subscription
+type UpdateWebhookRequest struct {
+	URL        *string   `json:"url,omitempty" binding:"omitempty,url"`
+	EventTypes *[]string `json:"event_types,omitempty"`
+	Secret     *string   `json:"secret,omitempty"`
+	Active     *bool     `json:"active,omitempty"`
+}
+
+// WebhookEvent payload posted to subscribers
+type WebhookEvent struct {
+	ID        uuid.UUID   `json:"id"`
+	Type      string      `json:"type"`
+	Company   uuid.UUID   `json:"company"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// GetWebhookResponse DTO
+type GetWebhookResponse struct {
+	ID         uuid.UUID `json:"id"`
+	Company    uuid.UUID `json:"company"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// GetWebhookDataResponse returns webhook response
+type GetWebhookDataResponse struct {
+	Success bool               `json:"success"`
+	Message string             `json:"message"`
+	Data    GetWebhookResponse `json:"data"`
+}
+
+// CreateWebhookResponse DTO of a new subscription, the only response that carries its secret
+type CreateWebhookResponse struct {
+	*domain.WebhookSubscription
+	Secret string `json:"secret"`
+}
+
+// CreateWebhookDataResponse returns the new webhook with its secret
+type CreateWebhookDataResponse struct {
+	Success bool                  `json:"success"`
+	Message string                `json:"message"`
+	Data    CreateWebhookResponse `json:"data"`
+}
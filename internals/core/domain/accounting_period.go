@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"errors"
+	"github.com/satori/go.uuid"
+	"time"
+)
+
+// PeriodStatus open or closed
+type PeriodStatus string
+
+// PeriodAction close or reopen, recorded in the period audit trail
+type PeriodAction string
+
+const (
+	PeriodOpen   PeriodStatus = "OPEN"
+	PeriodClosed PeriodStatus = "CLOSED"
+
+	ClosePeriodAction  PeriodAction = "CLOSE"
+	ReopenPeriodAction PeriodAction = "REOPEN"
+
+	AdjustmentType TransactionType = "ADJUSTMENT" // correction posted against a transaction of a closed period
+)
+
+var (
+	// ErrPeriodClosed returned when changing a transaction of a closed accounting period
+	ErrPeriodClosed = errors.New("accounting period is closed, post an adjusting entry instead")
+	// ErrTransactionLocked returned when changing a locked transaction
+	ErrTransactionLocked = errors.New("transaction is locked")
+	// ErrReopenNotAuthorized returned when someone other than the company owner reopens a period
+	ErrReopenNotAuthorized = errors.New("only the company owner can reopen an accounting period")
+)
+
+// AccountingPeriod model a calendar month of a company that finance closes once its books are final
+type AccountingPeriod struct {
+	Base
+	Company    uuid.UUID     `json:"company" gorm:"not null;uniqueIndex:idx_company_period;column:company"`
+	Year       int           `json:"year" gorm:"not null;uniqueIndex:idx_company_period"`
+	Month      int           `json:"month" gorm:"not null;uniqueIndex:idx_company_period"`
+	Status     PeriodStatus  `json:"status" gorm:"index;not null;default:'CLOSED'"`
+	ClosedBy   string        `json:"closed_by"`
+	ClosedAt   *time.Time    `json:"closed_at"`
+	ReopenedBy string        `json:"reopened_by"`
+	ReopenedAt *time.Time    `json:"reopened_at"`
+	Audit      []PeriodAudit `json:"audit,omitempty" gorm:"ForeignKey:Period;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+// Start first instant of the period
+func (p *AccountingPeriod) Start() time.Time {
+	return time.Date(p.Year, time.Month(p.Month), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// End first instant after the period
+func (p *AccountingPeriod) End() time.Time {
+	return p.Start().AddDate(0, 1, 0)
+}
+
+// PeriodAudit model who closed or reopened an accounting period and why
+type PeriodAudit struct {
+	Base
+	Period  uuid.UUID    `json:"period" gorm:"not null;index;column:period"`
+	Company uuid.UUID    `json:"company" gorm:"not null;index;column:company"`
+	Action  PeriodAction `json:"action" gorm:"not null"`
+	Actor   string       `json:"actor" gorm:"not null"`
+	Reason  string       `json:"reason"`
+}
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"github.com/satori/go.uuid"
+)
+
+// AmountChangeType authorization, incremental authorization or capture
+type AmountChangeType string
+
+const (
+	AuthorizationChange AmountChangeType = "AUTHORIZATION"
+	IncrementalChange   AmountChangeType = "INCREMENTAL"
+	CaptureChange       AmountChangeType = "CAPTURE"
+)
+
+// AmountChange model keeps the history of amount changes on a card transaction
+type AmountChange struct {
+	Base
+	Transaction    uuid.UUID        `json:"transaction" gorm:"not null;index;column:transaction_id"`
+	Type           AmountChangeType `json:"type" gorm:"not null"`
+	PreviousAmount float64          `json:"previous_amount"`
+	Amount         float64          `json:"amount"`
+	Difference     float64          `json:"difference"` // positive was debited, negative was credited back
+	ReferenceID    string           `json:"reference_id" gorm:"index"`
+}
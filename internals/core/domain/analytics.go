@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"time"
+)
+
+// SpendDimension what card spend is grouped by in analytics
+type SpendDimension string
+
+const (
+	CategoryDimension   SpendDimension = "category"
+	CardDimension       SpendDimension = "card"
+	CardholderDimension SpendDimension = "cardholder"
+	MerchantDimension   SpendDimension = "merchant"
+	ChannelDimension    SpendDimension = "channel"
+	MonthDimension      SpendDimension = "month"
+)
+
+// SpendPeriod the period being reported on, compared with the period of the same length right before it
+type SpendPeriod struct {
+	From         time.Time `json:"from"`
+	To           time.Time `json:"to"`
+	PreviousFrom time.Time `json:"previous_from"`
+}
+
+// NewSpendPeriod creates the period from, to and works out the previous period
+func NewSpendPeriod(from time.Time, to time.Time) SpendPeriod {
+	return SpendPeriod{
+		From:         from,
+		To:           to,
+		PreviousFrom: from.Add(-to.Sub(from)),
+	}
+}
+
+// SpendTotal card spend of one group in the period and the previous period
+type SpendTotal struct {
+	Key            string  `json:"key" gorm:"column:group_key"`
+	Amount         float64 `json:"amount"`
+	Count          int64   `json:"count"`
+	PreviousAmount float64 `json:"previous_amount"`
+	PreviousCount  int64   `json:"previous_count"`
+	Change         float64 `json:"change" gorm:"-"` // percentage change from the previous period, 0 when there was no previous spend
+}
+
+// SpendReport top spend groups of a company with the period over period comparison
+type SpendReport struct {
+	Dimension SpendDimension `json:"dimension"`
+	Period    SpendPeriod    `json:"period"`
+	Total     SpendTotal     `json:"total"`
+	Rows      []SpendTotal   `json:"rows"`
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"github.com/satori/go.uuid"
+	"time"
 )
 
 type Channels struct {
@@ -48,4 +49,14 @@ type Card struct {
 	MaskedPan         string           `json:"maskedPan" gorm:"index;not null"`
 	ExpiryMonth       string           `json:"expiryMonth" gorm:"index;not null"`
 	ExpiryYear        string           `json:"expiryYear" gorm:"index;not null"`
+	ExpiryNotifiedAt  *time.Time       `json:"expiry_notified_at"`
+	Renews            *uuid.UUID       `json:"renews" gorm:"column:renews"`               // card this card replaced on expiry
+	RenewedBy         *uuid.UUID       `json:"renewed_by" gorm:"index;column:renewed_by"` // card that replaced this card on expiry
+	Mode              CardMode         `json:"mode" gorm:"not null;default:'general'"`
+	LockedMerchantID  string           `json:"locked_merchant_id"` // merchant a merchant locked card is locked to, set on its first approval
+	LockedMerchant    string           `json:"locked_merchant"`
+	UsedBy            string           `json:"used_by"`                           // authorization a single use card was claimed by, set on its first approval
+	Policy            *uuid.UUID       `json:"policy" gorm:"index;column:policy"` // card policy the spending controls come from
+	ValidUntil        *time.Time       `json:"valid_until" gorm:"index"`          // end of the validity period of the policy
+	Schedule          *CardSchedule    `json:"schedule" gorm:"serializer:json"`   // when the card can be used, any time when nil
 }
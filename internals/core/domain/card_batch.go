@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"github.com/satori/go.uuid"
+	"strings"
+	"time"
+)
+
+// CardBatchStatus pending, processing, completed
+type CardBatchStatus string
+
+// CardBatchRowStatus pending, issuing, issued, failed
+type CardBatchRowStatus string
+
+const (
+	BatchPending    CardBatchStatus = "PENDING"
+	BatchProcessing CardBatchStatus = "PROCESSING"
+	BatchCompleted  CardBatchStatus = "COMPLETED" // every row was issued or failed, rows keep their own outcome
+
+	RowPending CardBatchRowStatus = "PENDING"
+	RowIssuing CardBatchRowStatus = "ISSUING" // claimed for issuance, a claim older than CardBatchClaimTTL is taken over
+	RowIssued  CardBatchRowStatus = "ISSUED"
+	RowFailed  CardBatchRowStatus = "FAILED"
+)
+
+// CardBatchClaimTTL how long a claimed row is left to its worker, well past the time an issuer takes to create a card,
+// after that the worker is taken to have died and another worker can claim the row
+const CardBatchClaimTTL = 15 * time.Minute
+
+// ErrCardBatchRowClaimed returned when a row is claimed for issuance while another worker holds its claim
+var ErrCardBatchRowClaimed = errors.New("card batch row was already claimed")
+
+// DefaultSpendingTemplate template of rows that do not name one
+const DefaultSpendingTemplate = "standard"
+
+// SpendingTemplates spending controls a bulk issued card starts with, rows pick one by name
+var SpendingTemplates = map[string]SpendingControls{
+	"standard": {
+		Channels:       Channels{Pos: true, Web: true},
+		SpendingLimits: SpendingLimits{Amount: 100000, Interval: "monthly"},
+	},
+	"online": {
+		Channels:       Channels{Web: true},
+		SpendingLimits: SpendingLimits{Amount: 50000, Interval: "monthly"},
+	},
+	"travel": {
+		Channels:       Channels{Atm: true, Pos: true, Web: true},
+		SpendingLimits: SpendingLimits{Amount: 500000, Interval: "monthly"},
+	},
+}
+
+// CardBatch model a bulk issuance of cards from a CSV of employees
+type CardBatch struct {
+	Base
+	Company  uuid.UUID       `json:"company" gorm:"not null;index;column:company"`
+	Wallet   uuid.UUID       `json:"wallet" gorm:"not null;column:wallet"`
+	Status   CardBatchStatus `json:"status" gorm:"index;not null;default:'PENDING'"`
+	Total    int             `json:"total"`
+	Issued   int             `json:"issued"`
+	Failed   int             `json:"failed"`
+	TotalFee int64           `json:"total_fee"` // card creation fees of every row in kobo
+	Rows     []CardBatchRow  `json:"rows,omitempty" gorm:"ForeignKey:Batch;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+// CardBatchRow model a row of a card batch and its outcome
+type CardBatchRow struct {
+	Base
+	Batch     uuid.UUID          `json:"batch" gorm:"not null;index;column:batch"`
+	Line      int                `json:"line" gorm:"not null"`
+	FirstName string             `json:"first_name"`
+	LastName  string             `json:"last_name"`
+	Email     string             `json:"email"`
+	Phone     string             `json:"phone"`
+	Type      string             `json:"type"`
+	Brand     string             `json:"brand"`
+	Template  string             `json:"template"`
+	Status    CardBatchRowStatus `json:"status" gorm:"index;not null;default:'PENDING'"`
+	Card      *uuid.UUID         `json:"card" gorm:"column:card"`
+	Error     string             `json:"error"`
+}
+
+// CardBatchRowError why a row of the CSV was rejected
+type CardBatchRowError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// CardBatchValidationError returned when rows of the CSV are invalid, no card of the batch is issued
+type CardBatchValidationError struct {
+	Rows []CardBatchRowError
+}
+
+func (e *CardBatchValidationError) Error() string {
+	lines := make([]string, 0, len(e.Rows))
+	for _, row := range e.Rows {
+		lines = append(lines, fmt.Sprintf("line %v: %v", row.Line, row.Error))
+	}
+	return "invalid card batch: " + strings.Join(lines, "; ")
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"errors"
+	"github.com/satori/go.uuid"
+	"time"
+)
+
+// FulfillmentStatus ordered, printed, dispatched, delivered, returned
+type FulfillmentStatus string
+
+const (
+	FulfillmentOrdered    FulfillmentStatus = "ORDERED"
+	FulfillmentPrinted    FulfillmentStatus = "PRINTED"
+	FulfillmentDispatched FulfillmentStatus = "DISPATCHED" // handed to the courier
+	FulfillmentDelivered  FulfillmentStatus = "DELIVERED"
+	FulfillmentReturned   FulfillmentStatus = "RETURNED" // the courier could not deliver, dispatched again once the address is fixed
+)
+
+// ErrInvalidFulfillmentTransition returned when a fulfillment is moved to a status its current status can not reach
+var ErrInvalidFulfillmentTransition = errors.New("invalid fulfillment status transition")
+
+// ErrCardNotDelivered returned when a physical card is activated before its delivery is confirmed
+var ErrCardNotDelivered = errors.New("physical card can only be activated once its delivery is confirmed")
+
+// ErrShippingAddressLocked returned when the shipping address of a card already with the courier is changed
+var ErrShippingAddressLocked = errors.New("shipping address can not change while the card is with the courier or delivered")
+
+// ErrTrackingReferenceRequired returned when a card is dispatched without the courier tracking reference
+var ErrTrackingReferenceRequired = errors.New("courier and tracking reference are required to dispatch a card")
+
+// FulfillmentTransitions allowed moves of the fulfillment state machine, delivered is terminal
+var FulfillmentTransitions = map[FulfillmentStatus][]FulfillmentStatus{
+	FulfillmentOrdered:    {FulfillmentPrinted},
+	FulfillmentPrinted:    {FulfillmentDispatched},
+	FulfillmentDispatched: {FulfillmentDelivered, FulfillmentReturned},
+	FulfillmentReturned:   {FulfillmentDispatched},
+}
+
+// CanTransitionFulfillment reports whether a fulfillment can move from one status to another
+func CanTransitionFulfillment(from, to FulfillmentStatus) bool {
+	for _, status := range FulfillmentTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ShippingAddress where a physical card is delivered, kept on the card and distinct from the company address
+type ShippingAddress struct {
+	Recipient  string `json:"recipient"`
+	Phone      string `json:"phone"`
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2"`
+	City       string `json:"city"`
+	State      string `json:"state"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
+}
+
+// CardFulfillment model the printing and delivery of a physical card
+type CardFulfillment struct {
+	Base
+	Card              uuid.UUID              `json:"card" gorm:"not null;uniqueIndex;column:card"`
+	Company           uuid.UUID              `json:"company" gorm:"not null;index;column:company"`
+	Status            FulfillmentStatus      `json:"status" gorm:"index;not null;default:'ORDERED'"`
+	ShippingAddress   ShippingAddress        `json:"shipping_address" gorm:"embedded;embeddedPrefix:shipping_"`
+	Courier           string                 `json:"courier"`
+	TrackingReference string                 `json:"tracking_reference" gorm:"index"`
+	DispatchedAt      *time.Time             `json:"dispatched_at"`
+	DeliveredAt       *time.Time             `json:"delivered_at"`
+	Events            []CardFulfillmentEvent `json:"events,omitempty" gorm:"ForeignKey:Fulfillment;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+// CardFulfillmentEvent model a move of a fulfillment with who made it
+type CardFulfillmentEvent struct {
+	Base
+	Fulfillment uuid.UUID         `json:"fulfillment" gorm:"not null;index;column:fulfillment"`
+	From        FulfillmentStatus `json:"from"`
+	To          FulfillmentStatus `json:"to" gorm:"not null"`
+	Actor       string            `json:"actor" gorm:"not null"`
+	Note        string            `json:"note"`
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"errors"
+)
+
+// ErrIssuerUnavailable returned when the card issuer could not be reached or failed on its side, the call can be retried
+var ErrIssuerUnavailable = errors.New("card issuer unavailable")
+
+// ErrUnknownIssuer returned when a company or a card names an issuer that is not configured
+var ErrUnknownIssuer = errors.New("unknown card issuer")
+
+// IssuerAddress billing address of a customer at the issuer
+type IssuerAddress struct {
+	Line1      string
+	City       string
+	State      string
+	PostalCode string
+	Country    string
+}
+
+// IssuerCustomer a cardholder as the issuer knows it, PartnerID is set by the issuer
+type IssuerCustomer struct {
+	PartnerID   string
+	CompanyName string
+	FirstName   string
+	LastName    string
+	Phone       string
+	Email       string
+	Status      string
+	Address     IssuerAddress
+}
+
+// IssueCardRequest what is sent to the issuer to issue a card, Number is the PAN of a physical card
+type IssueCardRequest struct {
+	PartnerCustomerID string
+	Type              string
+	Brand             string
+	Currency          string
+	Status            string
+	Number            string
+	SpendingControls  SpendingControls
+}
+
+// IssuedCard a card as the issuer holds it
+type IssuedCard struct {
+	PartnerCardID    string
+	Type             string
+	Brand            string
+	Currency         string
+	Status           string
+	Business         string
+	Account          string
+	MaskedPan        string
+	ExpiryMonth      string
+	ExpiryYear       string
+	SpendingControls SpendingControls
+}
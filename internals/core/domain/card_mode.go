@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+// CardMode what a card can be used for, general purpose cards approve any merchant
+type CardMode string
+
+const (
+	GeneralCard        CardMode = "general"
+	SingleUseCard      CardMode = "single_use"      // canceled once its first transaction settles
+	MerchantLockedCard CardMode = "merchant_locked" // only the merchant of its first transaction is approved afterwards
+)
+
+// ErrInvalidCardMode returned when a card is created with an unknown mode or a mode its type does not support
+var ErrInvalidCardMode = errors.New("invalid card mode, single use and merchant locked cards must be virtual")
+
+// ErrSingleUseCardUsed returned when a single use card that was already used is authorized again
+var ErrSingleUseCardUsed = errors.New("single use card was already used")
+
+// ErrMerchantNotAllowed returned when a merchant locked card is used at a merchant it is not locked to
+var ErrMerchantNotAllowed = errors.New("card is locked to another merchant")
+
+// ParseCardMode reads a mode from a request, general when it is not set
+func ParseCardMode(mode string) (CardMode, error) {
+	m := CardMode(strings.ToLower(strings.TrimSpace(mode)))
+	switch m {
+	case "":
+		return GeneralCard, nil
+	case GeneralCard, SingleUseCard, MerchantLockedCard:
+		return m, nil
+	}
+	return "", ErrInvalidCardMode
+}
+
+// MerchantKey identifies the merchant of an authorization, the partner merchant id or the name when the partner sends none
+func MerchantKey(merchantID string, name string) string {
+	if id := strings.TrimSpace(merchantID); id != "" {
+		return id
+	}
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"errors"
+	"github.com/satori/go.uuid"
+	"time"
+)
+
+// ErrCardPolicyExists returned when a company already has a card policy with the name
+var ErrCardPolicyExists = errors.New("company already has a card policy with this name")
+
+// ErrCardPolicyOfAnotherCompany returned when a card is issued on a policy of another company
+var ErrCardPolicyOfAnotherCompany = errors.New("card policy belongs to another company")
+
+// ErrUnknownCardPolicyPreset returned when a card policy is created from a preset that does not exist
+var ErrUnknownCardPolicyPreset = errors.New("unknown card policy preset")
+
+// CardPolicyPresets card policies a company starts from, the request can change any of their fields, categories are MCCs
+var CardPolicyPresets = map[string]CardPolicy{
+	"sales-travel": {
+		Name:        "Sales travel card",
+		Description: "flights, hotels and transport on sales trips",
+		SpendingControls: SpendingControls{
+			Channels:          Channels{Atm: true, Pos: true, Web: true},
+			AllowedCategories: []string{"4511", "7011", "7512", "4121", "5812"}, // airlines, hotels, car rental, taxis, restaurants
+			SpendingLimits:    SpendingLimits{Amount: 500000, Interval: "monthly"},
+		},
+		ValidityDays: 90,
+	},
+	"marketing-saas": {
+		Name:        "Marketing SaaS card",
+		Description: "software subscriptions and online advertising",
+		SpendingControls: SpendingControls{
+			Channels:          Channels{Web: true},
+			AllowedCategories: []string{"5734", "7311", "5818"}, // software, advertising, digital goods
+			SpendingLimits:    SpendingLimits{Amount: 200000, Interval: "monthly"},
+		},
+	},
+	"fleet-fuel": {
+		Name:        "Fleet fuel card",
+		Description: "fuel for drivers during working hours",
+		SpendingControls: SpendingControls{
+			Channels:          Channels{Pos: true},
+			AllowedCategories: []string{"5541", "5542"}, // service stations, fuel dispensers
+			SpendingLimits:    SpendingLimits{Amount: 50000, Interval: "weekly"},
+		},
+		Schedule: &CardSchedule{
+			TimeZone: DefaultScheduleTimeZone,
+			Windows:  []ScheduleWindow{{Days: []string{"mon", "tue", "wed", "thu", "fri"}, Start: "06:00", End: "20:00"}},
+		},
+	},
+}
+
+// CardPolicy model named spending controls of a company that cards are issued on
+type CardPolicy struct {
+	Base
+	Company          uuid.UUID        `json:"company" gorm:"not null;index;column:company"`
+	Name             string           `json:"name" gorm:"not null"`
+	Description      string           `json:"description"`
+	SpendingControls SpendingControls `json:"spending_controls" gorm:"serializer:json"`
+	ValidityDays     int              `json:"validity_days" gorm:"not null;default:0"` // days a card on the policy can be used, no limit when 0
+	Schedule         *CardSchedule    `json:"schedule" gorm:"serializer:json"`         // when cards on the policy can be used
+}
+
+// ValidUntil when a card issued on the policy at the time stops being usable, nil when the policy has no validity period
+func (p *CardPolicy) ValidUntil(issued time.Time) *time.Time {
+	if p.ValidityDays <= 0 {
+		return nil
+	}
+
+	until := issued.AddDate(0, 0, p.ValidityDays)
+	return &until
+}
+
+// CardPolicyPropagation outcome of applying a changed policy to the cards issued on it
+type CardPolicyPropagation struct {
+	Cards   int         `json:"cards"`
+	Updated int         `json:"updated"`
+	Failed  []uuid.UUID `json:"failed"`
+}
+
+// CardPolicyUpdate a changed card policy and, when asked for, how its cards were changed
+type CardPolicyUpdate struct {
+	Policy      *CardPolicy            `json:"policy"`
+	Propagation *CardPolicyPropagation `json:"propagation,omitempty"`
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"fmt"
+	"github.com/satori/go.uuid"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultCardExpiryNoticeDays how many days before expiry a company hears about a card and it is renewed
+const DefaultCardExpiryNoticeDays = 30
+
+// RecurringMerchantMonths how far back charges are looked at to find the merchants billing a card every month
+const RecurringMerchantMonths = 6
+
+// CardRecurringMerchant model a merchant that billed a card in more than one month, kept on the card that replaced it
+// so the company knows which subscriptions to move
+type CardRecurringMerchant struct {
+	Base
+	Card             uuid.UUID `json:"card" gorm:"not null;index;column:card"`
+	PreviousCard     uuid.UUID `json:"previous_card" gorm:"not null;column:previous_card"`
+	MerchantName     string    `json:"merchant_name" gorm:"not null"`
+	MerchantCategory string    `json:"merchant_category"`
+	MerchantCountry  string    `json:"merchant_country"`
+	Months           int       `json:"months"` // months the merchant billed the previous card in
+	LastAmount       float64   `json:"last_amount"`
+	LastChargedAt    time.Time `json:"last_charged_at"`
+}
+
+// CardRenewalRun outcome of a run of the expiry job
+type CardRenewalRun struct {
+	Checked  int `json:"checked"`
+	Notified int `json:"notified"`
+	Renewed  int `json:"renewed"`
+	Expired  int `json:"expired"`
+	Failed   int `json:"failed"`
+}
+
+// CardRenewal payload of the card renewed webhook
+type CardRenewal struct {
+	PreviousCard       uuid.UUID               `json:"previous_card"`
+	Card               *Card                   `json:"card"`
+	RecurringMerchants []CardRecurringMerchant `json:"recurring_merchants"`
+}
+
+// ExpiresAt the first instant the card can no longer be used, cards are valid through their expiry month
+func (c *Card) ExpiresAt() (time.Time, error) {
+	month, err := strconv.Atoi(c.ExpiryMonth)
+	if err != nil || month < 1 || month > 12 {
+		return time.Time{}, fmt.Errorf("invalid expiry month %q", c.ExpiryMonth)
+	}
+
+	year, err := strconv.Atoi(NormalizeExpiryYear(c.ExpiryYear))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid expiry year %q", c.ExpiryYear)
+	}
+	return time.Date(year, time.Month(month)+1, 1, 0, 0, 0, 0, time.UTC), nil
+}
+
+// NormalizeExpiryYear writes a two digit expiry year with its century, issuers send either form
+func NormalizeExpiryYear(year string) string {
+	year = strings.TrimSpace(year)
+	if len(year) == 2 {
+		if short, err := strconv.Atoi(year); err == nil {
+			return strconv.Itoa(2000 + short)
+		}
+	}
+	return year
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"errors"
+	"github.com/satori/go.uuid"
+	"time"
+)
+
+// CardRevealTokenTTL how long a reveal token can be used after it is issued
+const CardRevealTokenTTL = time.Minute
+
+// ErrInvalidRevealToken returned when a reveal token is unknown, expired or already used
+var ErrInvalidRevealToken = errors.New("reveal token is invalid, expired or already used")
+
+// ErrCardNotRevealable returned when the details of a card that cannot be used are asked for
+var ErrCardNotRevealable = errors.New("only the details of an active card can be revealed")
+
+// CardSecrets the full details of a card, read from the issuer on each reveal and never stored or logged
+type CardSecrets struct {
+	Number      string `json:"number"`
+	CVV         string `json:"cvv"`
+	ExpiryMonth string `json:"expiry_month"`
+	ExpiryYear  string `json:"expiry_year"`
+}
+
+// RevealToken a reveal token as handed to the client, the token itself is never stored
+type RevealToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// CardRevealToken model a single use token to reveal a card, only the hash of the token is stored
+type CardRevealToken struct {
+	Base
+	Card      uuid.UUID  `json:"card" gorm:"not null;index;column:card"`
+	TokenHash string     `json:"-" gorm:"not null;unique"`
+	Actor     string     `json:"actor" gorm:"not null"`
+	IP        string     `json:"ip"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`
+}
+
+// CardReveal model a reveal of the details of a card, who asked for it and from where
+type CardReveal struct {
+	Base
+	Card    uuid.UUID `json:"card" gorm:"not null;index;column:card"`
+	Company uuid.UUID `json:"company" gorm:"not null;index;column:company"`
+	Token   uuid.UUID `json:"token" gorm:"not null;column:token"`
+	Actor   string    `json:"actor" gorm:"not null"`
+	IP      string    `json:"ip"`
+}
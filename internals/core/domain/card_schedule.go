@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // schedules name their time zone, the servers may not have the zoneinfo database
+)
+
+// DefaultScheduleTimeZone time zone of schedules that do not name one
+const DefaultScheduleTimeZone = "Africa/Lagos"
+
+// ErrOutsideSchedule returned when a card is authorized outside the times its schedule allows
+var ErrOutsideSchedule = errors.New("card used outside its schedule")
+
+// ErrInvalidSchedule returned when a schedule has an unknown day, time or time zone
+var ErrInvalidSchedule = errors.New("invalid card schedule")
+
+var scheduleDays = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ScheduleWindow days and the time of day a card can be used on them, a window ending before it starts runs
+// past midnight into the next day
+type ScheduleWindow struct {
+	Days  []string `json:"days"`  // monday to sunday or mon to sun, every day when empty
+	Start string   `json:"start"` // HH:MM
+	End   string   `json:"end"`   // HH:MM, 24:00 for the end of the day
+}
+
+// CardSchedule when a card can be used, in the local time of its time zone
+type CardSchedule struct {
+	TimeZone string           `json:"time_zone"`
+	Windows  []ScheduleWindow `json:"windows"`
+}
+
+// Validate checks the days, times and time zone of the schedule
+func (s *CardSchedule) Validate() error {
+	if len(s.Windows) == 0 {
+		return fmt.Errorf("%w: at least one window is required", ErrInvalidSchedule)
+	}
+
+	if _, err := s.location(); err != nil {
+		return fmt.Errorf("%w: unknown time zone %v", ErrInvalidSchedule, s.TimeZone)
+	}
+
+	for _, window := range s.Windows {
+		for _, day := range window.Days {
+			if _, ok := scheduleDays[strings.ToLower(strings.TrimSpace(day))]; !ok {
+				return fmt.Errorf("%w: unknown day %v", ErrInvalidSchedule, day)
+			}
+		}
+
+		start, err := parseClock(window.Start)
+		if err != nil || start == 24*60 {
+			return fmt.Errorf("%w: invalid start %v", ErrInvalidSchedule, window.Start)
+		}
+
+		end, err := parseClock(window.End)
+		if err != nil || end == start {
+			return fmt.Errorf("%w: invalid end %v", ErrInvalidSchedule, window.End)
+		}
+	}
+	return nil
+}
+
+// Allows reports whether the card can be used at the time
+func (s *CardSchedule) Allows(at time.Time) (bool, error) {
+	location, err := s.location()
+	if err != nil {
+		return false, err
+	}
+
+	local := at.In(location)
+	minute := local.Hour()*60 + local.Minute()
+	yesterday := (local.Weekday() + 6) % 7
+
+	for _, window := range s.Windows {
+		start, err := parseClock(window.Start)
+		if err != nil {
+			return false, err
+		}
+
+		end, err := parseClock(window.End)
+		if err != nil {
+			return false, err
+		}
+
+		if start < end {
+			if window.on(local.Weekday()) && minute >= start && minute < end {
+				return true, nil
+			}
+			continue
+		}
+
+		// overnight, the evening of the day and the morning after it
+		if window.on(local.Weekday()) && minute >= start {
+			return true, nil
+		}
+		if window.on(yesterday) && minute < end {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// String the windows and time zone of the schedule, e.g. mon,tue,wed,thu,fri 08:00-20:00 Africa/Lagos
+func (s *CardSchedule) String() string {
+	windows := make([]string, 0, len(s.Windows))
+	for _, window := range s.Windows {
+		days := "every day"
+		if len(window.Days) > 0 {
+			days = strings.ToLower(strings.Join(window.Days, ","))
+		}
+		windows = append(windows, fmt.Sprintf("%v %v-%v", days, window.Start, window.End))
+	}
+
+	timeZone := s.TimeZone
+	if timeZone == "" {
+		timeZone = DefaultScheduleTimeZone
+	}
+	return fmt.Sprintf("%v %v", strings.Join(windows, "; "), timeZone)
+}
+
+func (s *CardSchedule) location() (*time.Location, error) {
+	if s.TimeZone == "" {
+		return time.LoadLocation(DefaultScheduleTimeZone)
+	}
+	return time.LoadLocation(s.TimeZone)
+}
+
+func (w ScheduleWindow) on(day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+
+	for _, name := range w.Days {
+		if d, ok := scheduleDays[strings.ToLower(strings.TrimSpace(name))]; ok && d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// parseClock minutes since midnight of a HH:MM time
+func parseClock(value string) (int, error) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) != 2 || len(parts[0]) != 2 || len(parts[1]) != 2 {
+		return 0, fmt.Errorf("%w: time must be HH:MM", ErrInvalidSchedule)
+	}
+
+	hour, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("%w: time must be HH:MM", ErrInvalidSchedule)
+	}
+
+	minute, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, fmt.Errorf("%w: time must be HH:MM", ErrInvalidSchedule)
+	}
+
+	if hour < 0 || minute < 0 || minute > 59 || hour > 24 || (hour == 24 && minute != 0) {
+		return 0, fmt.Errorf("%w: time must be HH:MM", ErrInvalidSchedule)
+	}
+	return hour*60 + minute, nil
+}
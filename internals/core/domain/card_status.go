@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"errors"
+	"github.com/satori/go.uuid"
+	"strings"
+	"time"
+)
+
+// CardStatus state of a card in its lifecycle
+type CardStatus string
+
+const (
+	CardPending  CardStatus = "pending"
+	CardActive   CardStatus = "active"
+	CardInactive CardStatus = "inactive"
+	CardLocked   CardStatus = "locked"
+	CardLost     CardStatus = "lost"
+	CardStolen   CardStatus = "stolen"
+	CardExpired  CardStatus = "expired"
+	CardCanceled CardStatus = "canceled" // spelled like the card partner does
+)
+
+// ErrInvalidCardTransition returned when a card is moved to a status its current status can not reach
+var ErrInvalidCardTransition = errors.New("invalid card status transition")
+
+// CardTransitions allowed moves of the card state machine, canceled is terminal
+var CardTransitions = map[CardStatus][]CardStatus{
+	CardPending:  {CardActive, CardCanceled},
+	CardActive:   {CardInactive, CardLocked, CardLost, CardStolen, CardExpired, CardCanceled},
+	CardInactive: {CardActive, CardLocked, CardLost, CardStolen, CardExpired, CardCanceled},
+	CardLocked:   {CardActive, CardInactive, CardLost, CardStolen, CardExpired, CardCanceled},
+	CardLost:     {CardCanceled},
+	CardStolen:   {CardCanceled},
+	CardExpired:  {CardCanceled},
+}
+
+// ParseCardStatus normalizes a stored or requested status, cards saved before the state machine have no status and are active
+func ParseCardStatus(status string) CardStatus {
+	s := CardStatus(strings.ToLower(strings.TrimSpace(status)))
+	if s == "" {
+		return CardActive
+	}
+
+	if s == "cancelled" {
+		return CardCanceled
+	}
+	return s
+}
+
+// CanTransitionCard reports whether a card can move from one status to another
+func CanTransitionCard(from, to CardStatus) bool {
+	for _, status := range CardTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// PartnerStatus the status the card partner holds for a card in this status, locked cards are inactive there
+// and the partner only knows canceled for lost and stolen cards. Empty when the partner is not told.
+func (s CardStatus) PartnerStatus() CardStatus {
+	switch s {
+	case CardActive:
+		return CardActive
+	case CardInactive, CardLocked:
+		return CardInactive
+	case CardLost, CardStolen, CardCanceled:
+		return CardCanceled
+	}
+	return ""
+}
+
+// LockMismatch a card whose status at the issuer differs from ours and what the reconciliation did about it
+type LockMismatch struct {
+	Card       uuid.UUID  `json:"card"`
+	Local      CardStatus `json:"local"`
+	Issuer     CardStatus `json:"issuer"`
+	Resolution string     `json:"resolution"` // resent, canceled, alerted or failed
+}
+
+// LockReconciliation outcome of comparing card statuses with the issuer
+type LockReconciliation struct {
+	Checked    int            `json:"checked"`
+	Repaired   int            `json:"repaired"`
+	Failed     int            `json:"failed"`
+	Alerted    int            `json:"alerted"` // frozen at the issuer while active here, left for the company to review
+	Mismatches []LockMismatch `json:"mismatches"`
+}
+
+// Usable reports whether the card can authorize new purchases, a card past the validity period of its policy can not
+func (c *Card) Usable() bool {
+	if c.ValidUntil != nil && !time.Now().Before(*c.ValidUntil) {
+		return false
+	}
+	return !c.Lock && ParseCardStatus(c.Status) == CardActive
+}
+
+// CardStatusChange model a transition of a card with who made it and why
+type CardStatusChange struct {
+	Base
+	Card   uuid.UUID  `json:"card" gorm:"not null;index;column:card"`
+	From   CardStatus `json:"from"`
+	To     CardStatus `json:"to" gorm:"not null"`
+	Actor  string     `json:"actor" gorm:"not null"`
+	Reason string     `json:"reason"`
+}
@@ -13,6 +13,8 @@ type Company struct {
 	Website         string            `json:"website" gorm:"index"`
 	Type            string            `json:"type" gorm:"index"`
 	FundingSource   string            `json:"funding_source"`
+	CardIssuer      string            `json:"card_issuer"`
+	CardAutoRenewal bool              `json:"card_auto_renewal" gorm:"default:false"` // replace cards before they expire
 	NoOfEmployee    string            `json:"no_of_employee" gorm:"not null;default:0"`
 	Address         []Address         `json:"address,omitempty" gorm:"ForeignKey:Company;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 	BusinessHead    BusinessHead      `json:"business_head,omitempty" gorm:"ForeignKey:Company;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
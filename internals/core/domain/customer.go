@@ -1,15 +1,28 @@
 package domain
 
 import (
+	"errors"
 	"github.com/satori/go.uuid"
 )
 
-// Customer model
+// ErrCustomerExists returned when a cardholder with the same email is already in the company
+var ErrCustomerExists = errors.New("a cardholder with this email already exists in the company")
+
+// ErrCustomerOfAnotherCompany returned when a card is issued to a cardholder of another company
+var ErrCustomerOfAnotherCompany = errors.New("cardholder belongs to another company")
+
+// Customer model a cardholder, created once per employee of a company and reused for every card issued to them
 type Customer struct {
 	Base
 	Company            uuid.UUID     `json:"company,omitempty" gorm:"column:company"`
 	Wallet             uuid.UUID     `json:",omitempty"`
+	User               string        `json:"user" gorm:"index"` // company user the cardholder signs in as, if any
+	FirstName          string        `json:"first_name"`
+	LastName           string        `json:"last_name"`
+	Email              string        `json:"email" gorm:"index"`
+	Phone              string        `json:"phone"`
 	PartnerCustomerID  string        `json:"partner_customer_id"`
+	Partner            string        `json:"partner" gorm:"index;not null;default:'sudo'"`
 	Address            string        `json:"address" gorm:"index;not null"`
 	ApartmentUnitFloor int32         `json:"apartment_unit_floor"`
 	City               string        `json:"city" gorm:"not null"`
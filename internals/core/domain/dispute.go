@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"github.com/satori/go.uuid"
+	"time"
+)
+
+// DisputeStatus opened, submitted, won, lost, withdrawn
+type DisputeStatus string
+
+// DisputeReason reason code given for disputing a card transaction
+type DisputeReason string
+
+const (
+	DisputeOpened    DisputeStatus = "OPENED"
+	DisputeSubmitted DisputeStatus = "SUBMITTED" // submitted to the card partner
+	DisputeWon       DisputeStatus = "WON"
+	DisputeLost      DisputeStatus = "LOST"
+	DisputeWithdrawn DisputeStatus = "WITHDRAWN"
+
+	FraudulentReason            DisputeReason = "FRAUDULENT"
+	DuplicateReason             DisputeReason = "DUPLICATE"
+	IncorrectAmountReason       DisputeReason = "INCORRECT_AMOUNT"
+	ProductNotReceivedReason    DisputeReason = "PRODUCT_NOT_RECEIVED"
+	ProductUnacceptableReason   DisputeReason = "PRODUCT_UNACCEPTABLE"
+	SubscriptionCancelledReason DisputeReason = "SUBSCRIPTION_CANCELLED"
+	OtherReason                 DisputeReason = "OTHER"
+)
+
+// DisputeTransitions allowed moves of the dispute state machine
+var DisputeTransitions = map[DisputeStatus][]DisputeStatus{
+	DisputeOpened:    {DisputeSubmitted, DisputeWithdrawn},
+	DisputeSubmitted: {DisputeWon, DisputeLost, DisputeWithdrawn},
+}
+
+// DisputeReasons valid reason codes
+var DisputeReasons = []DisputeReason{
+	FraudulentReason, DuplicateReason, IncorrectAmountReason, ProductNotReceivedReason,
+	ProductUnacceptableReason, SubscriptionCancelledReason, OtherReason,
+}
+
+// Dispute model
+type Dispute struct {
+	Base
+	Company           uuid.UUID         `json:"company" gorm:"not null;index;column:company"`
+	Wallet            uuid.UUID         `json:"wallet" gorm:"not null;column:wallet"`
+	Card              uuid.UUID         `json:"card" gorm:"column:card"`
+	Transaction       uuid.UUID         `json:"transaction" gorm:"not null;index;column:transaction_id"`
+	Amount            float64           `json:"amount" gorm:"not null"`
+	Reason            DisputeReason     `json:"reason" gorm:"not null"`
+	Explanation       string            `json:"explanation"`
+	Status            DisputeStatus     `json:"status" gorm:"index;not null;default:'OPENED'"`
+	PartnerReference  string            `json:"partner_reference"`
+	ProvisionalCredit string            `json:"provisional_credit"` // transaction id of the provisional credit
+	ResolvedAt        *time.Time        `json:"resolved_at"`
+	Evidence          []DisputeEvidence `json:"evidence,omitempty" gorm:"ForeignKey:Dispute;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+// DisputeEvidence model
+type DisputeEvidence struct {
+	Base
+	Dispute     uuid.UUID `json:"dispute" gorm:"not null;index;column:dispute"`
+	URL         string    `json:"url" gorm:"not null"`
+	Description string    `json:"description"`
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"github.com/satori/go.uuid"
+	"time"
+)
+
+// FraudRuleType check run against card authorizations
+type FraudRuleType string
+
+// FraudAction what happens to the authorization when a rule matches
+type FraudAction string
+
+// FraudAlertStatus open, confirmed or dismissed
+type FraudAlertStatus string
+
+const (
+	VelocityRule            FraudRuleType = "VELOCITY"              // more than threshold transactions in the window
+	AtmBurstRule            FraudRuleType = "ATM_BURST"             // more than threshold ATM withdrawals in the window
+	ForeignMerchantRule     FraudRuleType = "FOREIGN_MERCHANT"      // merchant outside the home country
+	AmountAnomalyRule       FraudRuleType = "AMOUNT_ANOMALY"        // amount above multiplier times the card average
+	NewMerchantCategoryRule FraudRuleType = "NEW_MERCHANT_CATEGORY" // merchant category the card never spent in
+
+	AlertAction   FraudAction = "ALERT"
+	DeclineAction FraudAction = "DECLINE"
+	LockAction    FraudAction = "LOCK" // declines and locks the card
+
+	AlertOpen      FraudAlertStatus = "OPEN"
+	AlertConfirmed FraudAlertStatus = "CONFIRMED"
+	AlertDismissed FraudAlertStatus = "DISMISSED"
+)
+
+// FraudRuleTypes valid rule types
+var FraudRuleTypes = []FraudRuleType{VelocityRule, AtmBurstRule, ForeignMerchantRule, AmountAnomalyRule, NewMerchantCategoryRule}
+
+// FraudActions valid rule actions, in order of severity
+var FraudActions = []FraudAction{AlertAction, DeclineAction, LockAction}
+
+// FraudRule model a rule a company runs on every card authorization
+type FraudRule struct {
+	Base
+	Company       uuid.UUID     `json:"company" gorm:"not null;index;column:company"`
+	Type          FraudRuleType `json:"type" gorm:"not null"`
+	Action        FraudAction   `json:"action" gorm:"not null;default:'ALERT'"`
+	Threshold     int           `json:"threshold"`      // velocity and ATM burst, transactions allowed in the window
+	WindowMinutes int           `json:"window_minutes"` // velocity and ATM burst
+	Multiplier    float64       `json:"multiplier"`     // amount anomaly, times the card average amount
+	MinHistory    int           `json:"min_history"`    // amount anomaly and new merchant category, transactions needed before the rule runs
+	HomeCountry   string        `json:"home_country"`   // foreign merchant
+	Active        bool          `json:"active" gorm:"default:true"`
+}
+
+// DefaultFraudRules rules that run for companies that did not configure any, they only raise alerts
+func DefaultFraudRules(company uuid.UUID) []FraudRule {
+	return []FraudRule{
+		{Company: company, Type: VelocityRule, Action: AlertAction, Threshold: 5, WindowMinutes: 10, Active: true},
+		{Company: company, Type: AtmBurstRule, Action: AlertAction, Threshold: 3, WindowMinutes: 30, Active: true},
+		{Company: company, Type: ForeignMerchantRule, Action: AlertAction, HomeCountry: "NG", Active: true},
+		{Company: company, Type: AmountAnomalyRule, Action: AlertAction, Multiplier: 5, MinHistory: 5, Active: true},
+		{Company: company, Type: NewMerchantCategoryRule, Action: AlertAction, MinHistory: 10, Active: true},
+	}
+}
+
+// FraudAlert model raised when an authorization matches a fraud rule
+type FraudAlert struct {
+	Base
+	Company         uuid.UUID        `json:"company" gorm:"not null;index;column:company"`
+	Card            uuid.UUID        `json:"card" gorm:"index;column:card"`
+	Rule            FraudRuleType    `json:"rule" gorm:"not null"`
+	Action          FraudAction      `json:"action" gorm:"not null"`
+	Reason          string           `json:"reason"`
+	AuthorizationID string           `json:"authorization_id" gorm:"index"`
+	Amount          float64          `json:"amount"`
+	MerchantName    string           `json:"merchant_name"`
+	MerchantCountry string           `json:"merchant_country"`
+	Status          FraudAlertStatus `json:"status" gorm:"index;not null;default:'OPEN'"`
+	ReviewedBy      string           `json:"reviewed_by"`
+	ReviewNote      string           `json:"review_note"`
+	ReviewedAt      *time.Time       `json:"reviewed_at"`
+}
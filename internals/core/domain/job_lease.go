@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrJobLeaseHeld returned when a scheduled job is claimed while another instance holds its lease
+var ErrJobLeaseHeld = errors.New("job lease is held by another instance")
+
+// JobLease model the claim of an instance on a scheduled job, the job runs again once the lease expires so
+// instances sharing the database run it once per interval between them
+type JobLease struct {
+	Name      string    `json:"name" gorm:"primaryKey"`
+	Holder    string    `json:"holder" gorm:"not null"` // instance that ran the job last
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"github.com/satori/go.uuid"
+	"time"
+)
+
+// DomainEvent name of a state change published on the event bus
+type DomainEvent string
+
+const (
+	CompanyCreated        DomainEvent = "CompanyCreated"
+	CardIssued            DomainEvent = "CardIssued"
+	TransactionAuthorized DomainEvent = "TransactionAuthorized"
+	WalletDebited         DomainEvent = "WalletDebited"
+	WalletCredited        DomainEvent = "WalletCredited"
+	CreditLimitChanged    DomainEvent = "CreditLimitChanged"
+)
+
+// OutboxEvent model a domain event written in the same database transaction as the state change, relayed to the broker later
+type OutboxEvent struct {
+	Base
+	Type        DomainEvent `json:"type" gorm:"index;not null"`
+	Aggregate   string      `json:"aggregate" gorm:"not null"` // company, card, transaction, wallet
+	AggregateID uuid.UUID   `json:"aggregate_id" gorm:"not null;index"`
+	Company     uuid.UUID   `json:"company" gorm:"index;column:company"`
+	Payload     string      `json:"payload" gorm:"type:text"`
+	PublishedAt *time.Time  `json:"published_at" gorm:"index"`
+	Attempts    int         `json:"attempts"`
+	LastError   string      `json:"last_error"`
+}
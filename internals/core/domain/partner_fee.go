@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"github.com/satori/go.uuid"
+)
+
+// PartnerFeeDetail model a fee line the card partner charged on a transaction
+type PartnerFeeDetail struct {
+	Base
+	Transaction uuid.UUID `json:"transaction" gorm:"not null;index;column:transaction_id"`
+	Contract    string    `json:"contract"`
+	Currency    string    `json:"currency"`
+	Amount      float64   `json:"amount"`
+	Description string    `json:"description"`
+}
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"github.com/satori/go.uuid"
+)
+
+// ReceiptStatus compliance of a transaction against the company receipt policy
+type ReceiptStatus string
+
+const (
+	ReceiptNotRequired ReceiptStatus = "NOT_REQUIRED"
+	ReceiptCompliant   ReceiptStatus = "COMPLIANT"
+	ReceiptPending     ReceiptStatus = "PENDING" // missing but still within the grace days
+	ReceiptOverdue     ReceiptStatus = "OVERDUE"
+)
+
+// ReceiptPolicy model
+type ReceiptPolicy struct {
+	Base
+	Company            uuid.UUID `json:"company" gorm:"not null;uniqueIndex;column:company"`
+	AmountThreshold    float64   `json:"amount_threshold" gorm:"not null;default:0"`
+	ExemptCategories   []string  `json:"exempt_categories" gorm:"serializer:json"`
+	GraceDays          int       `json:"grace_days" gorm:"not null;default:7"`
+	AutoLockCard       bool      `json:"auto_lock_card" gorm:"not null;default:false"`
+	MaxOverdueReceipts int       `json:"max_overdue_receipts" gorm:"not null;default:0"`
+}
@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"github.com/satori/go.uuid"
+)
+
+// Tag model company defined label for transactions e.g. projects, clients, trips
+type Tag struct {
+	Base
+	Company uuid.UUID `json:"company" gorm:"not null;uniqueIndex:idx_tag_company_name;column:company"`
+	Name    string    `json:"name" gorm:"not null;uniqueIndex:idx_tag_company_name"`
+	Color   string    `json:"color"`
+}
+
+// TransactionComment model a comment on the thread of a transaction
+type TransactionComment struct {
+	Base
+	Transaction uuid.UUID `json:"transaction" gorm:"not null;index;column:transaction_id"`
+	Author      string    `json:"author" gorm:"not null"`
+	Body        string    `json:"body" gorm:"not null"`
+}
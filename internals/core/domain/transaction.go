@@ -2,6 +2,7 @@ package domain
 
 import (
 	"github.com/satori/go.uuid"
+	"time"
 )
 
 // TransactionChannel channel used for withdrawal pos, web, atm, card etc
@@ -40,6 +41,8 @@ const (
 	InterestType     TransactionType = "INTEREST"
 	CardCreationType TransactionType = "CARD"
 	ShippingType     TransactionType = "SHIPPING"
+	DisputeType      TransactionType = "DISPUTE"  // provisional credit and its reversal while a dispute is open
+	ReversalType     TransactionType = "REVERSAL" // offsetting entry of a voided transaction
 
 	PhysicalType CardType = "PHYSICAL"
 	VirtualType  CardType = "VIRTUAL"
@@ -56,9 +59,17 @@ type Transaction struct {
 	PartnerCustomerID string             `json:"partner_customer_id"`
 	Debit             float64            `json:"debit"`
 	Credit            float64            `json:"credit"`
+	Currency          string             `json:"currency"`
+	MerchantAmount    float64            `json:"merchant_amount"`   // amount in the merchant currency, differs from debit on foreign purchases
+	MerchantCurrency  string             `json:"merchant_currency"` // currency the merchant charged in
+	MerchantName      string             `json:"merchant_name" gorm:"index"`
+	MerchantCategory  string             `json:"merchant_category"`
+	MerchantCountry   string             `json:"merchant_country"`
+	Vat               float64            `json:"vat"`
 	Note              string             `json:"note" gorm:"not null"`
 	ReferenceID       string             `json:"reference_id"`
 	PartnerFee        float64            `json:"partner_fee"`
+	FeeDetails        []PartnerFeeDetail `json:"fee_details,omitempty" gorm:"ForeignKey:Transaction;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 	Fee               []uuid.UUID        `json:"fee" gorm:"type:text;column:fee"`
 	Status            TransactionStatus  `json:"status" gorm:"index;not null;"`               // Pending, Success, Failed
 	Entry             TransactionEntry   `json:"entry" gorm:"index;not null;default:'debit'"` // debit or credit
@@ -66,8 +77,18 @@ type Transaction struct {
 	Reason            string             `json:"reason"`
 	Type              TransactionType    `json:"type" gorm:"not null"` // withdrawal, cashback, interest, shipping, cards, fee, refund
 	CardType          CardType           `json:"card_type"`
-	ParentID          string             `json:"parent_id"` //Parent id for the refund
+	ParentID          string             `json:"parent_id"`                     //Parent id for the refund
+	AuthorizationID   string             `json:"authorization_id" gorm:"index"` // partner authorization the transaction belongs to
+	AuthorizedAmount  float64            `json:"authorized_amount"`
+	AmountHistory     []AmountChange     `json:"amount_history,omitempty" gorm:"ForeignKey:Transaction;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 	Lock              bool               `json:"lock" gorm:"default:false"`
+	VoidedAt          *time.Time         `json:"voided_at"` // set when a reversal offsets the transaction, voided transactions are kept
+	VoidReason        string             `json:"void_reason,omitempty"`
 	Receipt           string             `json:"receipt"`
 	ExpenseCategory   string             `json:"expense_category"`
+	Splits            []TransactionSplit `json:"splits,omitempty" gorm:"ForeignKey:Transaction;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Tags              []Tag              `json:"tags,omitempty" gorm:"many2many:transaction_tags;"`
+	ReceiptStatus     ReceiptStatus      `json:"receipt_status,omitempty" gorm:"-"` // computed from the company receipt policy
+	RefundedAmount    float64            `json:"refunded_amount,omitempty" gorm:"-"`
+	Refunds           []Transaction      `json:"refunds,omitempty" gorm:"-"` // merchant refunds and reversals of the purchase
 }
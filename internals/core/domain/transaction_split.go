@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"github.com/satori/go.uuid"
+)
+
+// TransactionSplit model a line of a card transaction that belongs to its own expense category
+type TransactionSplit struct {
+	Base
+	Transaction uuid.UUID `json:"transaction" gorm:"not null;index;column:transaction_id"`
+	Amount      float64   `json:"amount" gorm:"not null"`
+	Category    string    `json:"category" gorm:"index;not null"`
+	Memo        string    `json:"memo"`
+	CostCenter  string    `json:"cost_center"`
+}
+
+// CategoryTotal amount spent in an expense category, split lines count towards their own category
+type CategoryTotal struct {
+	Category string  `json:"category"`
+	Amount   float64 `json:"amount"`
+	Count    int64   `json:"count"`
+}
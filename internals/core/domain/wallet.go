@@ -18,4 +18,6 @@ type Wallet struct {
 	CustomerID      string    `json:"customerId" gorm:"not null;index"`
 	SudoCustomerID  *string   `json:"sudo_customer_id"`
 	Status          bool      `json:"status" gorm:"not null;index"`
+	Closed          bool      `json:"closed" gorm:"default:false"` // closed wallets are kept and refuse new debits
+	ClosedReason    string    `json:"closed_reason,omitempty"`
 }
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"github.com/satori/go.uuid"
+	"time"
+)
+
+// EventType event emitted to company webhook subscriptions
+type EventType string
+
+// DeliveryStatus pending, delivered, failed
+type DeliveryStatus string
+
+const (
+	CardCreatedEvent           EventType = "card.created"
+	CardLimitChangedEvent      EventType = "card.limit_changed"
+	TransactionAuthorizedEvent EventType = "transaction.authorized"
+	TransactionDeclinedEvent   EventType = "transaction.declined"
+	CreditLimitChangedEvent    EventType = "wallet.credit_limit_changed"
+	CardExpiringEvent          EventType = "card.expiring"
+	CardRenewedEvent           EventType = "card.renewed"
+	CardShippingUpdatedEvent   EventType = "card.shipping_updated"
+	CardFrozenByIssuerEvent    EventType = "card.frozen_by_issuer"
+
+	DeliveryPending   DeliveryStatus = "PENDING" // waiting for the first attempt or a retry
+	DeliveryDelivered DeliveryStatus = "DELIVERED"
+	DeliveryFailed    DeliveryStatus = "FAILED" // gave up after the maximum attempts
+
+	// MaxDeliveryAttempts attempts before a delivery is marked failed
+	MaxDeliveryAttempts = 8
+)
+
+// EventTypes valid event types to subscribe to
+var EventTypes = []EventType{
+	CardCreatedEvent, CardLimitChangedEvent, TransactionAuthorizedEvent,
+	TransactionDeclinedEvent, CreditLimitChangedEvent, CardExpiringEvent,
+	CardRenewedEvent, CardShippingUpdatedEvent, CardFrozenByIssuerEvent,
+}
+
+// WebhookSubscription model
+type WebhookSubscription struct {
+	Base
+	Company    uuid.UUID   `json:"company" gorm:"not null;index;column:company"`
+	URL        string      `json:"url" gorm:"not null"`
+	EventTypes []EventType `json:"event_types" gorm:"serializer:json"`
+	Secret     string      `json:"-" gorm:"not null"` // only returned once, when the subscription is created
+	Active     bool        `json:"active" gorm:"default:true"`
+}
+
+// WebhookDelivery model a log entry of an event sent to a subscription
+type WebhookDelivery struct {
+	Base
+	Subscription  uuid.UUID      `json:"subscription" gorm:"not null;index;column:subscription"`
+	Company       uuid.UUID      `json:"company" gorm:"not null;index;column:company"`
+	EventType     EventType      `json:"event_type" gorm:"not null"`
+	Payload       string         `json:"payload" gorm:"type:text"`
+	Status        DeliveryStatus `json:"status" gorm:"index;not null;default:'PENDING'"`
+	Attempts      int            `json:"attempts"`
+	ResponseCode  int            `json:"response_code"`
+	LastError     string         `json:"last_error"`
+	NextAttemptAt *time.Time     `json:"next_attempt_at" gorm:"index"`
+	DeliveredAt   *time.Time     `json:"delivered_at"`
+}
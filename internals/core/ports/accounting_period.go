@@ -0,0 +1,38 @@
+package ports
+
+import (
+	"core_business/internals/common"
+	"core_business/internals/core/domain"
+	"core_business/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"time"
+)
+
+// IAccountingPeriodRepository defines the interface for accounting period repository
+type IAccountingPeriodRepository interface {
+	GetByID(id string) (*domain.AccountingPeriod, error)
+	GetPeriodByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
+	GetByMonth(company string, year int, month int) (*domain.AccountingPeriod, error)
+	IsClosed(company string, at time.Time) (bool, error)
+	Persist(period *domain.AccountingPeriod) error
+	PersistAudit(audit *domain.PeriodAudit) error
+	DeleteAll() error
+	WithTx(tx *gorm.DB) IAccountingPeriodRepository
+}
+
+// IAccountingPeriodService defines the interface for accounting period service
+type IAccountingPeriodService interface {
+	GetPeriodByID(id string) (*domain.AccountingPeriod, error)
+	GetPeriodByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
+	ClosePeriod(body common.ClosePeriodRequest) (*domain.AccountingPeriod, error)
+	ReopenPeriod(id string, actor string, body common.ReopenPeriodRequest) (*domain.AccountingPeriod, error)
+}
+
+// IAccountingPeriodHandler defines the interface for accounting period handler
+type IAccountingPeriodHandler interface {
+	GetPeriodByID(c *gin.Context)
+	GetPeriodByCompanyID(c *gin.Context)
+	ClosePeriod(c *gin.Context)
+	ReopenPeriod(c *gin.Context)
+}
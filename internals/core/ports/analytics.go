@@ -0,0 +1,25 @@
+package ports
+
+import (
+	"core_business/internals/common"
+	"core_business/internals/core/domain"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// IAnalyticsRepository defines the interface for analytics repository
+type IAnalyticsRepository interface {
+	GetSpendTotal(company string, period domain.SpendPeriod) (*domain.SpendTotal, error)
+	GetSpendBy(company string, dimension domain.SpendDimension, period domain.SpendPeriod, limit int) ([]domain.SpendTotal, error)
+	WithTx(tx *gorm.DB) IAnalyticsRepository
+}
+
+// IAnalyticsService defines the interface for analytics service
+type IAnalyticsService interface {
+	GetSpendByCompanyID(id string, query common.SpendAnalyticsRequest) (*domain.SpendReport, error)
+}
+
+// IAnalyticsHandler defines the interface for analytics handler
+type IAnalyticsHandler interface {
+	GetSpendByCompanyID(c *gin.Context)
+}
@@ -1,11 +1,13 @@
 package ports
 
 import (
+	"context"
 	"core_business/internals/common"
 	"core_business/internals/core/domain"
 	"core_business/pkg/utils"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"time"
 )
 
 // ICardRepository defines the interface for card repository
@@ -13,8 +15,20 @@ type ICardRepository interface {
 	GetByID(id string) (*domain.Card, error)
 	GetBy(id string) (*domain.Card, error)
 	GetCardByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
+	GetCardByCustomerID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
 	Get(pagination *utils.Pagination) (*utils.Pagination, error)
 	Persist(card *domain.Card) error
+	PersistStatusChange(card *domain.Card, change *domain.CardStatusChange) error
+	GetStatusHistory(id string) ([]domain.CardStatusChange, error)
+	GetByStatus(statuses []string) ([]domain.Card, error)
+	GetByExpiryYear(statuses []string, year int) ([]domain.Card, error)
+	GetByPolicy(id string, statuses []string) ([]domain.Card, error)
+	GetPastValidity(statuses []string, at time.Time) ([]domain.Card, error)
+	PersistRecurringMerchants(merchants []domain.CardRecurringMerchant) error
+	GetRecurringMerchants(id string) ([]domain.CardRecurringMerchant, error)
+	LockToMerchant(id string, merchantID string, merchant string) error
+	ClaimSingleUse(id string, authorization string) error
+	ReleaseSingleUse(id string, authorization string) error
 	Delete(id string) error
 	DeleteAll() error
 	WithTx(tx *gorm.DB) ICardRepository
@@ -24,12 +38,20 @@ type ICardRepository interface {
 type ICardService interface {
 	GetCardByID(id string) (*domain.Card, error)
 	GetCardByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
+	GetCardByCustomerID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
 	GetAllCard(pagination *utils.Pagination) (*utils.Pagination, error)
-	CreateCard(card common.CreateCardRequest) (*domain.Card, error)
-	UpdateCard(id string, body common.UpdateSudoCardRequest) (*domain.Card, error)
-	LockCard(id string, body common.ActionOnCardRequest) (*domain.Card, error)
-	CancelCard(id string, body common.ChangeCardStatusRequest) error
-	ChangeCardPin(id string, body common.ChangeCardPinRequest) error
+	CreateCard(ctx context.Context, card common.CreateCardRequest) (*domain.Card, error)
+	UpdateCard(ctx context.Context, id string, body common.UpdateSudoCardRequest) (*domain.Card, error)
+	LockCard(ctx context.Context, id string, body common.ActionOnCardRequest) (*domain.Card, error)
+	CancelCard(ctx context.Context, id string, body common.ChangeCardStatusRequest) error
+	ChangeCardStatus(ctx context.Context, id string, body common.ChangeCardStatusRequest) (*domain.Card, error)
+	GetCardStatusHistory(id string) ([]domain.CardStatusChange, error)
+	ReconcileCardLocks(ctx context.Context) (*domain.LockReconciliation, error)
+	RenewExpiringCards(ctx context.Context, days int) (*domain.CardRenewalRun, error)
+	ExpireCardsPastValidity(ctx context.Context) (int, error)
+	UpdateCardSchedule(id string, body common.UpdateCardScheduleRequest) (*domain.Card, error)
+	GetCardRecurringMerchants(id string) ([]domain.CardRecurringMerchant, error)
+	ChangeCardPin(ctx context.Context, id string, body common.ChangeCardPinRequest) error
 	AddPAN(body common.AddPANRequest) error
 	GetSinglePAN() (*domain.PAN, error)
 	DeletePAN(id string) error
@@ -39,10 +61,17 @@ type ICardService interface {
 type ICardHandler interface {
 	GetCardByID(c *gin.Context)
 	GetCardByCompanyID(c *gin.Context)
+	GetCardByCustomerID(c *gin.Context)
 	GetAllCard(c *gin.Context)
 	CreateCard(c *gin.Context)
 	UpdateCard(c *gin.Context)
 	CancelCard(c *gin.Context)
+	ChangeCardStatus(c *gin.Context)
+	GetCardStatusHistory(c *gin.Context)
+	ReconcileCardLocks(c *gin.Context)
+	RenewExpiringCards(c *gin.Context)
+	GetCardRecurringMerchants(c *gin.Context)
+	UpdateCardSchedule(c *gin.Context)
 	ChangeCardPin(c *gin.Context)
 	LockCard(c *gin.Context)
 	AddPAN(c *gin.Context)
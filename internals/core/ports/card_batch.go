@@ -0,0 +1,40 @@
+package ports
+
+import (
+	"core_business/internals/core/domain"
+	"core_business/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"io"
+	"time"
+)
+
+// ICardBatchRepository defines the interface for card batch repository
+type ICardBatchRepository interface {
+	GetByID(id string) (*domain.CardBatch, error)
+	GetCardBatchByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
+	GetByStatus(status domain.CardBatchStatus) ([]domain.CardBatch, error)
+	Persist(batch *domain.CardBatch) error
+	PersistRow(row *domain.CardBatchRow) error
+	ClaimRow(id string, staleBefore time.Time) error
+	UpdateProgress(batch *domain.CardBatch) error
+	DeleteAll() error
+	WithTx(tx *gorm.DB) ICardBatchRepository
+}
+
+// ICardBatchService defines the interface for card batch service
+type ICardBatchService interface {
+	GetCardBatchByID(id string) (*domain.CardBatch, error)
+	GetCardBatchByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
+	CreateCardBatch(company string, file io.Reader) (*domain.CardBatch, error)
+	GetCardBatchResult(id string) ([]byte, error)
+	ResumeCardBatches()
+}
+
+// ICardBatchHandler defines the interface for card batch handler
+type ICardBatchHandler interface {
+	GetCardBatchByID(c *gin.Context)
+	GetCardBatchByCompanyID(c *gin.Context)
+	CreateCardBatch(c *gin.Context)
+	DownloadCardBatchResult(c *gin.Context)
+}
@@ -0,0 +1,38 @@
+package ports
+
+import (
+	"core_business/internals/common"
+	"core_business/internals/core/domain"
+	"core_business/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ICardFulfillmentRepository defines the interface for card fulfillment repository
+type ICardFulfillmentRepository interface {
+	GetByID(id string) (*domain.CardFulfillment, error)
+	GetByCardID(id string) (*domain.CardFulfillment, error)
+	GetFulfillmentByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
+	Persist(fulfillment *domain.CardFulfillment) error
+	PersistStatusChange(fulfillment *domain.CardFulfillment, event *domain.CardFulfillmentEvent) error
+	DeleteAll() error
+	WithTx(tx *gorm.DB) ICardFulfillmentRepository
+}
+
+// ICardFulfillmentService defines the interface for card fulfillment service
+type ICardFulfillmentService interface {
+	GetFulfillmentByID(id string) (*domain.CardFulfillment, error)
+	GetFulfillmentByCardID(id string) (*domain.CardFulfillment, error)
+	GetFulfillmentByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
+	ChangeFulfillmentStatus(id string, body common.ChangeFulfillmentStatusRequest) (*domain.CardFulfillment, error)
+	UpdateShippingAddress(id string, body common.ShippingAddress) (*domain.CardFulfillment, error)
+}
+
+// ICardFulfillmentHandler defines the interface for card fulfillment handler
+type ICardFulfillmentHandler interface {
+	GetFulfillmentByID(c *gin.Context)
+	GetFulfillmentByCardID(c *gin.Context)
+	GetFulfillmentByCompanyID(c *gin.Context)
+	ChangeFulfillmentStatus(c *gin.Context)
+	UpdateShippingAddress(c *gin.Context)
+}
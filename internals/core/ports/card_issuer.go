@@ -0,0 +1,25 @@
+package ports
+
+import (
+	"context"
+	"core_business/internals/core/domain"
+)
+
+// ICardIssuer defines the interface for a card issuing partner
+type ICardIssuer interface {
+	Name() string
+	CreateCustomer(ctx context.Context, customer domain.IssuerCustomer) (*domain.IssuerCustomer, error)
+	IssueCard(ctx context.Context, request domain.IssueCardRequest) (*domain.IssuedCard, error)
+	UpdateControls(ctx context.Context, card *domain.Card) error
+	Freeze(ctx context.Context, card *domain.Card, frozen bool) error
+	Cancel(ctx context.Context, card *domain.Card, reason string) error
+	ChangePIN(ctx context.Context, card *domain.Card, oldPin string, newPin string) error
+	FetchCard(ctx context.Context, card *domain.Card) (*domain.IssuedCard, error)
+	RevealCard(ctx context.Context, card *domain.Card) (*domain.CardSecrets, error)
+}
+
+// ICardIssuers defines the interface for finding the issuer of a company or of a card
+type ICardIssuers interface {
+	ForCompany(company *domain.Company) (ICardIssuer, error)
+	ForCard(card *domain.Card) (ICardIssuer, error)
+}
@@ -0,0 +1,39 @@
+package ports
+
+import (
+	"context"
+	"core_business/internals/common"
+	"core_business/internals/core/domain"
+	"core_business/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ICardPolicyRepository defines the interface for card policy repository
+type ICardPolicyRepository interface {
+	GetByID(id string) (*domain.CardPolicy, error)
+	GetByName(company string, name string) (*domain.CardPolicy, error)
+	GetCardPolicyByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
+	Persist(policy *domain.CardPolicy) error
+	Delete(id string) error
+	DeleteAll() error
+	WithTx(tx *gorm.DB) ICardPolicyRepository
+}
+
+// ICardPolicyService defines the interface for card policy service
+type ICardPolicyService interface {
+	GetCardPolicyByID(id string) (*domain.CardPolicy, error)
+	GetCardPolicyByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
+	CreateCardPolicy(body common.CreateCardPolicyRequest) (*domain.CardPolicy, error)
+	UpdateCardPolicy(ctx context.Context, id string, body common.UpdateCardPolicyRequest) (*domain.CardPolicyUpdate, error)
+	DeleteCardPolicy(id string) error
+}
+
+// ICardPolicyHandler defines the interface for card policy handler
+type ICardPolicyHandler interface {
+	GetCardPolicyByID(c *gin.Context)
+	GetCardPolicyByCompanyID(c *gin.Context)
+	CreateCardPolicy(c *gin.Context)
+	UpdateCardPolicy(c *gin.Context)
+	DeleteCardPolicy(c *gin.Context)
+}
@@ -0,0 +1,34 @@
+package ports
+
+import (
+	"context"
+	"core_business/internals/common"
+	"core_business/internals/core/domain"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"time"
+)
+
+// ICardRevealRepository defines the interface for card reveal repository
+type ICardRevealRepository interface {
+	PersistToken(token *domain.CardRevealToken) error
+	UseToken(hash string, at time.Time) (*domain.CardRevealToken, error)
+	PersistReveal(reveal *domain.CardReveal) error
+	GetRevealsByCardID(id string) ([]domain.CardReveal, error)
+	DeleteAll() error
+	WithTx(tx *gorm.DB) ICardRevealRepository
+}
+
+// ICardRevealService defines the interface for card reveal service
+type ICardRevealService interface {
+	CreateRevealToken(id string, actor string, ip string) (*domain.RevealToken, error)
+	RevealCard(ctx context.Context, actor string, body common.RevealCardRequest, ip string) (*domain.CardSecrets, error)
+	GetCardReveals(id string) ([]domain.CardReveal, error)
+}
+
+// ICardRevealHandler defines the interface for card reveal handler
+type ICardRevealHandler interface {
+	CreateRevealToken(c *gin.Context)
+	RevealCard(c *gin.Context)
+	GetCardReveals(c *gin.Context)
+}
@@ -1,8 +1,10 @@
 package ports
 
 import (
+	"core_business/internals/common"
 	"core_business/internals/core/domain"
 	"core_business/pkg/utils"
+	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
@@ -10,9 +12,27 @@ import (
 type ICustomerRepository interface {
 	GetByID(id string) (*domain.Customer, error)
 	GetBy(filter interface{}) (*domain.Customer, error)
+	GetByEmail(company string, email string) (*domain.Customer, error)
+	GetCustomerByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
 	Get(pagination *utils.Pagination) (*utils.Pagination, error)
 	Persist(customer *domain.Customer) error
 	Delete(id string) error
 	DeleteAll() error
 	WithTx(tx *gorm.DB) ICustomerRepository
 }
+
+// ICustomerService defines the interface for customer service
+type ICustomerService interface {
+	GetCustomerByID(id string) (*domain.Customer, error)
+	GetCustomerByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
+	CreateCustomer(body common.CreateCustomerRequest) (*domain.Customer, error)
+	UpdateCustomer(id string, body common.UpdateCustomerRequest) (*domain.Customer, error)
+}
+
+// ICustomerHandler defines the interface for customer handler
+type ICustomerHandler interface {
+	GetCustomerByID(c *gin.Context)
+	GetCustomerByCompanyID(c *gin.Context)
+	CreateCustomer(c *gin.Context)
+	UpdateCustomer(c *gin.Context)
+}
@@ -0,0 +1,40 @@
+package ports
+
+import (
+	"core_business/internals/common"
+	"core_business/internals/core/domain"
+	"core_business/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// IDisputeRepository defines the interface for dispute repository
+type IDisputeRepository interface {
+	GetByID(id string) (*domain.Dispute, error)
+	GetDisputeByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
+	GetActiveByTransaction(id string) ([]domain.Dispute, error)
+	GetByTransaction(id string) ([]domain.Dispute, error)
+	Persist(dispute *domain.Dispute) error
+	PersistEvidence(evidence *domain.DisputeEvidence) error
+	Delete(id string) error
+	DeleteAll() error
+	WithTx(tx *gorm.DB) IDisputeRepository
+}
+
+// IDisputeService defines the interface for dispute service
+type IDisputeService interface {
+	GetDisputeByID(id string) (*domain.Dispute, error)
+	GetDisputeByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
+	OpenDispute(body common.OpenDisputeRequest) (*domain.Dispute, error)
+	AddDisputeEvidence(id string, body common.DisputeEvidenceRequest) (*domain.Dispute, error)
+	ChangeDisputeStatus(id string, body common.ChangeDisputeStatusRequest) (*domain.Dispute, error)
+}
+
+// IDisputeHandler defines the interface for dispute handler
+type IDisputeHandler interface {
+	GetDisputeByID(c *gin.Context)
+	GetDisputeByCompanyID(c *gin.Context)
+	OpenDispute(c *gin.Context)
+	AddDisputeEvidence(c *gin.Context)
+	ChangeDisputeStatus(c *gin.Context)
+}
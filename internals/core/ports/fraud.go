@@ -0,0 +1,48 @@
+package ports
+
+import (
+	"context"
+	"core_business/internals/common"
+	"core_business/internals/core/domain"
+	"core_business/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"time"
+)
+
+// IFraudRepository defines the interface for fraud repository
+type IFraudRepository interface {
+	GetRuleByID(id string) (*domain.FraudRule, error)
+	GetRulesByCompanyID(id string) ([]domain.FraudRule, error)
+	PersistRule(rule *domain.FraudRule) error
+	DeleteRule(id string) error
+	GetAlertByID(id string) (*domain.FraudAlert, error)
+	GetAlertsByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
+	PersistAlert(alert *domain.FraudAlert) error
+	CountCardTransactionsSince(card string, channel domain.TransactionChannel, since time.Time) (int64, error)
+	GetCardAverageAmount(card string) (float64, int64, error)
+	CountCardMerchantCategory(card string, category string) (int64, error)
+	DeleteAll() error
+	WithTx(tx *gorm.DB) IFraudRepository
+}
+
+// IFraudService defines the interface for fraud service
+type IFraudService interface {
+	GetFraudRulesByCompanyID(id string) ([]domain.FraudRule, error)
+	CreateFraudRule(body common.CreateFraudRuleRequest) (*domain.FraudRule, error)
+	UpdateFraudRule(id string, body common.UpdateFraudRuleRequest) (*domain.FraudRule, error)
+	DeleteFraudRule(id string) error
+	GetFraudAlertsByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
+	ReviewFraudAlert(ctx context.Context, id string, body common.ReviewFraudAlertRequest) (*domain.FraudAlert, error)
+	EvaluateAuthorization(card *domain.Card, body *common.CreateTransactionRequest) error
+}
+
+// IFraudHandler defines the interface for fraud handler
+type IFraudHandler interface {
+	GetFraudRulesByCompanyID(c *gin.Context)
+	CreateFraudRule(c *gin.Context)
+	UpdateFraudRule(c *gin.Context)
+	DeleteFraudRule(c *gin.Context)
+	GetFraudAlertsByCompanyID(c *gin.Context)
+	ReviewFraudAlert(c *gin.Context)
+}
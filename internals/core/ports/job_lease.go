@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+// IJobLeaseRepository defines the interface for job lease repository
+type IJobLeaseRepository interface {
+	Acquire(name string, holder string, at time.Time, until time.Time) error
+	DeleteAll() error
+	WithTx(tx *gorm.DB) IJobLeaseRepository
+}
+
+// IJobScheduler defines the interface for the scheduler running background jobs on one instance at a time
+type IJobScheduler interface {
+	Every(name string, interval time.Duration, job func() error)
+}
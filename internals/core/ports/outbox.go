@@ -0,0 +1,25 @@
+package ports
+
+import (
+	"core_business/internals/core/domain"
+	"gorm.io/gorm"
+)
+
+// IOutboxRepository defines the interface for outbox repository
+type IOutboxRepository interface {
+	GetUnpublished(limit int) ([]domain.OutboxEvent, error)
+	Persist(event *domain.OutboxEvent) error
+	DeleteAll() error
+	WithTx(tx *gorm.DB) IOutboxRepository
+}
+
+// IBroker defines the interface for the message broker domain events are published to
+type IBroker interface {
+	Publish(topic string, key string, messageID string, payload []byte) error
+	Close() error
+}
+
+// IOutboxRelay defines the interface for the relay publishing outbox events to the broker
+type IOutboxRelay interface {
+	RelayPending() (int, error)
+}
@@ -0,0 +1,36 @@
+package ports
+
+import (
+	"core_business/internals/common"
+	"core_business/internals/core/domain"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// IReceiptPolicyRepository defines the interface for receipt policy repository
+type IReceiptPolicyRepository interface {
+	GetByID(id string) (*domain.ReceiptPolicy, error)
+	GetByCompany(id string) (*domain.ReceiptPolicy, error)
+	Persist(receiptPolicy *domain.ReceiptPolicy) error
+	Delete(id string) error
+	DeleteAll() error
+	WithTx(tx *gorm.DB) IReceiptPolicyRepository
+}
+
+// IReceiptPolicyService defines the interface for receipt policy service
+type IReceiptPolicyService interface {
+	GetReceiptPolicyByID(id string) (*domain.ReceiptPolicy, error)
+	GetReceiptPolicyByCompanyID(id string) (*domain.ReceiptPolicy, error)
+	CreateReceiptPolicy(receiptPolicy *domain.ReceiptPolicy) error
+	UpdateReceiptPolicy(id string, body common.UpdateReceiptPolicyRequest) (*domain.ReceiptPolicy, error)
+	DeleteReceiptPolicy(id string) error
+}
+
+// IReceiptPolicyHandler defines the interface for receipt policy handler
+type IReceiptPolicyHandler interface {
+	GetReceiptPolicyByID(c *gin.Context)
+	GetReceiptPolicyByCompanyID(c *gin.Context)
+	CreateReceiptPolicy(c *gin.Context)
+	UpdateReceiptPolicy(c *gin.Context)
+	DeleteReceiptPolicy(c *gin.Context)
+}
@@ -0,0 +1,39 @@
+package ports
+
+import (
+	"core_business/internals/common"
+	"core_business/internals/core/domain"
+	"core_business/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ITagRepository defines the interface for tag repository
+type ITagRepository interface {
+	GetByID(id string) (*domain.Tag, error)
+	GetByIDs(ids []string) ([]domain.Tag, error)
+	GetTagByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
+	GetByName(company string, name string) (*domain.Tag, error)
+	Persist(tag *domain.Tag) error
+	Delete(id string) error
+	DeleteAll() error
+	WithTx(tx *gorm.DB) ITagRepository
+}
+
+// ITagService defines the interface for tag service
+type ITagService interface {
+	GetTagByID(id string) (*domain.Tag, error)
+	GetTagByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
+	CreateTag(tag *domain.Tag) error
+	UpdateTag(id string, body common.UpdateTagRequest) (*domain.Tag, error)
+	DeleteTag(id string) error
+}
+
+// ITagHandler defines the interface for tag handler
+type ITagHandler interface {
+	GetTagByID(c *gin.Context)
+	GetTagByCompanyID(c *gin.Context)
+	CreateTag(c *gin.Context)
+	UpdateTag(c *gin.Context)
+	DeleteTag(c *gin.Context)
+}
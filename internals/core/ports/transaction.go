@@ -1,20 +1,37 @@
 package ports
 
 import (
+	"context"
 	"core_business/internals/common"
 	"core_business/internals/core/domain"
 	"core_business/pkg/utils"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"time"
 )
 
 // ITransactionRepository defines the interface for transaction repository
 type ITransactionRepository interface {
 	GetByID(id string) (*domain.Transaction, error)
+	GetByIDForUpdate(id string) (*domain.Transaction, error)
 	GetTransactionByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
 	GetTransactionByCardID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
+	GetTransactionByCustomerID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
 	Get(pagination *utils.Pagination) (*utils.Pagination, error)
+	GetMissingReceiptsByCustomerID(id string, policy *domain.ReceiptPolicy, pagination *utils.Pagination) (*utils.Pagination, error)
+	CountOverdueReceiptsByCardID(id string, policy *domain.ReceiptPolicy, before time.Time) (int64, error)
+	GetMerchantChargesByCardID(id string, since time.Time) ([]domain.Transaction, error)
 	GetBy(filter interface{}) ([]domain.Transaction, error)
+	GetRefundsByParentID(ids []string) ([]domain.Transaction, error)
+	GetAmountChangesByReference(reference string) ([]domain.AmountChange, error)
+	PersistAmountChange(change *domain.AmountChange) error
+	ReplaceSplits(id string, splits []domain.TransactionSplit) error
+	ReplaceFeeDetails(id string, details []domain.PartnerFeeDetail) error
+	GetCategoryTotalsByCompanyID(id string) ([]domain.CategoryTotal, error)
+	GetForExport(id string, from, to time.Time) ([]domain.Transaction, error)
+	ReplaceTags(transaction *domain.Transaction, tags []domain.Tag) error
+	GetCommentsByTransactionID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
+	PersistComment(comment *domain.TransactionComment) error
 	Persist(transaction *domain.Transaction) error
 	Delete(id string) error
 	DeleteAll() error
@@ -26,11 +43,20 @@ type ITransactionService interface {
 	GetTransactionByID(id string) (*domain.Transaction, error)
 	GetTransactionByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
 	GetTransactionByCardID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
+	GetTransactionByCustomerID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
 	GetAllTransaction(pagination *utils.Pagination) (*utils.Pagination, error)
-	CreateTransaction(transaction *common.CreateTransactionRequest) error
+	GetMissingReceiptsByCustomerID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
+	CreateTransaction(ctx context.Context, transaction *common.CreateTransactionRequest) error
 	UpdateTransaction(id string, body common.UpdateTransactionRequest) (*domain.Transaction, error)
-	DeleteTransaction(id string) error
+	VoidTransaction(id string, body common.VoidTransactionRequest) (*domain.Transaction, error)
 	LockTransaction(id string) (*domain.Transaction, error)
+	AdjustTransaction(id string, body common.AdjustTransactionRequest) (*domain.Transaction, error)
+	SplitTransaction(id string, body common.SplitTransactionRequest) (*domain.Transaction, error)
+	GetCategoryTotalsByCompanyID(id string) ([]domain.CategoryTotal, error)
+	ExportTransactionsByCompanyID(id string, query common.ExportTransactionsRequest) ([]byte, error)
+	TagTransaction(id string, body common.TagTransactionRequest) (*domain.Transaction, error)
+	GetCommentsByTransactionID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
+	CreateTransactionComment(id string, author string, body common.CreateTransactionCommentRequest) (*domain.TransactionComment, error)
 }
 
 // ITransactionHandler defines the interface for transaction handler
@@ -38,9 +64,18 @@ type ITransactionHandler interface {
 	GetTransactionByID(c *gin.Context)
 	GetTransactionByCompanyID(c *gin.Context)
 	GetTransactionByCardID(c *gin.Context)
+	GetTransactionByCustomerID(c *gin.Context)
 	GetAllTransaction(c *gin.Context)
+	GetMissingReceiptsByCustomerID(c *gin.Context)
 	CreateTransaction(c *gin.Context)
 	UpdateTransaction(c *gin.Context)
-	DeleteTransaction(c *gin.Context)
+	VoidTransaction(c *gin.Context)
 	LockTransaction(c *gin.Context)
+	AdjustTransaction(c *gin.Context)
+	SplitTransaction(c *gin.Context)
+	GetCategoryTotalsByCompanyID(c *gin.Context)
+	ExportTransactionsByCompanyID(c *gin.Context)
+	TagTransaction(c *gin.Context)
+	GetCommentsByTransactionID(c *gin.Context)
+	CreateTransactionComment(c *gin.Context)
 }
@@ -25,15 +25,17 @@ type IWalletService interface {
 	CreateWallet(wallet *domain.Wallet) error
 	DebitWallet(wallet *domain.Wallet, chargesInKobo int64) (*domain.Wallet, error)
 	CreditWallet(wallet *domain.Wallet, chargesInKobo int64) (*domain.Wallet, error)
+	OverdrawWallet(wallet *domain.Wallet, chargesInKobo int64) (*domain.Wallet, error)
 	UpdateWallet(id string, body common.UpdateWalletRequest) (*domain.Wallet, error)
 	UpdateBalance(id string, body common.UpdateWalletRequest) (*domain.Wallet, error)
-	DeleteWallet(id string) error
+	CloseWallet(id string, body common.CloseWalletRequest) (*domain.Wallet, error)
+	WithTx(tx *gorm.DB) IWalletService
 }
 
 // IWalletHandler defines the interface for wallet handler
 type IWalletHandler interface {
 	GetWalletByID(c *gin.Context)
 	CreateWallet(c *gin.Context)
-	DeleteWallet(c *gin.Context)
+	CloseWallet(c *gin.Context)
 	UpdateWallet(c *gin.Context)
 }
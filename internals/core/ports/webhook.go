@@ -0,0 +1,55 @@
+package ports
+
+import (
+	"core_business/internals/common"
+	"core_business/internals/core/domain"
+	"core_business/pkg/utils"
+	"github.com/gin-gonic/gin"
+	uuid "github.com/satori/go.uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// IEventPublisher publishes events to the webhook subscriptions of a company
+type IEventPublisher interface {
+	Publish(company uuid.UUID, event domain.EventType, data interface{})
+}
+
+// IWebhookRepository defines the interface for webhook repository
+type IWebhookRepository interface {
+	GetByID(id string) (*domain.WebhookSubscription, error)
+	GetWebhookByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
+	GetActiveByCompany(id string) ([]domain.WebhookSubscription, error)
+	Persist(subscription *domain.WebhookSubscription) error
+	Delete(id string) error
+	DeleteAll() error
+	GetDeliveryByID(id string) (*domain.WebhookDelivery, error)
+	GetDeliveriesBySubscriptionID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
+	GetDueDeliveries(before time.Time, limit int) ([]domain.WebhookDelivery, error)
+	PersistDelivery(delivery *domain.WebhookDelivery) error
+	WithTx(tx *gorm.DB) IWebhookRepository
+}
+
+// IWebhookService defines the interface for webhook service
+type IWebhookService interface {
+	IEventPublisher
+	GetWebhookByID(id string) (*domain.WebhookSubscription, error)
+	GetWebhookByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
+	CreateWebhook(body common.CreateWebhookRequest) (*domain.WebhookSubscription, error)
+	UpdateWebhook(id string, body common.UpdateWebhookRequest) (*domain.WebhookSubscription, error)
+	DeleteWebhook(id string) error
+	GetDeliveriesByWebhookID(id string, pagination *utils.Pagination) (*utils.Pagination, error)
+	RedeliverWebhook(id string) (*domain.WebhookDelivery, error)
+	RetryDueDeliveries()
+}
+
+// IWebhookHandler defines the interface for webhook handler
+type IWebhookHandler interface {
+	GetWebhookByID(c *gin.Context)
+	GetWebhookByCompanyID(c *gin.Context)
+	CreateWebhook(c *gin.Context)
+	UpdateWebhook(c *gin.Context)
+	DeleteWebhook(c *gin.Context)
+	GetDeliveriesByWebhookID(c *gin.Context)
+	RedeliverWebhook(c *gin.Context)
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"core_business/internals/common"
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"core_business/pkg/utils"
+	"errors"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+type accountingPeriodService struct {
+	AccountingPeriodRepository ports.IAccountingPeriodRepository
+	CompanyRepository          ports.ICompanyRepository
+	DB                         *gorm.DB
+	logger                     *log.Logger
+}
+
+// NewAccountingPeriodService function create a new instance for service
+func NewAccountingPeriodService(apr ports.IAccountingPeriodRepository, cr ports.ICompanyRepository, db *gorm.DB, l *log.Logger) ports.IAccountingPeriodService {
+	return &accountingPeriodService{
+		AccountingPeriodRepository: apr,
+		CompanyRepository:          cr,
+		DB:                         db,
+		logger:                     l,
+	}
+}
+
+func (as *accountingPeriodService) GetPeriodByID(id string) (*domain.AccountingPeriod, error) {
+	period, err := as.AccountingPeriodRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return period, nil
+}
+
+func (as *accountingPeriodService) GetPeriodByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error) {
+	periods, err := as.AccountingPeriodRepository.GetPeriodByCompanyID(id, pagination)
+	if err != nil {
+		as.logger.Error(err)
+		return nil, err
+	}
+	return periods, nil
+}
+
+// ClosePeriod closes a month that has ended, closing a reopened month closes it again
+func (as *accountingPeriodService) ClosePeriod(body common.ClosePeriodRequest) (*domain.AccountingPeriod, error) {
+	if _, err := as.CompanyRepository.GetByID(body.Company.String()); err != nil {
+		return nil, err
+	}
+
+	period, err := as.AccountingPeriodRepository.GetByMonth(body.Company.String(), body.Year, body.Month)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if period == nil {
+		period = &domain.AccountingPeriod{Company: body.Company, Year: body.Year, Month: body.Month}
+	} else if period.Status == domain.PeriodClosed {
+		return nil, errors.New("accounting period is already closed")
+	}
+
+	now := time.Now()
+	if period.End().After(now) {
+		return nil, errors.New("only a month that has ended can be closed")
+	}
+
+	period.Status = domain.PeriodClosed
+	period.ClosedBy = body.ClosedBy
+	period.ClosedAt = &now
+
+	err = as.audit(period, domain.ClosePeriodAction, body.ClosedBy, body.Reason)
+	if err != nil {
+		as.logger.Error(err)
+		return nil, err
+	}
+	return period, nil
+}
+
+// ReopenPeriod reopens a closed month, only the company owner is allowed and the reason goes to the audit trail, the
+// actor is the authenticated user of the request
+func (as *accountingPeriodService) ReopenPeriod(id string, actor string, body common.ReopenPeriodRequest) (*domain.AccountingPeriod, error) {
+	period, err := as.AccountingPeriodRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if period.Status != domain.PeriodClosed {
+		return nil, errors.New("accounting period is not closed")
+	}
+
+	company, err := as.CompanyRepository.GetByID(period.Company.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if actor == "" || strings.TrimSpace(actor) != company.Owner {
+		return nil, domain.ErrReopenNotAuthorized
+	}
+
+	now := time.Now()
+	period.Status = domain.PeriodOpen
+	period.ReopenedBy = actor
+	period.ReopenedAt = &now
+
+	err = as.audit(period, domain.ReopenPeriodAction, actor, body.Reason)
+	if err != nil {
+		as.logger.Error(err)
+		return nil, err
+	}
+	return period, nil
+}
+
+// audit saves the period together with its audit entry
+func (as *accountingPeriodService) audit(period *domain.AccountingPeriod, action domain.PeriodAction, actor string, reason string) error {
+	return inTransaction(as.DB, func(txx *gorm.DB) error {
+		periodRepository := as.AccountingPeriodRepository.WithTx(txx)
+		if err := periodRepository.Persist(period); err != nil {
+			return err
+		}
+
+		entry := domain.PeriodAudit{
+			Period:  period.ID,
+			Company: period.Company,
+			Action:  action,
+			Actor:   actor,
+			Reason:  reason,
+		}
+
+		if err := periodRepository.PersistAudit(&entry); err != nil {
+			return err
+		}
+
+		period.Audit = append(period.Audit, entry)
+		return nil
+	})
+}
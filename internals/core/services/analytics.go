@@ -0,0 +1,76 @@
+package services
+
+import (
+	"core_business/internals/common"
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"errors"
+	log "github.com/sirupsen/logrus"
+	"math"
+	"time"
+)
+
+type analyticsService struct {
+	AnalyticsRepository ports.IAnalyticsRepository
+	logger              *log.Logger
+}
+
+// NewAnalyticsService function create a new instance for service
+func NewAnalyticsService(ar ports.IAnalyticsRepository, l *log.Logger) ports.IAnalyticsService {
+	return &analyticsService{
+		AnalyticsRepository: ar,
+		logger:              l,
+	}
+}
+
+// GetSpendByCompanyID reports the top spend groups of a company against the previous period of the same length
+func (as *analyticsService) GetSpendByCompanyID(id string, query common.SpendAnalyticsRequest) (*domain.SpendReport, error) {
+	to := query.To
+	if to.IsZero() {
+		to = time.Now()
+	}
+
+	from := query.From
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -30)
+	}
+
+	if !from.Before(to) {
+		return nil, errors.New("from must be before to")
+	}
+
+	dimension := domain.SpendDimension(query.GroupBy)
+	period := domain.NewSpendPeriod(from, to)
+
+	total, err := as.AnalyticsRepository.GetSpendTotal(id, period)
+	if err != nil {
+		as.logger.Error(err)
+		return nil, err
+	}
+
+	rows, err := as.AnalyticsRepository.GetSpendBy(id, dimension, period, query.Limit)
+	if err != nil {
+		as.logger.Error(err)
+		return nil, err
+	}
+
+	total.Change = percentChange(total.Amount, total.PreviousAmount)
+	for i := range rows {
+		rows[i].Change = percentChange(rows[i].Amount, rows[i].PreviousAmount)
+	}
+
+	return &domain.SpendReport{
+		Dimension: dimension,
+		Period:    period,
+		Total:     *total,
+		Rows:      rows,
+	}, nil
+}
+
+// percentChange percentage change from previous to current, rounded to two decimal places
+func percentChange(current float64, previous float64) float64 {
+	if previous == 0 {
+		return 0
+	}
+	return math.Round((current-previous)/previous*10000) / 100
+}
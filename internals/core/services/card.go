@@ -1,22 +1,20 @@
 package services
 
 import (
+	"context"
 	"core_business/internals/common"
 	"core_business/internals/core/domain"
 	"core_business/internals/core/ports"
 	"core_business/pkg/config"
 	"core_business/pkg/utils"
-	"encoding/json"
 	"errors"
 	"fmt"
 	log "github.com/sirupsen/logrus"
-	"net/http"
+	"gorm.io/gorm"
 	"strings"
+	"time"
 )
 
-// CardRequest to create API request
-var CardRequest utils.Client
-
 type cardService struct {
 	CardRepository        ports.ICardRepository
 	CompanyRepository     ports.ICompanyRepository
@@ -27,6 +25,12 @@ type cardService struct {
 	TransactionRepository ports.ITransactionRepository
 	PANRepository         ports.IPANRepository
 	FeeRepository         ports.IFeeRepository
+	EventPublisher        ports.IEventPublisher
+	OutboxRepository      ports.IOutboxRepository
+	CardIssuers           ports.ICardIssuers
+	FulfillmentRepository ports.ICardFulfillmentRepository
+	CardPolicyRepository  ports.ICardPolicyRepository
+	DB                    *gorm.DB
 	logger                *log.Logger
 }
 
@@ -34,7 +38,9 @@ type cardService struct {
 func NewCardService(cr ports.ICardRepository, csr ports.ICustomerRepository,
 	ar ports.IAddressRepository, cmr ports.ICompanyRepository, fr ports.IFeeRepository,
 	ws ports.IWalletService, tr ports.ITransactionRepository, pr ports.IPANRepository,
-	wr ports.IWalletRepository, l *log.Logger) ports.ICardService {
+	wr ports.IWalletRepository, ep ports.IEventPublisher, or ports.IOutboxRepository,
+	ci ports.ICardIssuers, flr ports.ICardFulfillmentRepository, cpr ports.ICardPolicyRepository,
+	db *gorm.DB, l *log.Logger) ports.ICardService {
 	return &cardService{
 		CardRepository:        cr,
 		CompanyRepository:     cmr,
@@ -45,6 +51,12 @@ func NewCardService(cr ports.ICardRepository, csr ports.ICustomerRepository,
 		WalletRepository:      wr,
 		TransactionRepository: tr,
 		PANRepository:         pr,
+		EventPublisher:        ep,
+		OutboxRepository:      or,
+		CardIssuers:           ci,
+		FulfillmentRepository: flr,
+		CardPolicyRepository:  cpr,
+		DB:                    db,
 		logger:                l,
 	}
 }
@@ -65,6 +77,14 @@ func (cs *cardService) GetCardByCompanyID(id string, pagination *utils.Paginatio
 	return cards, nil
 }
 
+func (cs *cardService) GetCardByCustomerID(id string, pagination *utils.Pagination) (*utils.Pagination, error) {
+	cards, err := cs.CardRepository.GetCardByCustomerID(id, pagination)
+	if err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
+
 func (cs *cardService) GetAllCard(pagination *utils.Pagination) (*utils.Pagination, error) {
 	cards, err := cs.CardRepository.Get(pagination)
 	if err != nil {
@@ -73,20 +93,18 @@ func (cs *cardService) GetAllCard(pagination *utils.Pagination) (*utils.Paginati
 	return cards, nil
 }
 
-func (cs *cardService) CreateCard(body common.CreateCardRequest) (*domain.Card, error) {
+func (cs *cardService) CreateCard(ctx context.Context, body common.CreateCardRequest) (*domain.Card, error) {
 	var err error
 	var chargesIdentifier []common.PricingIdentifier
 	var chargesInKobo *int64
 
-	Headers := map[string]string{
-		"Accept":        "application/json; charset=utf-8",
-		"Authorization": "Bearer " + config.Instance.SudoAPIKey,
-		"Content-Type":  "application/json",
+	mode, err := domain.ParseCardMode(body.Mode)
+	if err != nil {
+		return nil, err
 	}
 
-	CardRequest = utils.Client{
-		BaseURL: config.Instance.SudoBaseURL,
-		Header:  Headers,
+	if mode != domain.GeneralCard && strings.ToLower(body.Type) != "virtual" {
+		return nil, domain.ErrInvalidCardMode
 	}
 
 	addressEntity := domain.Address{Company: body.Company}
@@ -116,26 +134,25 @@ func (cs *cardService) CreateCard(body common.CreateCardRequest) (*domain.Card,
 		return nil, err
 	}
 
-	customerDTO := common.CreateCustomerRequest{
-		Company: common.Company{
-			Name: company.Name,
-		},
-		Individual: common.Individual{
-			FirstName: body.User.FirstName,
-			LastName:  body.User.LastName,
-		},
-		Status: body.Status,
-		Type:   common.CustomerType,
-		Name:   fmt.Sprintf("%v %v", body.User.FirstName, body.User.LastName),
-		Phone:  body.User.Phone,
-		Email:  body.User.Email,
-		BillingAddress: common.BillingAddress{
-			Line1:      address[0].Address,
-			City:       address[0].City,
-			State:      address[0].State,
-			PostalCode: address[0].PostalCode,
-			Country:    address[0].Country,
-		},
+	issuer, err := cs.CardIssuers.ForCompany(company)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := cs.cardPolicy(&body, company)
+	if err != nil {
+		return nil, err
+	}
+
+	var schedule *domain.CardSchedule
+	if policy != nil {
+		schedule = policy.Schedule
+	}
+
+	if body.Schedule != nil {
+		if schedule, err = cardSchedule(*body.Schedule); err != nil {
+			return nil, err
+		}
 	}
 
 	if strings.ToLower(body.Type) == "virtual" {
@@ -156,226 +173,387 @@ func (cs *cardService) CreateCard(body common.CreateCardRequest) (*domain.Card,
 		return nil, errors.New("insufficient available credit")
 	}
 
-	sudoCustomer, err := cs.CreateSudoCustomer(customerDTO)
+	customer, err := cs.cardholder(ctx, &body, company, &wallet[0], &address[0], issuer)
 
 	if err != nil {
-		fmt.Println(err, "####")
 		return nil, err
 	}
 
-	customer := &domain.Customer{
-		Company:           company.ID,
-		Wallet:            wallet[0].ID,
-		PartnerCustomerID: sudoCustomer.Data.ID,
-		Address:           sudoCustomer.Data.BillingAddress.Line1,
-		City:              sudoCustomer.Data.BillingAddress.City,
-		State:             sudoCustomer.Data.BillingAddress.State,
-		Country:           sudoCustomer.Data.BillingAddress.Country,
-		PostalCode:        sudoCustomer.Data.BillingAddress.PostalCode,
-	}
-
-	cardEntity, err := cs.ReturnCardObj(&body, sudoCustomer)
+	cardEntity, err := cs.issueCardRequest(&body, customer.PartnerCustomerID)
 
 	if err != nil {
 		return nil, err
 	}
 
-	fmt.Println(cardEntity)
+	var fulfillment *domain.CardFulfillment
+	if strings.ToLower(body.Type) == "physical" {
+		fulfillment = &domain.CardFulfillment{
+			Company:         company.ID,
+			Status:          domain.FulfillmentOrdered,
+			ShippingAddress: shippingAddress(body.ShippingAddress, customer, &address[0]),
+		}
+		// a physical card can not be used before it reaches the cardholder
+		cardEntity.Status = string(domain.CardInactive)
+	}
 
-	byteBody, err := json.Marshal(cardEntity)
+	issued, err := issuer.IssueCard(ctx, *cardEntity)
 
 	if err != nil {
 		return nil, err
 	}
 
-	byteResponse, err := CardRequest.CHANGE("POST", "cards", byteBody)
-
-	var response common.CreateSudoCardResponse
-
-	err = json.Unmarshal(byteResponse, &response)
+	_, err = cs.WalletService.DebitWallet(&wallet[0], *chargesInKobo)
 
 	if err != nil {
 		return nil, err
 	}
 
-	fmt.Println(response)
+	card := &domain.Card{
+		Company:           company.ID,
+		Wallet:            wallet[0].ID,
+		Name:              fmt.Sprintf("%v %v", customer.FirstName, customer.LastName),
+		PartnerCustomerID: customer.PartnerCustomerID,
+		PartnerCardID:     issued.PartnerCardID,
+		Type:              issued.Type,
+		Brand:             issued.Brand,
+		Currency:          issued.Currency,
+		Status:            issued.Status,
+		Partner:           issuer.Name(),
+		Summary:           body.Summary,
+		Customer:          customer.ID,
+		SpendingControls:  issued.SpendingControls,
+		Business:          issued.Business,
+		Account:           issued.Account,
+		MaskedPan:         issued.MaskedPan,
+		ExpiryMonth:       issued.ExpiryMonth,
+		ExpiryYear:        domain.NormalizeExpiryYear(issued.ExpiryYear),
+		Mode:              mode,
+		Schedule:          schedule,
+	}
+
+	if policy != nil {
+		card.Policy = &policy.ID
+		card.ValidUntil = policy.ValidUntil(time.Now())
+	}
 
-	if response.StatusCode != 200 {
-		return nil, errors.New("error occurred creating card partner")
+	if fulfillment != nil {
+		card.Status = string(domain.CardPending)
+		card.Lock = true
 	}
 
-	_, err = cs.WalletService.DebitWallet(&wallet[0], *chargesInKobo)
+	err = inTransaction(cs.DB, func(txx *gorm.DB) error {
+		change := &domain.CardStatusChange{To: domain.ParseCardStatus(card.Status), Actor: "system", Reason: "card issued"}
+		if err := cs.CardRepository.WithTx(txx).PersistStatusChange(card, change); err != nil {
+			return err
+		}
+
+		if fulfillment != nil {
+			fulfillment.Card = card.ID
+			event := &domain.CardFulfillmentEvent{To: domain.FulfillmentOrdered, Actor: "system", Note: "card ordered"}
+			if err := cs.FulfillmentRepository.WithTx(txx).PersistStatusChange(fulfillment, event); err != nil {
+				return err
+			}
+		}
+		return recordEvent(cs.OutboxRepository.WithTx(txx), domain.CardIssued, "card", card.ID, card.Company, card)
+	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	err = cs.CustomerRepository.Persist(customer)
+	err = cs.LogTransactions(chargesIdentifier, card)
 
 	if err != nil {
 		return nil, err
 	}
 
-	card := &domain.Card{
-		Company:           company.ID,
-		Wallet:            wallet[0].ID,
-		Name:              fmt.Sprintf("%v %v", body.User.FirstName, body.User.LastName),
-		PartnerCustomerID: sudoCustomer.Data.ID,
-		PartnerCardID:     response.Data.ID,
-		Type:              response.Data.Type,
-		Brand:             response.Data.Brand,
-		Currency:          response.Data.Currency,
-		Status:            response.Data.Status,
-		Partner:           common.Partner,
-		Summary:           body.Summary,
-		Customer:          customer.ID,
-		SpendingControls: domain.SpendingControls{
-			Channels: domain.Channels{
-				Pos:    response.Data.SpendingControls.Channels.Pos,
-				Web:    response.Data.SpendingControls.Channels.Web,
-				Atm:    response.Data.SpendingControls.Channels.Atm,
-				Mobile: response.Data.SpendingControls.Channels.Mobile,
-			},
-			SpendingLimits: domain.SpendingLimits{
-				Amount:   response.Data.SpendingControls.SpendingLimits[0].Amount,
-				Interval: response.Data.SpendingControls.SpendingLimits[0].Interval,
-			},
-		},
-		Business:    response.Data.Business,
-		Account:     response.Data.Account,
-		MaskedPan:   response.Data.MaskedPan,
-		ExpiryMonth: response.Data.ExpiryMonth,
-		ExpiryYear:  response.Data.ExpiryYear,
+	publish(cs.EventPublisher, card.Company, domain.CardCreatedEvent, card)
+	return card, nil
+}
+
+// cardholder returns the cardholder the card is issued to, an employee already in the company is found by email
+// and reused, the cardholder is created at the issuer the first time one of their cards is issued with it
+func (cs *cardService) cardholder(ctx context.Context, body *common.CreateCardRequest, company *domain.Company, wallet *domain.Wallet,
+	address *domain.Address, issuer ports.ICardIssuer) (*domain.Customer, error) {
+	var customer *domain.Customer
+	var err error
+
+	if body.Customer != nil {
+		customer, err = cs.CustomerRepository.GetByID(body.Customer.String())
+		if err != nil {
+			return nil, err
+		}
+
+		if customer.Company != company.ID {
+			return nil, domain.ErrCustomerOfAnotherCompany
+		}
+	} else {
+		email := strings.ToLower(strings.TrimSpace(body.User.Email))
+		if email != "" {
+			customer, err = cs.CustomerRepository.GetByEmail(company.ID.String(), email)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, err
+			}
+		}
+
+		if customer == nil {
+			customer = &domain.Customer{
+				Company:   company.ID,
+				Wallet:    wallet.ID,
+				FirstName: body.User.FirstName,
+				LastName:  body.User.LastName,
+				Email:     email,
+				Phone:     body.User.Phone,
+			}
+		}
 	}
 
-	err = cs.CardRepository.Persist(card)
+	if customer.PartnerCustomerID != "" && customer.Partner == issuer.Name() {
+		return customer, nil
+	}
 
+	partnerCustomer, err := issuer.CreateCustomer(ctx, domain.IssuerCustomer{
+		CompanyName: company.Name,
+		FirstName:   customer.FirstName,
+		LastName:    customer.LastName,
+		Status:      "active",
+		Phone:       customer.Phone,
+		Email:       customer.Email,
+		Address: domain.IssuerAddress{
+			Line1:      address.Address,
+			City:       address.City,
+			State:      address.State,
+			PostalCode: address.PostalCode,
+			Country:    address.Country,
+		},
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	err = cs.LogTransactions(chargesIdentifier, card)
+	customer.Wallet = wallet.ID
+	customer.PartnerCustomerID = partnerCustomer.PartnerID
+	customer.Partner = issuer.Name()
+	customer.Address = partnerCustomer.Address.Line1
+	customer.City = partnerCustomer.Address.City
+	customer.State = partnerCustomer.Address.State
+	customer.Country = partnerCustomer.Address.Country
+	customer.PostalCode = partnerCustomer.Address.PostalCode
 
-	if err != nil {
+	if err = cs.CustomerRepository.Persist(customer); err != nil {
 		return nil, err
 	}
-
-	return card, nil
+	return customer, nil
 }
 
-func (cs *cardService) CreateSudoCustomer(customerDTO common.CreateCustomerRequest) (*common.CreateSudoCustomerResponse, error) {
-	byteBody, err := json.Marshal(customerDTO)
+// cardPolicy the card policy the card is issued on, its spending controls replace the ones of the request
+func (cs *cardService) cardPolicy(body *common.CreateCardRequest, company *domain.Company) (*domain.CardPolicy, error) {
+	if body.Policy == nil {
+		return nil, nil
+	}
 
+	policy, err := cs.CardPolicyRepository.GetByID(body.Policy.String())
 	if err != nil {
 		return nil, err
 	}
 
-	byteResponse, err := CardRequest.CHANGE("POST", "customers", byteBody)
-
-	var response common.CreateSudoCustomerResponse
+	if policy.Company != company.ID {
+		return nil, domain.ErrCardPolicyOfAnotherCompany
+	}
 
-	err = json.Unmarshal(byteResponse, &response)
+	body.SpendingControls = requestControls(policy.SpendingControls)
+	return policy, nil
+}
 
+// UpdateCardSchedule sets when the card can be used, checked on every authorization
+func (cs *cardService) UpdateCardSchedule(id string, body common.UpdateCardScheduleRequest) (*domain.Card, error) {
+	card, err := cs.CardRepository.GetByID(id)
 	if err != nil {
 		return nil, err
 	}
 
-	if response.StatusCode != 200 {
-		return nil, errors.New("error occurred creating customer partner")
+	if card.Schedule, err = cardSchedule(body.Schedule); err != nil {
+		return nil, err
 	}
 
-	return &response, nil
+	if err = cs.CardRepository.Persist(card); err != nil {
+		cs.logger.Error(err)
+		return nil, err
+	}
+	return card, nil
 }
 
-func (cs *cardService) ReturnCardObj(body *common.CreateCardRequest,
-	sudoCustomer *common.CreateSudoCustomerResponse) (*common.CreateSudoCardRequest, error) {
-	var card common.CreateSudoCardRequest
+// cardSchedule maps a requested schedule, nil when it has no windows so the card can be used at any time
+func cardSchedule(requested common.CardSchedule) (*domain.CardSchedule, error) {
+	if len(requested.Windows) == 0 {
+		return nil, nil
+	}
 
-	if strings.ToLower(body.Type) == "virtual" {
-		card = common.CreateSudoCardRequest{
-			Type:             body.Type,
-			Brand:            cs.Capitalize(strings.ToLower(body.Brand)),
-			Currency:         common.Currency,
-			Status:           body.Status,
-			CustomerID:       sudoCustomer.Data.ID,
-			FundingSourceID:  config.Instance.FundingSource,
-			SpendingControls: body.SpendingControls,
-		}
-	} else if strings.ToLower(body.Type) == "physical" {
-		var pan *domain.PAN
-		var sudoPAN *common.SudoPANNumber
-		var err error
-		var number string
+	schedule := &domain.CardSchedule{TimeZone: requested.TimeZone}
+	for _, window := range requested.Windows {
+		schedule.Windows = append(schedule.Windows, domain.ScheduleWindow{
+			Days:  window.Days,
+			Start: window.Start,
+			End:   window.End,
+		})
+	}
 
-		if config.Instance.Env == "development" {
-			sudoPAN, err = cs.GetPANSudo()
+	if err := schedule.Validate(); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
 
-			if sudoPAN == nil {
-				return nil, errors.New("no PAN available on partner")
-			}
+// shippingAddress where a physical card is sent, the company address addressed to the cardholder when none is given
+func shippingAddress(requested *common.ShippingAddress, customer *domain.Customer, address *domain.Address) domain.ShippingAddress {
+	recipient := strings.TrimSpace(fmt.Sprintf("%v %v", customer.FirstName, customer.LastName))
+	if requested == nil {
+		return domain.ShippingAddress{
+			Recipient:  recipient,
+			Phone:      customer.Phone,
+			Line1:      address.Address,
+			City:       address.City,
+			State:      address.State,
+			PostalCode: address.PostalCode,
+			Country:    address.Country,
+		}
+	}
 
-			if err != nil {
-				return nil, err
-			}
+	shipping := domain.ShippingAddress{
+		Recipient:  requested.Recipient,
+		Phone:      requested.Phone,
+		Line1:      requested.Line1,
+		Line2:      requested.Line2,
+		City:       requested.City,
+		State:      requested.State,
+		PostalCode: requested.PostalCode,
+		Country:    requested.Country,
+	}
+	if shipping.Recipient == "" {
+		shipping.Recipient = recipient
+	}
+	if shipping.Phone == "" {
+		shipping.Phone = customer.Phone
+	}
+	return shipping
+}
 
-			fmt.Println(sudoPAN, "$$$$$")
-			number = sudoPAN.Data.Number
+// issueCardRequest builds what is sent to the issuer, physical cards take a PAN from the stock in production
+func (cs *cardService) issueCardRequest(body *common.CreateCardRequest, partnerCustomerID string) (*domain.IssueCardRequest, error) {
+	request := &domain.IssueCardRequest{
+		PartnerCustomerID: partnerCustomerID,
+		Type:              body.Type,
+		Brand:             cs.Capitalize(strings.ToLower(body.Brand)),
+		Currency:          common.Currency,
+		Status:            body.Status,
+		SpendingControls:  spendingControls(body.SpendingControls),
+	}
 
-		} else if config.Instance.Env == "production" {
-			pan, err = cs.PANRepository.GetFirstOne()
+	if strings.ToLower(body.Type) == "virtual" {
+		return request, nil
+	}
 
-			if pan == nil {
-				return nil, errors.New("no PAN available")
-			}
+	if strings.ToLower(body.Type) != "physical" {
+		return nil, errors.New("invalid card type")
+	}
 
-			if err != nil {
-				return nil, err
-			}
+	// outside production the issuer provides test PANs
+	if config.Instance.Env == "production" {
+		pan, err := cs.PANRepository.GetFirstOne()
 
-			number = pan.Number
+		if pan == nil {
+			return nil, errors.New("no PAN available")
 		}
 
-		card = common.CreateSudoCardRequest{
-			Type:             body.Type,
-			Brand:            cs.Capitalize(strings.ToLower(body.Brand)),
-			Currency:         common.Currency,
-			Status:           body.Status,
-			CustomerID:       sudoCustomer.Data.ID,
-			FundingSourceID:  config.Instance.FundingSource,
-			Number:           number,
-			SpendingControls: body.SpendingControls,
+		if err != nil {
+			return nil, err
 		}
 
-	} else {
-		return nil, errors.New("invalid card type")
+		request.Number = pan.Number
 	}
 
-	return &card, nil
+	return request, nil
 }
 
-func (cs *cardService) Capitalize(value string) string {
-	return strings.ToUpper(string(value[0])) + value[1:]
+func spendingControls(controls common.SpendingControls) domain.SpendingControls {
+	result := domain.SpendingControls{
+		Channels: domain.Channels{
+			Atm:    controls.Channels.Atm,
+			Pos:    controls.Channels.Pos,
+			Web:    controls.Channels.Web,
+			Mobile: controls.Channels.Mobile,
+		},
+		AllowedCategories: controls.AllowedCategories,
+		BlockedCategories: controls.BlockedCategories,
+	}
+
+	if len(controls.SpendingLimits) > 0 {
+		result.SpendingLimits = domain.SpendingLimits{
+			Amount:   controls.SpendingLimits[0].Amount,
+			Interval: controls.SpendingLimits[0].Interval,
+		}
+	}
+	return result
 }
 
-func (cs *cardService) UpdateCard(id string, body common.UpdateSudoCardRequest) (*domain.Card, error) {
-	Headers := map[string]string{
-		"Accept":        "application/json; charset=utf-8",
-		"Authorization": "Bearer " + config.Instance.SudoAPIKey,
-		"Content-Type":  "application/json",
+// requestControls the spending controls of a card as a card request takes them
+func requestControls(controls domain.SpendingControls) common.SpendingControls {
+	allowed := controls.AllowedCategories
+	if allowed == nil {
+		allowed = []string{}
+	}
+
+	blocked := controls.BlockedCategories
+	if blocked == nil {
+		blocked = []string{}
 	}
 
-	CardRequest = utils.Client{
-		BaseURL: config.Instance.SudoBaseURL,
-		Header:  Headers,
+	return common.SpendingControls{
+		AllowedCategories: allowed,
+		BlockedCategories: blocked,
+		Channels: common.Channels{
+			Atm:    controls.Channels.Atm,
+			Web:    controls.Channels.Web,
+			Pos:    controls.Channels.Pos,
+			Mobile: controls.Channels.Mobile,
+		},
+		SpendingLimits: []common.SpendingLimits{
+			{
+				Amount:   controls.SpendingLimits.Amount,
+				Interval: controls.SpendingLimits.Interval,
+			},
+		},
 	}
+}
+
+func (cs *cardService) Capitalize(value string) string {
+	return strings.ToUpper(string(value[0])) + value[1:]
+}
 
+func (cs *cardService) UpdateCard(ctx context.Context, id string, body common.UpdateSudoCardRequest) (*domain.Card, error) {
 	card, err := cs.CardRepository.GetByID(id)
 	if err != nil {
 		return nil, err
 	}
 
+	from := domain.ParseCardStatus(card.Status)
+	status := from
 	if body.Status != nil {
-		card.Status = *body.Status
+		status = domain.ParseCardStatus(*body.Status)
+		if status != from && !domain.CanTransitionCard(from, status) {
+			return nil, fmt.Errorf("%w: cannot move card from %v to %v", domain.ErrInvalidCardTransition, from, status)
+		}
+
+		if status != from {
+			if err = cs.checkDelivered(card, from, status); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	issuer, err := cs.CardIssuers.ForCard(card)
+	if err != nil {
+		return nil, err
 	}
 
 	if body.SpendingControls.SpendingLimits.Amount != nil {
@@ -398,187 +576,204 @@ func (cs *cardService) UpdateCard(id string, body common.UpdateSudoCardRequest)
 		card.SpendingControls.Channels.Web = *body.SpendingControls.Channels.Web
 	}
 
-	body.SpendingControls.AllowedCategories = []string{}
-	body.SpendingControls.BlockedCategories = []string{}
-
-	byteBody, err := json.Marshal(body)
-
-	if err != nil {
+	if err = issuer.UpdateControls(ctx, card); err != nil {
 		return nil, err
 	}
 
-	url := fmt.Sprintf("%v/%v", "cards", card.PartnerCardID)
-
-	byteResponse, err := CardRequest.CHANGE(http.MethodPut, url, byteBody)
-
-	if err != nil {
-		return nil, err
+	// the issuer is given its own status, locked cards are inactive there
+	if partnerStatus := status.PartnerStatus(); status != from && partnerStatus != "" {
+		if err = syncIssuerStatus(ctx, cs.CardIssuers, card, partnerStatus, "card update"); err != nil {
+			return nil, err
+		}
 	}
 
-	var response common.ProcessCardUpdate
-
-	err = json.Unmarshal(byteResponse, &response)
-
-	if response.StatusCode != 200 {
-		return nil, errors.New("error occurred updating card partner")
+	if status != from {
+		err = transitionCard(cs.CardRepository, card, status, actorOrDefault(""), "card update")
+	} else {
+		err = cs.CardRepository.Persist(card)
 	}
 
-	err = cs.CardRepository.Persist(card)
-
 	if err != nil {
 		fmt.Println("E HAPPEN 2")
 
 		return nil, err
 	}
 
+	if body.SpendingControls.SpendingLimits.Amount != nil || body.SpendingControls.SpendingLimits.Interval != nil {
+		publish(cs.EventPublisher, card.Company, domain.CardLimitChangedEvent, card)
+	}
+
 	return card, nil
 }
 
-func (cs *cardService) LockCard(id string, body common.ActionOnCardRequest) (*domain.Card, error) {
+func (cs *cardService) LockCard(ctx context.Context, id string, body common.ActionOnCardRequest) (*domain.Card, error) {
 	card, err := cs.CardRepository.GetByID(id)
 
 	if err != nil {
 		return nil, err
 	}
 
-	card.Lock = body.Lock
-
-	err = cs.CardRepository.Persist(card)
-	if err != nil {
-		return nil, err
+	status := domain.CardActive
+	if body.Lock {
+		status = domain.CardLocked
 	}
 
-	return card, nil
-}
+	from := domain.ParseCardStatus(card.Status)
+	if from == status {
+		return card, nil
+	}
 
-func (cs *cardService) CancelCard(id string, body common.ChangeCardStatusRequest) error {
-	Headers := map[string]string{
-		"Accept":        "application/json; charset=utf-8",
-		"Authorization": "Bearer " + config.Instance.SudoAPIKey,
-		"Content-Type":  "application/json",
+	if !domain.CanTransitionCard(from, status) {
+		return nil, fmt.Errorf("%w: cannot move card from %v to %v", domain.ErrInvalidCardTransition, from, status)
 	}
 
-	CardRequest = utils.Client{
-		BaseURL: config.Instance.SudoBaseURL,
-		Header:  Headers,
+	if err = cs.checkDelivered(card, from, status); err != nil {
+		return nil, err
 	}
 
-	card, err := cs.CardRepository.GetByID(id)
+	// the issuer is frozen first so a card we report locked can not be used offline or on partner approvals
+	if err = syncIssuerStatus(ctx, cs.CardIssuers, card, status.PartnerStatus(), body.Reason); err != nil {
+		cs.logger.Error(err)
+		return nil, err
+	}
 
+	err = transitionCard(cs.CardRepository, card, status, actorOrDefault(body.Actor), body.Reason)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	return card, nil
+}
+
+func (cs *cardService) CancelCard(ctx context.Context, id string, body common.ChangeCardStatusRequest) error {
 	isValid := body.Status != "active" && body.Status != "inactive" && body.Status != "canceled"
 
 	if isValid {
 		return errors.New("invalid status")
 	}
 
-	var listOfSpendingLimits = []common.SpendingLimits{
-		common.SpendingLimits{
-			Amount:   card.SpendingControls.SpendingLimits.Amount,
-			Interval: card.SpendingControls.SpendingLimits.Interval,
-		},
-	}
-
 	if body.Reason == "" {
 		body.Reason = "lost"
 	}
 
-	request := common.CancelCardRequest{
-		Status: body.Status,
-		Reason: body.Reason,
-		SpendingControls: common.SpendingControls{
-			SpendingLimits: listOfSpendingLimits,
-			Channels: common.Channels{
-				Pos:    card.SpendingControls.Channels.Pos,
-				Web:    card.SpendingControls.Channels.Web,
-				Atm:    card.SpendingControls.Channels.Atm,
-				Mobile: false,
-			},
-			AllowedCategories: []string{},
-			BlockedCategories: []string{},
-		},
-	}
-
-	byteBody, err := json.Marshal(request)
-
+	_, err := cs.ChangeCardStatus(ctx, id, body)
 	if err != nil {
 		return err
 	}
 
-	url := fmt.Sprintf("%v/%v", "cards", card.PartnerCardID)
+	return nil
+}
 
-	byteResponse, err := CardRequest.CHANGE(http.MethodPut, url, byteBody)
+// ChangeCardStatus moves a card through its lifecycle, statuses the partner knows about are changed there first
+func (cs *cardService) ChangeCardStatus(ctx context.Context, id string, body common.ChangeCardStatusRequest) (*domain.Card, error) {
+	card, err := cs.CardRepository.GetByID(id)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	var response common.ProcessCardUpdate
+	status := domain.ParseCardStatus(body.Status)
+	from := domain.ParseCardStatus(card.Status)
 
-	err = json.Unmarshal(byteResponse, &response)
+	if !domain.CanTransitionCard(from, status) {
+		return nil, fmt.Errorf("%w: cannot move card from %v to %v", domain.ErrInvalidCardTransition, from, status)
+	}
 
-	if response.StatusCode != 200 {
-		return errors.New("error occurred updating card partner")
+	if err = cs.checkDelivered(card, from, status); err != nil {
+		return nil, err
 	}
 
-	card.Status = body.Status
+	if partnerStatus := status.PartnerStatus(); partnerStatus != "" {
+		reason := body.Reason
+		if status == domain.CardLost || status == domain.CardStolen {
+			// the partner only knows canceled, the reason tells it why
+			reason = string(status)
+		}
 
-	err = cs.CardRepository.Delete(card.ID.String())
+		if err = syncIssuerStatus(ctx, cs.CardIssuers, card, partnerStatus, reason); err != nil {
+			return nil, err
+		}
+	}
 
+	err = transitionCard(cs.CardRepository, card, status, actorOrDefault(body.Actor), body.Reason)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
-
+	return card, nil
 }
 
-func (cs *cardService) ChangeCardPin(id string, body common.ChangeCardPinRequest) error {
+func (cs *cardService) GetCardStatusHistory(id string) ([]domain.CardStatusChange, error) {
+	if _, err := cs.CardRepository.GetByID(id); err != nil {
+		return nil, err
+	}
 
-	Headers := map[string]string{
-		"Accept":        "application/json; charset=utf-8",
-		"Authorization": "Bearer " + config.Instance.SudoAPIKey,
-		"Content-Type":  "application/json",
+	history, err := cs.CardRepository.GetStatusHistory(id)
+	if err != nil {
+		cs.logger.Error(err)
+		return nil, err
 	}
+	return history, nil
+}
 
-	CardRequest = utils.Client{
-		BaseURL: config.Instance.SudoBaseURL,
-		Header:  Headers,
+// checkDelivered only lets a pending physical card be activated once its fulfillment is delivered
+func (cs *cardService) checkDelivered(card *domain.Card, from, to domain.CardStatus) error {
+	if from != domain.CardPending || to != domain.CardActive || strings.ToLower(card.Type) != "physical" {
+		return nil
 	}
 
-	card, err := cs.CardRepository.GetByID(id)
+	fulfillment, err := cs.FulfillmentRepository.GetByCardID(card.ID.String())
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// physical cards issued before fulfillment was tracked
+			return nil
+		}
 		return err
 	}
 
-	byteBody, err := json.Marshal(body)
+	if fulfillment.Status != domain.FulfillmentDelivered {
+		return fmt.Errorf("%w: card fulfillment is %v", domain.ErrCardNotDelivered, fulfillment.Status)
+	}
+	return nil
+}
 
-	if err != nil {
-		return err
+// transitionCard moves a card through the state machine and records who moved it and why, only active cards are unlocked
+func transitionCard(cardRepository ports.ICardRepository, card *domain.Card, to domain.CardStatus, actor string, reason string) error {
+	from := domain.ParseCardStatus(card.Status)
+	if !domain.CanTransitionCard(from, to) {
+		return fmt.Errorf("%w: cannot move card from %v to %v", domain.ErrInvalidCardTransition, from, to)
 	}
 
-	url := fmt.Sprintf("%v/%v/%v", "cards", card.PartnerCardID, "pin")
+	card.Status = string(to)
+	card.Lock = to != domain.CardActive
 
-	byteResponse, err := CardRequest.CHANGE("PUT", url, byteBody)
+	return cardRepository.PersistStatusChange(card, &domain.CardStatusChange{
+		From:   from,
+		To:     to,
+		Actor:  actor,
+		Reason: reason,
+	})
+}
 
+func actorOrDefault(actor string) string {
+	if actor == "" {
+		return "api"
+	}
+	return actor
+}
+
+func (cs *cardService) ChangeCardPin(ctx context.Context, id string, body common.ChangeCardPinRequest) error {
+	card, err := cs.CardRepository.GetByID(id)
 	if err != nil {
 		return err
 	}
 
-	var response common.ProcessCardUpdate
-
-	err = json.Unmarshal(byteResponse, &response)
-
-	fmt.Println(response)
-
-	if response.StatusCode != 200 {
-		return errors.New("error occurred updating card partner")
+	issuer, err := cs.CardIssuers.ForCard(card)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	return issuer.ChangePIN(ctx, card, body.OldPin, body.NewPin)
 }
 
 func (cs *cardService) BalanceSufficiency(wallet *domain.Wallet, chargesInKobo int64) bool {
@@ -589,18 +784,26 @@ func (cs *cardService) BalanceSufficiency(wallet *domain.Wallet, chargesInKobo i
 }
 
 func (cs *cardService) GetAllCharges(identifiers []common.PricingIdentifier, wallet *domain.Wallet) (*int64, error) {
+	chargesInKobo, err := cardCharges(cs.FeeRepository, identifiers)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chargesInKobo, nil
+}
+
+// cardCharges sums the fees of the pricing identifiers in kobo
+func cardCharges(feeRepository ports.IFeeRepository, identifiers []common.PricingIdentifier) (int64, error) {
 	var charges float64
 	for _, identifier := range identifiers {
-		fee, err := cs.FeeRepository.GetByIdentifier(string(identifier))
+		fee, err := feeRepository.GetByIdentifier(string(identifier))
 		if err != nil {
-			return nil, err
+			return 0, err
 		}
 		charges += fee.Fee
 	}
 
-	chargesInKobo := utils.ToMinorUnit(charges)
-
-	return &chargesInKobo, nil
+	return utils.ToMinorUnit(charges), nil
 }
 
 func (cs *cardService) LogTransactions(identifiers []common.PricingIdentifier, card *domain.Card) error {
@@ -675,22 +878,3 @@ func (cs *cardService) DeletePAN(id string) error {
 	}
 	return nil
 }
-
-func (cs *cardService) GetPANSudo() (*common.SudoPANNumber, error) {
-	byteResponse, err := CardRequest.GET("GET", "cards/simulator/generate", nil)
-
-	if err != nil {
-		return nil, err
-	}
-
-	var response common.SudoPANNumber
-
-	err = json.Unmarshal(byteResponse, &response)
-
-	fmt.Println(response)
-
-	if response.StatusCode != 200 {
-		return nil, errors.New("error occurred updating card partner")
-	}
-	return &response, nil
-}
@@ -0,0 +1,382 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"core_business/internals/common"
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"core_business/pkg/utils"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxCardBatchRows largest CSV a single batch accepts
+const maxCardBatchRows = 1000
+
+var cardBatchColumns = []string{"name", "email", "phone", "type", "brand", "template"}
+
+var cardBrands = []string{"verve", "visa", "mastercard"}
+
+var phonePattern = regexp.MustCompile(`^\+?[0-9]{10,15}$`)
+
+type cardBatchService struct {
+	CardBatchRepository ports.ICardBatchRepository
+	CompanyRepository   ports.ICompanyRepository
+	AddressRepository   ports.IAddressRepository
+	WalletRepository    ports.IWalletRepository
+	FeeRepository       ports.IFeeRepository
+	CardService         ports.ICardService
+	logger              *log.Logger
+}
+
+// NewCardBatchService function create a new instance for service
+func NewCardBatchService(br ports.ICardBatchRepository, cmr ports.ICompanyRepository,
+	ar ports.IAddressRepository, wr ports.IWalletRepository, fr ports.IFeeRepository,
+	cs ports.ICardService, l *log.Logger) ports.ICardBatchService {
+	return &cardBatchService{
+		CardBatchRepository: br,
+		CompanyRepository:   cmr,
+		AddressRepository:   ar,
+		WalletRepository:    wr,
+		FeeRepository:       fr,
+		CardService:         cs,
+		logger:              l,
+	}
+}
+
+func (bs *cardBatchService) GetCardBatchByID(id string) (*domain.CardBatch, error) {
+	batch, err := bs.CardBatchRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+func (bs *cardBatchService) GetCardBatchByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error) {
+	batches, err := bs.CardBatchRepository.GetCardBatchByCompanyID(id, pagination)
+	if err != nil {
+		return nil, err
+	}
+	return batches, nil
+}
+
+// CreateCardBatch validates every row of the CSV and the wallet before any card is issued, the cards are then
+// issued in the background and each row keeps its own outcome
+func (bs *cardBatchService) CreateCardBatch(company string, file io.Reader) (*domain.CardBatch, error) {
+	owner, err := bs.CompanyRepository.GetByID(company)
+	if err != nil {
+		return nil, err
+	}
+
+	address, err := bs.AddressRepository.GetBy(domain.Address{Company: owner.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(address) < 1 {
+		return nil, errors.New("company address not set")
+	}
+
+	wallet, err := bs.WalletRepository.GetBy(domain.Wallet{Company: owner.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(wallet) < 1 {
+		return nil, errors.New("account has not been integrated")
+	}
+
+	if wallet[0].Closed {
+		return nil, errors.New("wallet is closed")
+	}
+
+	rows, err := parseCardBatch(file)
+	if err != nil {
+		return nil, err
+	}
+
+	fees := map[string]int64{}
+	var totalFee int64
+	for _, row := range rows {
+		if _, ok := fees[row.Type]; !ok {
+			identifiers := common.VirtualCardIdentifier
+			if row.Type == "physical" {
+				identifiers = common.PhysicalCardIdentifier
+			}
+
+			if fees[row.Type], err = cardCharges(bs.FeeRepository, identifiers); err != nil {
+				return nil, err
+			}
+		}
+		totalFee += fees[row.Type]
+	}
+
+	if wallet[0].AvailableCredit <= totalFee {
+		return nil, fmt.Errorf("insufficient available credit: %v cards need %v kobo in fees", len(rows), totalFee)
+	}
+
+	batch := &domain.CardBatch{
+		Company:  owner.ID,
+		Wallet:   wallet[0].ID,
+		Status:   domain.BatchPending,
+		Total:    len(rows),
+		TotalFee: totalFee,
+		Rows:     rows,
+	}
+
+	if err = bs.CardBatchRepository.Persist(batch); err != nil {
+		bs.logger.Error(err)
+		return nil, err
+	}
+
+	// the background issuance works on its own copy, the batch is returned as it was created
+	processing := *batch
+	processing.Rows = append([]domain.CardBatchRow{}, batch.Rows...)
+	go bs.process(&processing)
+
+	return batch, nil
+}
+
+// ResumeCardBatches picks up the batches a restart or a dead worker left unfinished, rows already tried are not
+// issued again and rows claimed by a worker are only taken over once the claim went stale
+func (bs *cardBatchService) ResumeCardBatches() {
+	for _, status := range []domain.CardBatchStatus{domain.BatchProcessing, domain.BatchPending} {
+		batches, err := bs.CardBatchRepository.GetByStatus(status)
+		if err != nil {
+			bs.logger.Error(err)
+			return
+		}
+
+		for i := range batches {
+			bs.process(&batches[i])
+		}
+	}
+}
+
+// GetCardBatchResult the rows of the batch with their outcome as a CSV
+func (bs *cardBatchService) GetCardBatchResult(id string) ([]byte, error) {
+	batch, err := bs.CardBatchRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+
+	header := []string{"line", "name", "email", "phone", "type", "brand", "template", "status", "card", "masked_pan", "error"}
+	if err = writer.Write(header); err != nil {
+		return nil, err
+	}
+
+	for _, row := range batch.Rows {
+		var cardID, maskedPan string
+		if row.Card != nil {
+			cardID = row.Card.String()
+			if card, err := bs.CardService.GetCardByID(cardID); err == nil {
+				maskedPan = card.MaskedPan
+			}
+		}
+
+		record := []string{
+			strconv.Itoa(row.Line),
+			strings.TrimSpace(row.FirstName + " " + row.LastName),
+			row.Email,
+			row.Phone,
+			row.Type,
+			row.Brand,
+			row.Template,
+			string(row.Status),
+			cardID,
+			maskedPan,
+			row.Error,
+		}
+		if err = writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	writer.Flush()
+	if err = writer.Error(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func (bs *cardBatchService) process(batch *domain.CardBatch) {
+	batch.Status = domain.BatchProcessing
+	bs.saveProgress(batch)
+
+	for i := range batch.Rows {
+		row := &batch.Rows[i]
+		if row.Status == domain.RowIssued || row.Status == domain.RowFailed {
+			continue
+		}
+
+		// a row another worker claimed is left to it unless its claim went stale
+		if err := bs.CardBatchRepository.ClaimRow(row.ID.String(), time.Now().Add(-domain.CardBatchClaimTTL)); err != nil {
+			if !errors.Is(err, domain.ErrCardBatchRowClaimed) {
+				bs.logger.Error(err)
+			}
+			continue
+		}
+
+		card, err := bs.CardService.CreateCard(context.Background(), cardBatchRequest(batch, row))
+		if err != nil {
+			row.Status = domain.RowFailed
+			row.Error = err.Error()
+		} else {
+			row.Status = domain.RowIssued
+			row.Card = &card.ID
+		}
+
+		if err = bs.CardBatchRepository.PersistRow(row); err != nil {
+			bs.logger.Error(err)
+		}
+		bs.saveProgress(batch)
+	}
+}
+
+// saveProgress saves the status of the batch, the counters and completion come from the rows so workers sharing
+// a batch agree
+func (bs *cardBatchService) saveProgress(batch *domain.CardBatch) {
+	if err := bs.CardBatchRepository.UpdateProgress(batch); err != nil {
+		bs.logger.Error(err)
+	}
+}
+
+func cardBatchRequest(batch *domain.CardBatch, row *domain.CardBatchRow) common.CreateCardRequest {
+	return common.CreateCardRequest{
+		Name:    fmt.Sprintf("%v %v", row.FirstName, row.LastName),
+		Company: batch.Company,
+		Type:    row.Type,
+		Brand:   row.Brand,
+		Status:  string(domain.CardActive),
+		User: common.User{
+			FirstName: row.FirstName,
+			LastName:  row.LastName,
+			Email:     row.Email,
+			Phone:     row.Phone,
+		},
+		SpendingControls: requestControls(domain.SpendingTemplates[row.Template]),
+	}
+}
+
+// parseCardBatch reads the rows of the CSV, every invalid row is reported together
+func parseCardBatch(file io.Reader) ([]domain.CardBatchRow, error) {
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv: %w", err)
+	}
+
+	if len(records) < 2 {
+		return nil, errors.New("csv has no employees")
+	}
+
+	if len(records)-1 > maxCardBatchRows {
+		return nil, fmt.Errorf("csv has more than %v employees", maxCardBatchRows)
+	}
+
+	columns := map[string]int{}
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for _, name := range cardBatchColumns {
+		if _, ok := columns[name]; !ok && name != "template" {
+			return nil, fmt.Errorf("csv is missing the %v column", name)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var rows []domain.CardBatchRow
+	var invalid []domain.CardBatchRowError
+	emails := map[string]int{}
+
+	for i, record := range records[1:] {
+		line := i + 2
+		names := strings.Fields(field(record, "name"))
+		row := domain.CardBatchRow{
+			Line:     line,
+			Email:    strings.ToLower(field(record, "email")),
+			Phone:    field(record, "phone"),
+			Type:     strings.ToLower(field(record, "type")),
+			Brand:    strings.ToLower(field(record, "brand")),
+			Template: strings.ToLower(field(record, "template")),
+			Status:   domain.RowPending,
+		}
+
+		if row.Template == "" {
+			row.Template = domain.DefaultSpendingTemplate
+		}
+
+		var problems []string
+		if len(names) < 2 {
+			problems = append(problems, "first and last name are required")
+		} else {
+			row.FirstName = strings.Join(names[:len(names)-1], " ")
+			row.LastName = names[len(names)-1]
+		}
+
+		if _, err := mail.ParseAddress(row.Email); err != nil {
+			problems = append(problems, "invalid email")
+		} else if previous, ok := emails[row.Email]; ok {
+			problems = append(problems, fmt.Sprintf("email already on line %v", previous))
+		} else {
+			emails[row.Email] = line
+		}
+
+		if !phonePattern.MatchString(row.Phone) {
+			problems = append(problems, "invalid phone")
+		}
+
+		if row.Type != "virtual" && row.Type != "physical" {
+			problems = append(problems, "type must be virtual or physical")
+		}
+
+		if !contains(cardBrands, row.Brand) {
+			problems = append(problems, fmt.Sprintf("brand must be one of %v", strings.Join(cardBrands, ", ")))
+		}
+
+		if _, ok := domain.SpendingTemplates[row.Template]; !ok {
+			problems = append(problems, fmt.Sprintf("unknown spending template %v", row.Template))
+		}
+
+		if len(problems) > 0 {
+			invalid = append(invalid, domain.CardBatchRowError{Line: line, Error: strings.Join(problems, ", ")})
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	if len(invalid) > 0 {
+		return nil, &domain.CardBatchValidationError{Rows: invalid}
+	}
+	return rows, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
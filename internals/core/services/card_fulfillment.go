@@ -0,0 +1,150 @@
+package services
+
+import (
+	"core_business/internals/common"
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"core_business/pkg/utils"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"strings"
+	"time"
+)
+
+type cardFulfillmentService struct {
+	FulfillmentRepository ports.ICardFulfillmentRepository
+	EventPublisher        ports.IEventPublisher
+	logger                *log.Logger
+}
+
+// NewCardFulfillmentService function create a new instance for service
+func NewCardFulfillmentService(fr ports.ICardFulfillmentRepository, ep ports.IEventPublisher,
+	l *log.Logger) ports.ICardFulfillmentService {
+	return &cardFulfillmentService{
+		FulfillmentRepository: fr,
+		EventPublisher:        ep,
+		logger:                l,
+	}
+}
+
+func (fs *cardFulfillmentService) GetFulfillmentByID(id string) (*domain.CardFulfillment, error) {
+	fulfillment, err := fs.FulfillmentRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return fulfillment, nil
+}
+
+func (fs *cardFulfillmentService) GetFulfillmentByCardID(id string) (*domain.CardFulfillment, error) {
+	fulfillment, err := fs.FulfillmentRepository.GetByCardID(id)
+	if err != nil {
+		return nil, err
+	}
+	return fulfillment, nil
+}
+
+func (fs *cardFulfillmentService) GetFulfillmentByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error) {
+	fulfillments, err := fs.FulfillmentRepository.GetFulfillmentByCompanyID(id, pagination)
+	if err != nil {
+		return nil, err
+	}
+	return fulfillments, nil
+}
+
+// ChangeFulfillmentStatus moves a card through printing and delivery, a card is only dispatched with the courier
+// and its tracking reference so support can tell the cardholder where it is
+func (fs *cardFulfillmentService) ChangeFulfillmentStatus(id string, body common.ChangeFulfillmentStatusRequest) (*domain.CardFulfillment, error) {
+	fulfillment, err := fs.FulfillmentRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	from := fulfillment.Status
+	to := domain.FulfillmentStatus(strings.ToUpper(strings.TrimSpace(body.Status)))
+	if !domain.CanTransitionFulfillment(from, to) {
+		return nil, fmt.Errorf("%w: cannot move fulfillment from %v to %v", domain.ErrInvalidFulfillmentTransition, from, to)
+	}
+
+	if body.Courier != "" {
+		fulfillment.Courier = body.Courier
+	}
+
+	if body.TrackingReference != "" {
+		fulfillment.TrackingReference = body.TrackingReference
+	}
+
+	now := time.Now()
+	switch to {
+	case domain.FulfillmentDispatched:
+		if fulfillment.Courier == "" || fulfillment.TrackingReference == "" {
+			return nil, domain.ErrTrackingReferenceRequired
+		}
+		fulfillment.DispatchedAt = &now
+	case domain.FulfillmentDelivered:
+		fulfillment.DeliveredAt = &now
+	}
+
+	fulfillment.Status = to
+	event := &domain.CardFulfillmentEvent{
+		From:  from,
+		To:    to,
+		Actor: actorOrDefault(body.Actor),
+		Note:  body.Note,
+	}
+
+	if err = fs.FulfillmentRepository.PersistStatusChange(fulfillment, event); err != nil {
+		fs.logger.Error(err)
+		return nil, err
+	}
+	fulfillment.Events = append(fulfillment.Events, *event)
+
+	publish(fs.EventPublisher, fulfillment.Company, domain.CardShippingUpdatedEvent, fulfillment)
+	return fulfillment, nil
+}
+
+// UpdateShippingAddress changes where the card is sent, only before it is dispatched or after the courier returned it
+func (fs *cardFulfillmentService) UpdateShippingAddress(id string, body common.ShippingAddress) (*domain.CardFulfillment, error) {
+	fulfillment, err := fs.FulfillmentRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if fulfillment.Status == domain.FulfillmentDispatched || fulfillment.Status == domain.FulfillmentDelivered {
+		return nil, domain.ErrShippingAddressLocked
+	}
+
+	// the cardholder stays the recipient unless someone else is named
+	if body.Recipient == "" {
+		body.Recipient = fulfillment.ShippingAddress.Recipient
+	}
+	if body.Phone == "" {
+		body.Phone = fulfillment.ShippingAddress.Phone
+	}
+
+	fulfillment.ShippingAddress = domain.ShippingAddress{
+		Recipient:  body.Recipient,
+		Phone:      body.Phone,
+		Line1:      body.Line1,
+		Line2:      body.Line2,
+		City:       body.City,
+		State:      body.State,
+		PostalCode: body.PostalCode,
+		Country:    body.Country,
+	}
+
+	// the change is kept in the history of the fulfillment, the status stays the same
+	event := &domain.CardFulfillmentEvent{
+		From:  fulfillment.Status,
+		To:    fulfillment.Status,
+		Actor: actorOrDefault(""),
+		Note:  "shipping address changed",
+	}
+
+	if err = fs.FulfillmentRepository.PersistStatusChange(fulfillment, event); err != nil {
+		fs.logger.Error(err)
+		return nil, err
+	}
+	fulfillment.Events = append(fulfillment.Events, *event)
+
+	return fulfillment, nil
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"time"
+)
+
+// issuerAttempts how many times a status change is sent to the card issuer before giving up
+var issuerAttempts = 3
+
+// issuerRetryDelay wait before the first retry, doubled on every retry after it
+var issuerRetryDelay = 500 * time.Millisecond
+
+// syncIssuerStatus sets the status of the card at its issuer, transient failures are retried with backoff until the
+// context is done
+func syncIssuerStatus(ctx context.Context, issuers ports.ICardIssuers, card *domain.Card, status domain.CardStatus, reason string) error {
+	issuer, err := issuers.ForCard(card)
+	if err != nil {
+		return err
+	}
+
+	delay := issuerRetryDelay
+
+	for attempt := 1; attempt <= issuerAttempts; attempt++ {
+		err = setIssuerStatus(ctx, issuer, card, status, reason)
+		if err == nil || !errors.Is(err, domain.ErrIssuerUnavailable) {
+			return err
+		}
+
+		if attempt < issuerAttempts {
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+			delay *= 2
+		}
+	}
+	return err
+}
+
+func setIssuerStatus(ctx context.Context, issuer ports.ICardIssuer, card *domain.Card, status domain.CardStatus, reason string) error {
+	switch status {
+	case domain.CardActive:
+		return issuer.Freeze(ctx, card, false)
+	case domain.CardInactive:
+		return issuer.Freeze(ctx, card, true)
+	case domain.CardCanceled:
+		return issuer.Cancel(ctx, card, reason)
+	}
+	return fmt.Errorf("status %v can not be set on the issuer", status)
+}
+
+// getIssuerStatus reads the status the issuer holds for the card
+func getIssuerStatus(ctx context.Context, issuers ports.ICardIssuers, card *domain.Card) (domain.CardStatus, error) {
+	issuer, err := issuers.ForCard(card)
+	if err != nil {
+		return "", err
+	}
+
+	issued, err := issuer.FetchCard(ctx, card)
+	if err != nil {
+		return "", err
+	}
+	return domain.ParseCardStatus(issued.Status), nil
+}
+
+// lockCardForSystem locks a card here right away and freezes it at the issuer in the background, authorizations
+// can not wait on issuer retries and the lock reconciliation catches a freeze that never made it
+func lockCardForSystem(issuers ports.ICardIssuers, cardRepository ports.ICardRepository, card *domain.Card, actor string, reason string, logger *log.Logger) error {
+	if err := transitionCard(cardRepository, card, domain.CardLocked, actor, reason); err != nil {
+		return err
+	}
+
+	// the freeze outlives the request that triggered it, so it does not run on the request context
+	go func(card domain.Card) {
+		if err := syncIssuerStatus(context.Background(), issuers, &card, domain.CardLocked.PartnerStatus(), reason); err != nil {
+			logger.Error(err)
+		}
+	}(*card)
+
+	return nil
+}
+
+// ReconcileCardLocks compares the status of our usable and frozen cards with the issuer. Our status wins and is
+// sent again, except for cards the issuer canceled which can not come back and are canceled here too. A card the
+// issuer froze while we have it active is left frozen, the issuer may have frozen it for fraud or compliance, and
+// the company is alerted instead.
+func (cs *cardService) ReconcileCardLocks(ctx context.Context) (*domain.LockReconciliation, error) {
+	cards, err := cs.CardRepository.GetByStatus([]string{string(domain.CardActive), string(domain.CardInactive), string(domain.CardLocked)})
+	if err != nil {
+		cs.logger.Error(err)
+		return nil, err
+	}
+
+	report := &domain.LockReconciliation{Mismatches: []domain.LockMismatch{}}
+
+	for i := range cards {
+		card := &cards[i]
+		report.Checked++
+
+		expected := domain.ParseCardStatus(card.Status).PartnerStatus()
+		actual, err := getIssuerStatus(ctx, cs.CardIssuers, card)
+		if err != nil {
+			cs.logger.Error(err)
+			report.Failed++
+			continue
+		}
+
+		if actual == expected {
+			continue
+		}
+
+		mismatch := domain.LockMismatch{Card: card.ID, Local: domain.ParseCardStatus(card.Status), Issuer: actual}
+
+		if expected == domain.CardActive && actual == domain.CardInactive {
+			cs.logger.Warnf("card %v is active here but frozen at the issuer, it is left frozen", card.ID)
+			publish(cs.EventPublisher, card.Company, domain.CardFrozenByIssuerEvent, card)
+			mismatch.Resolution = "alerted"
+			report.Alerted++
+			report.Mismatches = append(report.Mismatches, mismatch)
+			continue
+		}
+
+		if actual == domain.CardCanceled {
+			err = transitionCard(cs.CardRepository, card, domain.CardCanceled, "system:reconciliation", "canceled at the issuer")
+			mismatch.Resolution = "canceled"
+		} else {
+			err = syncIssuerStatus(ctx, cs.CardIssuers, card, expected, "lock reconciliation")
+			mismatch.Resolution = "resent"
+		}
+
+		if err != nil {
+			cs.logger.Error(err)
+			mismatch.Resolution = "failed"
+			report.Failed++
+		} else {
+			report.Repaired++
+		}
+
+		report.Mismatches = append(report.Mismatches, mismatch)
+	}
+
+	return report, nil
+}
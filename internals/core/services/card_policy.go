@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"core_business/internals/common"
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"core_business/pkg/utils"
+	"errors"
+	"github.com/satori/go.uuid"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+type cardPolicyService struct {
+	CardPolicyRepository ports.ICardPolicyRepository
+	CompanyRepository    ports.ICompanyRepository
+	CardRepository       ports.ICardRepository
+	CardIssuers          ports.ICardIssuers
+	logger               *log.Logger
+}
+
+// NewCardPolicyService function create a new instance for service
+func NewCardPolicyService(pr ports.ICardPolicyRepository, cmr ports.ICompanyRepository,
+	cr ports.ICardRepository, ci ports.ICardIssuers, l *log.Logger) ports.ICardPolicyService {
+	return &cardPolicyService{
+		CardPolicyRepository: pr,
+		CompanyRepository:    cmr,
+		CardRepository:       cr,
+		CardIssuers:          ci,
+		logger:               l,
+	}
+}
+
+func (ps *cardPolicyService) GetCardPolicyByID(id string) (*domain.CardPolicy, error) {
+	policy, err := ps.CardPolicyRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (ps *cardPolicyService) GetCardPolicyByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error) {
+	policies, err := ps.CardPolicyRepository.GetCardPolicyByCompanyID(id, pagination)
+	if err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// CreateCardPolicy adds a named set of spending controls to the company, starting from a preset when one is named
+func (ps *cardPolicyService) CreateCardPolicy(body common.CreateCardPolicyRequest) (*domain.CardPolicy, error) {
+	company, err := ps.CompanyRepository.GetByID(body.Company.String())
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &domain.CardPolicy{}
+	if body.Preset != "" {
+		preset, ok := domain.CardPolicyPresets[strings.ToLower(strings.TrimSpace(body.Preset))]
+		if !ok {
+			return nil, domain.ErrUnknownCardPolicyPreset
+		}
+		*policy = preset
+	}
+
+	policy.Company = company.ID
+	if name := strings.TrimSpace(body.Name); name != "" {
+		policy.Name = name
+	}
+
+	if body.Description != "" {
+		policy.Description = body.Description
+	}
+
+	if body.SpendingControls != nil {
+		policy.SpendingControls = spendingControls(*body.SpendingControls)
+	}
+
+	if body.ValidityDays != nil {
+		policy.ValidityDays = *body.ValidityDays
+	}
+
+	if body.Schedule != nil {
+		if policy.Schedule, err = cardSchedule(*body.Schedule); err != nil {
+			return nil, err
+		}
+	}
+
+	if policy.Name == "" {
+		return nil, errors.New("card policy name is required")
+	}
+
+	if err = ps.ensureNameFree(company.ID.String(), policy.Name, nil); err != nil {
+		return nil, err
+	}
+
+	if err = ps.CardPolicyRepository.Persist(policy); err != nil {
+		ps.logger.Error(err)
+		return nil, err
+	}
+	return policy, nil
+}
+
+// UpdateCardPolicy changes the policy for the cards issued on it from now on, with propagate the spending controls
+// and schedule of the cards already on it are changed too. The validity period of cards already issued does not change.
+func (ps *cardPolicyService) UpdateCardPolicy(ctx context.Context, id string, body common.UpdateCardPolicyRequest) (*domain.CardPolicyUpdate, error) {
+	policy, err := ps.CardPolicyRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if body.Name != nil {
+		name := strings.TrimSpace(*body.Name)
+		if name == "" {
+			return nil, errors.New("card policy name is required")
+		}
+
+		if err = ps.ensureNameFree(policy.Company.String(), name, &policy.ID); err != nil {
+			return nil, err
+		}
+		policy.Name = name
+	}
+
+	if body.Description != nil {
+		policy.Description = *body.Description
+	}
+
+	if body.SpendingControls != nil {
+		policy.SpendingControls = spendingControls(*body.SpendingControls)
+	}
+
+	if body.ValidityDays != nil {
+		policy.ValidityDays = *body.ValidityDays
+	}
+
+	if body.Schedule != nil {
+		if policy.Schedule, err = cardSchedule(*body.Schedule); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = ps.CardPolicyRepository.Persist(policy); err != nil {
+		ps.logger.Error(err)
+		return nil, err
+	}
+
+	update := &domain.CardPolicyUpdate{Policy: policy}
+	if body.Propagate {
+		if update.Propagation, err = ps.propagate(ctx, policy); err != nil {
+			return nil, err
+		}
+	}
+	return update, nil
+}
+
+// DeleteCardPolicy removes the policy, the cards issued on it keep their spending controls
+func (ps *cardPolicyService) DeleteCardPolicy(id string) error {
+	if _, err := ps.CardPolicyRepository.GetByID(id); err != nil {
+		return err
+	}
+
+	if err := ps.CardPolicyRepository.Delete(id); err != nil {
+		ps.logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// propagate applies the spending controls and schedule of the policy to the cards on it, the controls at the issuer
+// first, a card the issuer refuses keeps its controls and is reported
+func (ps *cardPolicyService) propagate(ctx context.Context, policy *domain.CardPolicy) (*domain.CardPolicyPropagation, error) {
+	statuses := []string{string(domain.CardPending), string(domain.CardActive), string(domain.CardInactive), string(domain.CardLocked)}
+	cards, err := ps.CardRepository.GetByPolicy(policy.ID.String(), statuses)
+	if err != nil {
+		ps.logger.Error(err)
+		return nil, err
+	}
+
+	propagation := &domain.CardPolicyPropagation{Cards: len(cards), Failed: []uuid.UUID{}}
+	for i := range cards {
+		card := &cards[i]
+		previous, schedule := card.SpendingControls, card.Schedule
+		card.SpendingControls = policy.SpendingControls
+		card.Schedule = policy.Schedule
+
+		if err = ps.updateControls(ctx, card); err != nil {
+			ps.logger.Errorf("card %v: %v", card.ID, err)
+			card.SpendingControls, card.Schedule = previous, schedule
+			propagation.Failed = append(propagation.Failed, card.ID)
+			continue
+		}
+		propagation.Updated++
+	}
+	return propagation, nil
+}
+
+func (ps *cardPolicyService) updateControls(ctx context.Context, card *domain.Card) error {
+	issuer, err := ps.CardIssuers.ForCard(card)
+	if err != nil {
+		return err
+	}
+
+	if err = issuer.UpdateControls(ctx, card); err != nil {
+		return err
+	}
+	return ps.CardRepository.Persist(card)
+}
+
+// ensureNameFree fails when another card policy of the company has the name
+func (ps *cardPolicyService) ensureNameFree(company string, name string, policy *uuid.UUID) error {
+	existing, err := ps.CardPolicyRepository.GetByName(company, name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if policy != nil && existing.ID == *policy {
+		return nil
+	}
+	return domain.ErrCardPolicyExists
+}
+
+// ExpireCardsPastValidity expires the cards whose policy validity period ended, the issuer freezes them first
+func (cs *cardService) ExpireCardsPastValidity(ctx context.Context) (int, error) {
+	statuses := []string{string(domain.CardActive), string(domain.CardInactive), string(domain.CardLocked)}
+	cards, err := cs.CardRepository.GetPastValidity(statuses, time.Now())
+	if err != nil {
+		cs.logger.Error(err)
+		return 0, err
+	}
+
+	expired := 0
+	reason := "card policy validity period ended"
+	for i := range cards {
+		card := &cards[i]
+
+		if domain.ParseCardStatus(card.Status) != domain.CardInactive {
+			if err = syncIssuerStatus(ctx, cs.CardIssuers, card, domain.CardInactive, reason); err != nil {
+				cs.logger.Errorf("card %v: %v", card.ID, err)
+				continue
+			}
+		}
+
+		if err = transitionCard(cs.CardRepository, card, domain.CardExpired, "system", reason); err != nil {
+			cs.logger.Errorf("card %v: %v", card.ID, err)
+			continue
+		}
+		expired++
+	}
+	return expired, nil
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"core_business/internals/common"
+	"core_business/internals/core/domain"
+	"github.com/satori/go.uuid"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+// RenewExpiringCards tells companies about the cards expiring in the next days, replaces them when the company
+// renews cards automatically and moves the cards past their expiry to expired
+func (cs *cardService) RenewExpiringCards(ctx context.Context, days int) (*domain.CardRenewalRun, error) {
+	if days <= 0 {
+		days = domain.DefaultCardExpiryNoticeDays
+	}
+
+	now := time.Now()
+	horizon := now.AddDate(0, 0, days)
+
+	statuses := []string{string(domain.CardActive), string(domain.CardInactive), string(domain.CardLocked)}
+	cards, err := cs.CardRepository.GetByExpiryYear(statuses, horizon.Year())
+	if err != nil {
+		cs.logger.Error(err)
+		return nil, err
+	}
+
+	run := &domain.CardRenewalRun{}
+	companies := map[uuid.UUID]*domain.Company{}
+
+	for i := range cards {
+		card := &cards[i]
+
+		expiresAt, err := card.ExpiresAt()
+		if err != nil {
+			cs.logger.Errorf("card %v: %v", card.ID, err)
+			continue
+		}
+
+		if expiresAt.After(horizon) {
+			continue
+		}
+		run.Checked++
+
+		if card.ExpiryNotifiedAt == nil && expiresAt.After(now) {
+			notifiedAt := now
+			card.ExpiryNotifiedAt = &notifiedAt
+			if err = cs.CardRepository.Persist(card); err != nil {
+				cs.logger.Error(err)
+				run.Failed++
+				continue
+			}
+
+			publish(cs.EventPublisher, card.Company, domain.CardExpiringEvent, card)
+			run.Notified++
+		}
+
+		if card.RenewedBy == nil && renewable(card) {
+			company, ok := companies[card.Company]
+			if !ok {
+				if company, err = cs.CompanyRepository.GetByID(card.Company.String()); err != nil {
+					cs.logger.Error(err)
+					run.Failed++
+					continue
+				}
+				companies[card.Company] = company
+			}
+
+			if company.CardAutoRenewal {
+				if err = cs.renewCard(ctx, card); err != nil {
+					cs.logger.Errorf("renewing card %v: %v", card.ID, err)
+					run.Failed++
+				} else {
+					run.Renewed++
+				}
+			}
+		}
+
+		if !expiresAt.After(now) {
+			if err = transitionCard(cs.CardRepository, card, domain.CardExpired, "system:expiry", "card expired"); err != nil {
+				cs.logger.Error(err)
+				run.Failed++
+				continue
+			}
+			run.Expired++
+		}
+	}
+
+	return run, nil
+}
+
+func (cs *cardService) GetCardRecurringMerchants(id string) ([]domain.CardRecurringMerchant, error) {
+	if _, err := cs.CardRepository.GetByID(id); err != nil {
+		return nil, err
+	}
+
+	merchants, err := cs.CardRepository.GetRecurringMerchants(id)
+	if err != nil {
+		return nil, err
+	}
+	return merchants, nil
+}
+
+// renewCard issues a replacement to the same cardholder with the same spending controls, the merchants that
+// billed the card month after month are kept on the replacement
+func (cs *cardService) renewCard(ctx context.Context, card *domain.Card) error {
+	customer := card.Customer
+	renewal, err := cs.CreateCard(ctx, common.CreateCardRequest{
+		Name:             card.Name,
+		Company:          card.Company,
+		Type:             card.Type,
+		Brand:            card.Brand,
+		Status:           string(domain.ParseCardStatus(card.Status)),
+		Summary:          card.Summary,
+		Customer:         &customer,
+		SpendingControls: requestControls(card.SpendingControls),
+		ShippingAddress:  cs.previousShippingAddress(card),
+		Mode:             string(card.Mode),
+	})
+	if err != nil {
+		return err
+	}
+
+	var merchants []domain.CardRecurringMerchant
+	since := time.Now().AddDate(0, -domain.RecurringMerchantMonths, 0)
+	charges, err := cs.TransactionRepository.GetMerchantChargesByCardID(card.ID.String(), since)
+	if err != nil {
+		// the replacement is issued already, it is linked even without its merchants
+		cs.logger.Error(err)
+	} else {
+		merchants = recurringMerchants(charges, card.ID, renewal.ID)
+	}
+
+	renewal.Renews = &card.ID
+	card.RenewedBy = &renewal.ID
+
+	// a subscription card keeps paying the merchant it was locked to
+	renewal.LockedMerchantID = card.LockedMerchantID
+	renewal.LockedMerchant = card.LockedMerchant
+
+	// the replacement stays on the policy and keeps its validity period, which does not start again
+	renewal.Policy = card.Policy
+	renewal.ValidUntil = card.ValidUntil
+	renewal.Schedule = card.Schedule
+
+	err = inTransaction(cs.DB, func(txx *gorm.DB) error {
+		cardRepository := cs.CardRepository.WithTx(txx)
+		if err := cardRepository.Persist(renewal); err != nil {
+			return err
+		}
+		if err := cardRepository.Persist(card); err != nil {
+			return err
+		}
+		return cardRepository.PersistRecurringMerchants(merchants)
+	})
+	if err != nil {
+		return err
+	}
+
+	publish(cs.EventPublisher, card.Company, domain.CardRenewedEvent, domain.CardRenewal{
+		PreviousCard:       card.ID,
+		Card:               renewal,
+		RecurringMerchants: merchants,
+	})
+	return nil
+}
+
+// previousShippingAddress where the card being renewed was delivered, a physical replacement is sent there too
+func (cs *cardService) previousShippingAddress(card *domain.Card) *common.ShippingAddress {
+	fulfillment, err := cs.FulfillmentRepository.GetByCardID(card.ID.String())
+	if err != nil {
+		return nil
+	}
+
+	address := fulfillment.ShippingAddress
+	return &common.ShippingAddress{
+		Recipient:  address.Recipient,
+		Phone:      address.Phone,
+		Line1:      address.Line1,
+		Line2:      address.Line2,
+		City:       address.City,
+		State:      address.State,
+		PostalCode: address.PostalCode,
+		Country:    address.Country,
+	}
+}
+
+// renewable reports whether a card is replaced before it expires, locked, lost and stolen cards are not
+func renewable(card *domain.Card) bool {
+	status := domain.ParseCardStatus(card.Status)
+	return !card.Lock && (status == domain.CardActive || status == domain.CardInactive)
+}
+
+// recurringMerchants the merchants of the charges that billed the previous card in more than one month
+func recurringMerchants(charges []domain.Transaction, previous uuid.UUID, card uuid.UUID) []domain.CardRecurringMerchant {
+	var order []string
+	merchants := map[string]*domain.CardRecurringMerchant{}
+	months := map[string]map[string]bool{}
+
+	for _, charge := range charges {
+		name := strings.TrimSpace(charge.MerchantName)
+		key := strings.ToLower(name)
+
+		merchant, ok := merchants[key]
+		if !ok {
+			merchant = &domain.CardRecurringMerchant{Card: card, PreviousCard: previous, MerchantName: name}
+			merchants[key] = merchant
+			months[key] = map[string]bool{}
+			order = append(order, key)
+		}
+
+		merchant.MerchantCategory = charge.MerchantCategory
+		merchant.MerchantCountry = charge.MerchantCountry
+		merchant.LastAmount = charge.Debit
+		merchant.LastChargedAt = charge.CreatedAt
+		months[key][charge.CreatedAt.Format("2006-01")] = true
+	}
+
+	recurring := []domain.CardRecurringMerchant{}
+	for _, key := range order {
+		if len(months[key]) < 2 {
+			continue
+		}
+
+		merchant := *merchants[key]
+		merchant.Months = len(months[key])
+		recurring = append(recurring, merchant)
+	}
+	return recurring
+}
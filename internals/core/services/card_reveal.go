@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"core_business/internals/common"
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	log "github.com/sirupsen/logrus"
+	"time"
+)
+
+type cardRevealService struct {
+	CardRevealRepository ports.ICardRevealRepository
+	CardRepository       ports.ICardRepository
+	CardIssuers          ports.ICardIssuers
+	logger               *log.Logger
+}
+
+// NewCardRevealService function create a new instance for service
+func NewCardRevealService(rr ports.ICardRevealRepository, cr ports.ICardRepository,
+	ci ports.ICardIssuers, l *log.Logger) ports.ICardRevealService {
+	return &cardRevealService{
+		CardRevealRepository: rr,
+		CardRepository:       cr,
+		CardIssuers:          ci,
+		logger:               l,
+	}
+}
+
+// CreateRevealToken issues a single use token to reveal the card to the actor, only its hash is stored
+func (rs *cardRevealService) CreateRevealToken(id string, actor string, ip string) (*domain.RevealToken, error) {
+	card, err := rs.CardRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !card.Usable() {
+		return nil, domain.ErrCardNotRevealable
+	}
+
+	secret := make([]byte, 32)
+	if _, err = rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	token := hex.EncodeToString(secret)
+	expiresAt := time.Now().Add(domain.CardRevealTokenTTL)
+
+	err = rs.CardRevealRepository.PersistToken(&domain.CardRevealToken{
+		Card:      card.ID,
+		TokenHash: hashRevealToken(token),
+		Actor:     actor,
+		IP:        ip,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		rs.logger.Error(err)
+		return nil, err
+	}
+
+	return &domain.RevealToken{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// RevealCard uses the token and reads the card details from the issuer, the details are only returned
+// once the reveal is logged and are never stored or logged themselves. Only the actor the token was issued
+// to can use it
+func (rs *cardRevealService) RevealCard(ctx context.Context, actor string, body common.RevealCardRequest, ip string) (*domain.CardSecrets, error) {
+	token, err := rs.CardRevealRepository.UseToken(hashRevealToken(body.Token), time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if token.Actor != actor {
+		return nil, domain.ErrInvalidRevealToken
+	}
+
+	card, err := rs.CardRepository.GetByID(token.Card.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if !card.Usable() {
+		return nil, domain.ErrCardNotRevealable
+	}
+
+	issuer, err := rs.CardIssuers.ForCard(card)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets, err := issuer.RevealCard(ctx, card)
+	if err != nil {
+		return nil, err
+	}
+
+	err = rs.CardRevealRepository.PersistReveal(&domain.CardReveal{
+		Card:    card.ID,
+		Company: card.Company,
+		Token:   token.ID,
+		Actor:   actor,
+		IP:      ip,
+	})
+	if err != nil {
+		rs.logger.Error(err)
+		return nil, err
+	}
+
+	return secrets, nil
+}
+
+func (rs *cardRevealService) GetCardReveals(id string) ([]domain.CardReveal, error) {
+	if _, err := rs.CardRepository.GetByID(id); err != nil {
+		return nil, err
+	}
+
+	reveals, err := rs.CardRevealRepository.GetRevealsByCardID(id)
+	if err != nil {
+		return nil, err
+	}
+	return reveals, nil
+}
+
+func hashRevealToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
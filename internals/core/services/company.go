@@ -11,6 +11,7 @@ import (
 	"fmt"
 	uuid "github.com/satori/go.uuid"
 	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 	"strings"
 	"time"
 )
@@ -23,6 +24,9 @@ type companyService struct {
 	CompanyProfileRepository      ports.ICompanyProfileRepository
 	WalletRepository              ports.IWalletRepository
 	CreditLimitIncreaseRepository ports.ICreditLimitRequestRepository
+	EventPublisher                ports.IEventPublisher
+	OutboxRepository              ports.IOutboxRepository
+	DB                            *gorm.DB
 	logger                        *log.Logger
 }
 
@@ -31,12 +35,18 @@ func NewCompanyService(cr ports.ICompanyRepository,
 	cpr ports.ICompanyProfileRepository,
 	wr ports.IWalletRepository,
 	cli ports.ICreditLimitRequestRepository,
+	ep ports.IEventPublisher,
+	or ports.IOutboxRepository,
+	db *gorm.DB,
 	l *log.Logger) ports.ICompanyService {
 	return &companyService{
 		CompanyRepository:             cr,
 		CompanyProfileRepository:      cpr,
 		WalletRepository:              wr,
 		CreditLimitIncreaseRepository: cli,
+		EventPublisher:                ep,
+		OutboxRepository:              or,
+		DB:                            db,
 		logger:                        l,
 	}
 }
@@ -78,7 +88,12 @@ func (c *companyService) CreateCompany(company *domain.Company) error {
 		return errors.New("already exist")
 	}
 
-	err = c.CompanyRepository.Persist(company)
+	err = inTransaction(c.DB, func(txx *gorm.DB) error {
+		if err := c.CompanyRepository.WithTx(txx).Persist(company); err != nil {
+			return err
+		}
+		return recordEvent(c.OutboxRepository.WithTx(txx), domain.CompanyCreated, "company", company.ID, company.ID, company)
+	})
 
 	if err != nil {
 		c.logger.Error(err)
@@ -123,6 +138,14 @@ func (c *companyService) UpdateCompany(params common.GetByIDRequest, body common
 		company.FundingSource = *body.FundingSource
 	}
 
+	if body.CardIssuer != nil {
+		company.CardIssuer = *body.CardIssuer
+	}
+
+	if body.CardAutoRenewal != nil {
+		company.CardAutoRenewal = *body.CardAutoRenewal
+	}
+
 	err = c.CompanyRepository.Persist(company)
 
 	if err != nil {
@@ -213,11 +236,13 @@ func (c *companyService) UnderWriting(id string) (*common.UnderWritingResponse,
 
 	wallet[0].CreditLimit = utils.ToMinorUnit(creditLimit)
 
-	err = c.WalletRepository.Persist(&wallet[0])
+	err = c.changeCreditLimit(&wallet[0])
 	if err != nil {
 		return nil, err
 	}
 
+	publish(c.EventPublisher, wallet[0].Company, domain.CreditLimitChangedEvent, wallet[0])
+
 	return &common.UnderWritingResponse{
 		CreditLimit: creditLimit,
 		TotalPoint:  totalPoint,
@@ -480,7 +505,11 @@ func (c *companyService) UpdateRequestCreditLimitIncrease(params common.GetByIDR
 
 	if body.Approve == true {
 		wallet.CreditLimit = creditLimitRequest.DesiredCreditLimit
-		c.WalletRepository.Persist(wallet)
+		if err = c.changeCreditLimit(wallet); err != nil {
+			c.logger.Error(err)
+			return err
+		}
+		publish(c.EventPublisher, wallet.Company, domain.CreditLimitChangedEvent, wallet)
 		c.CreditLimitIncreaseRepository.Delete(creditLimitRequest.ID.String())
 		return nil
 	}
@@ -489,3 +518,15 @@ func (c *companyService) UpdateRequestCreditLimitIncrease(params common.GetByIDR
 	return errors.New("request was rejected please try again or contact support")
 
 }
+
+// changeCreditLimit persists the new credit limit of a wallet together with its CreditLimitChanged event
+func (c *companyService) changeCreditLimit(wallet *domain.Wallet) error {
+	return inTransaction(c.DB, func(txx *gorm.DB) error {
+		if err := c.WalletRepository.WithTx(txx).Persist(wallet); err != nil {
+			return err
+		}
+		return recordEvent(c.OutboxRepository.WithTx(txx), domain.CreditLimitChanged, "wallet", wallet.ID, wallet.Company, map[string]interface{}{
+			"credit_limit": wallet.CreditLimit,
+		})
+	})
+}
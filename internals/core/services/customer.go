@@ -0,0 +1,126 @@
+package services
+
+import (
+	"core_business/internals/common"
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"core_business/pkg/utils"
+	"errors"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"strings"
+)
+
+type customerService struct {
+	CustomerRepository ports.ICustomerRepository
+	CompanyRepository  ports.ICompanyRepository
+	logger             *log.Logger
+}
+
+// NewCustomerService function create a new instance for service
+func NewCustomerService(cr ports.ICustomerRepository, cmr ports.ICompanyRepository, l *log.Logger) ports.ICustomerService {
+	return &customerService{
+		CustomerRepository: cr,
+		CompanyRepository:  cmr,
+		logger:             l,
+	}
+}
+
+func (cs *customerService) GetCustomerByID(id string) (*domain.Customer, error) {
+	customer, err := cs.CustomerRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return customer, nil
+}
+
+func (cs *customerService) GetCustomerByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error) {
+	customers, err := cs.CustomerRepository.GetCustomerByCompanyID(id, pagination)
+	if err != nil {
+		return nil, err
+	}
+	return customers, nil
+}
+
+// CreateCustomer adds a cardholder to the company, the issuer only gets them with their first card
+func (cs *customerService) CreateCustomer(body common.CreateCustomerRequest) (*domain.Customer, error) {
+	company, err := cs.CompanyRepository.GetByID(body.Company.String())
+	if err != nil {
+		return nil, err
+	}
+
+	email := strings.ToLower(strings.TrimSpace(body.Email))
+	if err = cs.ensureEmailFree(company.ID.String(), email, nil); err != nil {
+		return nil, err
+	}
+
+	customer := &domain.Customer{
+		Company:   company.ID,
+		User:      body.User,
+		FirstName: body.FirstName,
+		LastName:  body.LastName,
+		Email:     email,
+		Phone:     body.Phone,
+	}
+
+	if err = cs.CustomerRepository.Persist(customer); err != nil {
+		cs.logger.Error(err)
+		return nil, err
+	}
+	return customer, nil
+}
+
+// UpdateCustomer changes the details of the cardholder, the issuer keeps the details it was created with
+func (cs *customerService) UpdateCustomer(id string, body common.UpdateCustomerRequest) (*domain.Customer, error) {
+	customer, err := cs.CustomerRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if body.Email != nil {
+		email := strings.ToLower(strings.TrimSpace(*body.Email))
+		if err = cs.ensureEmailFree(customer.Company.String(), email, customer); err != nil {
+			return nil, err
+		}
+		customer.Email = email
+	}
+
+	if body.User != nil {
+		customer.User = *body.User
+	}
+
+	if body.FirstName != nil {
+		customer.FirstName = *body.FirstName
+	}
+
+	if body.LastName != nil {
+		customer.LastName = *body.LastName
+	}
+
+	if body.Phone != nil {
+		customer.Phone = *body.Phone
+	}
+
+	if err = cs.CustomerRepository.Persist(customer); err != nil {
+		cs.logger.Error(err)
+		return nil, err
+	}
+	return customer, nil
+}
+
+// ensureEmailFree fails when another cardholder of the company has the email
+func (cs *customerService) ensureEmailFree(company string, email string, customer *domain.Customer) error {
+	existing, err := cs.CustomerRepository.GetByEmail(company, email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if customer != nil && existing.ID == customer.ID {
+		return nil
+	}
+	return domain.ErrCustomerExists
+}
@@ -0,0 +1,308 @@
+package services
+
+import (
+	"core_business/internals/common"
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"core_business/pkg/utils"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+type disputeService struct {
+	DisputeRepository     ports.IDisputeRepository
+	TransactionRepository ports.ITransactionRepository
+	WalletRepository      ports.IWalletRepository
+	WalletService         ports.IWalletService
+	DB                    *gorm.DB
+	logger                *log.Logger
+}
+
+// NewDisputeService function create a new instance for service
+func NewDisputeService(dr ports.IDisputeRepository, tr ports.ITransactionRepository,
+	wr ports.IWalletRepository, ws ports.IWalletService, db *gorm.DB, l *log.Logger) ports.IDisputeService {
+	return &disputeService{
+		DisputeRepository:     dr,
+		TransactionRepository: tr,
+		WalletRepository:      wr,
+		WalletService:         ws,
+		DB:                    db,
+		logger:                l,
+	}
+}
+
+func (ds *disputeService) GetDisputeByID(id string) (*domain.Dispute, error) {
+	dispute, err := ds.DisputeRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return dispute, nil
+}
+
+func (ds *disputeService) GetDisputeByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error) {
+	disputes, err := ds.DisputeRepository.GetDisputeByCompanyID(id, pagination)
+	if err != nil {
+		ds.logger.Error(err)
+		return nil, err
+	}
+	return disputes, nil
+}
+
+// OpenDispute opens a dispute on a card charge and posts a provisional credit to the wallet
+func (ds *disputeService) OpenDispute(body common.OpenDisputeRequest) (*domain.Dispute, error) {
+	reason := domain.DisputeReason(strings.ToUpper(strings.TrimSpace(body.Reason)))
+	if !validDisputeReason(reason) {
+		return nil, fmt.Errorf("invalid dispute reason %v", body.Reason)
+	}
+
+	transaction, err := ds.TransactionRepository.GetByID(body.Transaction.String())
+	if err != nil {
+		return nil, err
+	}
+
+	wallet, err := ds.WalletRepository.GetByID(transaction.Wallet.String())
+	if err != nil {
+		return nil, err
+	}
+
+	dispute := &domain.Dispute{
+		Company:     transaction.Company,
+		Wallet:      transaction.Wallet,
+		Card:        transaction.Card,
+		Transaction: transaction.ID,
+		Reason:      reason,
+		Explanation: body.Explanation,
+		Status:      domain.DisputeOpened,
+	}
+
+	for _, evidence := range body.Evidence {
+		dispute.Evidence = append(dispute.Evidence, domain.DisputeEvidence{
+			URL:         evidence.URL,
+			Description: evidence.Description,
+		})
+	}
+
+	// the transaction row stays locked until the dispute is saved, a concurrent open waits and finds this dispute.
+	// The dispute, its provisional credit row and the wallet credit are kept or rolled back together
+	err = inTransaction(ds.DB, func(txx *gorm.DB) error {
+		transactionRepository := ds.TransactionRepository.WithTx(txx)
+		disputeRepository := ds.DisputeRepository.WithTx(txx)
+
+		if _, err := transactionRepository.GetByIDForUpdate(transaction.ID.String()); err != nil {
+			return err
+		}
+
+		amount, err := disputableAmount(transactionRepository, disputeRepository, transaction)
+		if err != nil {
+			return err
+		}
+
+		if body.Amount != nil {
+			if *body.Amount <= 0 || *body.Amount > amount {
+				return errors.New("dispute amount must be between zero and the undisputed transaction amount")
+			}
+			amount = *body.Amount
+		}
+		dispute.Amount = amount
+
+		if err := disputeRepository.Persist(dispute); err != nil {
+			return err
+		}
+
+		if _, err := ds.WalletService.WithTx(txx).CreditWallet(wallet, utils.ToMinorUnit(amount)); err != nil {
+			return err
+		}
+
+		credit := disputeTransaction(transaction, dispute, domain.CreditEntry,
+			fmt.Sprintf("%v provisionally credited for disputed transaction", amount))
+
+		if err := transactionRepository.Persist(credit); err != nil {
+			return err
+		}
+
+		dispute.ProvisionalCredit = credit.ID.String()
+		return disputeRepository.Persist(dispute)
+	})
+
+	if err != nil {
+		ds.logger.Error(err)
+		return nil, err
+	}
+
+	return dispute, nil
+}
+
+func (ds *disputeService) AddDisputeEvidence(id string, body common.DisputeEvidenceRequest) (*domain.Dispute, error) {
+	dispute, err := ds.DisputeRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if dispute.Status != domain.DisputeOpened && dispute.Status != domain.DisputeSubmitted {
+		return nil, fmt.Errorf("cannot add evidence to a %v dispute", strings.ToLower(string(dispute.Status)))
+	}
+
+	evidence := &domain.DisputeEvidence{
+		Dispute:     dispute.ID,
+		URL:         body.URL,
+		Description: body.Description,
+	}
+
+	if err = ds.DisputeRepository.PersistEvidence(evidence); err != nil {
+		ds.logger.Error(err)
+		return nil, err
+	}
+
+	dispute.Evidence = append(dispute.Evidence, *evidence)
+	return dispute, nil
+}
+
+// ChangeDisputeStatus moves a dispute through its workflow, a lost or withdrawn dispute reverses the provisional credit
+func (ds *disputeService) ChangeDisputeStatus(id string, body common.ChangeDisputeStatusRequest) (*domain.Dispute, error) {
+	dispute, err := ds.DisputeRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	status := domain.DisputeStatus(strings.ToUpper(strings.TrimSpace(body.Status)))
+	if !canTransitionDispute(dispute.Status, status) {
+		return nil, fmt.Errorf("cannot move dispute from %v to %v", dispute.Status, status)
+	}
+
+	dispute.Status = status
+	if body.PartnerReference != "" {
+		dispute.PartnerReference = body.PartnerReference
+	}
+
+	if _, ok := domain.DisputeTransitions[status]; !ok {
+		now := time.Now()
+		dispute.ResolvedAt = &now
+	}
+
+	if status != domain.DisputeLost && status != domain.DisputeWithdrawn {
+		if err = ds.DisputeRepository.Persist(dispute); err != nil {
+			ds.logger.Error(err)
+			return nil, err
+		}
+		return dispute, nil
+	}
+
+	transaction, err := ds.TransactionRepository.GetByID(dispute.Transaction.String())
+	if err != nil {
+		return nil, err
+	}
+
+	wallet, err := ds.WalletRepository.GetByID(dispute.Wallet.String())
+	if err != nil {
+		return nil, err
+	}
+
+	// the reversal of the provisional credit and the resolution are kept or rolled back together
+	err = inTransaction(ds.DB, func(txx *gorm.DB) error {
+		// the loss is booked even without headroom, the credit was already spent and the dispute must not stay open
+		if _, err := ds.WalletService.WithTx(txx).OverdrawWallet(wallet, utils.ToMinorUnit(dispute.Amount)); err != nil {
+			return err
+		}
+
+		reversal := disputeTransaction(transaction, dispute, domain.DebitEntry,
+			fmt.Sprintf("%v provisional credit reversed for %v dispute", dispute.Amount, strings.ToLower(string(status))))
+
+		if err := ds.TransactionRepository.WithTx(txx).Persist(reversal); err != nil {
+			return err
+		}
+
+		return ds.DisputeRepository.WithTx(txx).Persist(dispute)
+	})
+
+	if err != nil {
+		ds.logger.Error(err)
+		return nil, err
+	}
+
+	return dispute, nil
+}
+
+// disputableAmount what of a card charge can still be disputed, the charge less what the merchant refunded. A voided
+// charge, or one with an open or won dispute, was already credited back and can not be disputed
+func disputableAmount(transactionRepository ports.ITransactionRepository, disputeRepository ports.IDisputeRepository, transaction *domain.Transaction) (float64, error) {
+	if transaction.Type != domain.WithdrawalType || transaction.Entry != domain.DebitEntry ||
+		transaction.Status != domain.SuccessStatus {
+		return 0, errors.New("only successful card charges can be disputed")
+	}
+
+	if transaction.VoidedAt != nil {
+		return 0, errors.New("a voided transaction can not be disputed")
+	}
+
+	disputes, err := disputeRepository.GetByTransaction(transaction.ID.String())
+	if err != nil {
+		return 0, err
+	}
+
+	// lost and withdrawn disputes had their provisional credit reversed, they leave the charge disputable
+	for _, d := range disputes {
+		switch d.Status {
+		case domain.DisputeOpened, domain.DisputeSubmitted:
+			return 0, errors.New("transaction already has an open dispute")
+		case domain.DisputeWon:
+			return 0, errors.New("transaction already has a won dispute")
+		}
+	}
+
+	refunds, err := transactionRepository.GetRefundsByParentID([]string{transaction.ID.String()})
+	if err != nil {
+		return 0, err
+	}
+
+	amount := transaction.Debit
+	for _, refund := range refunds {
+		amount -= refund.Debit
+	}
+
+	if amount <= 0 {
+		return 0, errors.New("transaction was fully refunded, nothing to dispute")
+	}
+	return amount, nil
+}
+
+func disputeTransaction(transaction *domain.Transaction, dispute *domain.Dispute, entry domain.TransactionEntry, note string) *domain.Transaction {
+	return &domain.Transaction{
+		Company:           transaction.Company,
+		Wallet:            transaction.Wallet,
+		Card:              transaction.Card,
+		PartnerCardID:     transaction.PartnerCardID,
+		Customer:          transaction.Customer,
+		PartnerCustomerID: transaction.PartnerCustomerID,
+		Debit:             dispute.Amount,
+		Note:              note,
+		ReferenceID:       dispute.ID.String(),
+		Status:            domain.SuccessStatus,
+		Entry:             entry,
+		Channel:           transaction.Channel,
+		Type:              domain.DisputeType,
+		CardType:          transaction.CardType,
+		ParentID:          transaction.ID.String(),
+	}
+}
+
+func validDisputeReason(reason domain.DisputeReason) bool {
+	for _, r := range domain.DisputeReasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+func canTransitionDispute(from, to domain.DisputeStatus) bool {
+	for _, status := range domain.DisputeTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"core_business/internals/common"
+	"core_business/internals/core/domain"
+	"core_business/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// createCapturedCharge authorizes and captures a charge of amount on the card
+func createCapturedCharge(t *testing.T, card *domain.Card, authorization string, amount int) domain.Transaction {
+	require.NoError(t, authorize(card, authorization+"-event", authorization, amount, "store"))
+	require.NoError(t, settle(card, authorization+"-capture", authorization, amount, "approved"))
+	return authorized(t, card, authorization, domain.WithdrawalType)
+}
+
+func TestDisputeCappedByRefunds(t *testing.T) {
+	card := createRandomCardholder(t, domain.GeneralCard)
+	charge := createCapturedCharge(t, card, "auth-"+card.PartnerCardID, 1000)
+
+	require.NoError(t, refund(card, "refund-event-"+card.PartnerCardID, charge.AuthorizationID, 400))
+
+	// only what the merchant did not refund is credited provisionally
+	dispute, err := DisputeService.OpenDispute(common.OpenDisputeRequest{Transaction: charge.ID, Reason: "duplicate"})
+	require.NoError(t, err)
+	require.Equal(t, float64(600), dispute.Amount)
+	require.Equal(t, utils.ToMinorUnit(10), spent(t, card))
+
+	_, err = DisputeService.OpenDispute(common.OpenDisputeRequest{Transaction: charge.ID, Reason: "duplicate"})
+	require.Error(t, err)
+
+	// the open dispute already credited the charge back, a void or a further refund would credit it again
+	_, err = TransactionService.VoidTransaction(charge.ID.String(), common.VoidTransactionRequest{Reason: "duplicate"})
+	require.Error(t, err)
+	require.Error(t, refund(card, "late-refund-event-"+card.PartnerCardID, charge.AuthorizationID, 100))
+	require.Equal(t, utils.ToMinorUnit(10), spent(t, card))
+
+	for _, status := range []string{"submitted", "lost"} {
+		_, err = DisputeService.ChangeDisputeStatus(dispute.ID.String(), common.ChangeDisputeStatusRequest{Status: status})
+		require.NoError(t, err)
+	}
+	require.Equal(t, utils.ToMinorUnit(610), spent(t, card))
+}
+
+func TestDisputeOfVoidedCharge(t *testing.T) {
+	card := createRandomCardholder(t, domain.GeneralCard)
+	charge := createCapturedCharge(t, card, "auth-"+card.PartnerCardID, 1000)
+
+	_, err := TransactionService.VoidTransaction(charge.ID.String(), common.VoidTransactionRequest{Reason: "duplicate"})
+	require.NoError(t, err)
+
+	_, err = DisputeService.OpenDispute(common.OpenDisputeRequest{Transaction: charge.ID, Reason: "duplicate"})
+	require.Error(t, err)
+	require.Equal(t, utils.ToMinorUnit(10), spent(t, card))
+}
+
+func TestLostDisputeWithoutHeadroom(t *testing.T) {
+	card := createRandomCardholder(t, domain.GeneralCard)
+	charge := createCapturedCharge(t, card, "auth-"+card.PartnerCardID, 1000)
+
+	dispute, err := DisputeService.OpenDispute(common.OpenDisputeRequest{Transaction: charge.ID, Reason: "fraudulent"})
+	require.NoError(t, err)
+
+	// a settlement past the credit limit leaves the wallet without headroom for the reversal
+	require.NoError(t, authorize(card, "other-event-"+card.PartnerCardID, "other-"+card.PartnerCardID, 1000, "fuel"))
+	require.NoError(t, settle(card, "other-capture-"+card.PartnerCardID, "other-"+card.PartnerCardID, 200000, "approved"))
+	require.Greater(t, spent(t, card), int64(cardholderCreditLimit))
+
+	for _, status := range []string{"submitted", "lost"} {
+		dispute, err = DisputeService.ChangeDisputeStatus(dispute.ID.String(), common.ChangeDisputeStatusRequest{Status: status})
+		require.NoError(t, err)
+	}
+	require.Equal(t, domain.DisputeLost, dispute.Status)
+	require.Equal(t, utils.ToMinorUnit(203010), spent(t, card))
+}
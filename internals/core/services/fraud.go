@@ -0,0 +1,343 @@
+package services
+
+import (
+	"context"
+	"core_business/internals/common"
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"core_business/pkg/utils"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"strings"
+	"time"
+)
+
+// ErrFraudDeclined returned when a fraud rule declines an authorization
+var ErrFraudDeclined = errors.New("authorization declined by fraud rules")
+
+type fraudService struct {
+	FraudRepository ports.IFraudRepository
+	CardRepository  ports.ICardRepository
+	CardIssuers     ports.ICardIssuers
+	logger          *log.Logger
+}
+
+// NewFraudService function create a new instance for service
+func NewFraudService(fr ports.IFraudRepository, cr ports.ICardRepository, ci ports.ICardIssuers, l *log.Logger) ports.IFraudService {
+	return &fraudService{
+		FraudRepository: fr,
+		CardRepository:  cr,
+		CardIssuers:     ci,
+		logger:          l,
+	}
+}
+
+func (fs *fraudService) GetFraudRulesByCompanyID(id string) ([]domain.FraudRule, error) {
+	rules, err := fs.FraudRepository.GetRulesByCompanyID(id)
+	if err != nil {
+		fs.logger.Error(err)
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (fs *fraudService) CreateFraudRule(body common.CreateFraudRuleRequest) (*domain.FraudRule, error) {
+	rule := &domain.FraudRule{
+		Company:       body.Company,
+		Type:          domain.FraudRuleType(strings.ToUpper(strings.TrimSpace(body.Type))),
+		Action:        domain.FraudAction(strings.ToUpper(strings.TrimSpace(body.Action))),
+		Threshold:     body.Threshold,
+		WindowMinutes: body.WindowMinutes,
+		Multiplier:    body.Multiplier,
+		MinHistory:    body.MinHistory,
+		HomeCountry:   domain.NormalizeCountry(body.HomeCountry),
+		Active:        true,
+	}
+
+	if rule.Action == "" {
+		rule.Action = domain.AlertAction
+	}
+
+	if err := validateFraudRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := fs.FraudRepository.PersistRule(rule); err != nil {
+		fs.logger.Error(err)
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (fs *fraudService) UpdateFraudRule(id string, body common.UpdateFraudRuleRequest) (*domain.FraudRule, error) {
+	rule, err := fs.FraudRepository.GetRuleByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if body.Action != nil {
+		rule.Action = domain.FraudAction(strings.ToUpper(strings.TrimSpace(*body.Action)))
+	}
+
+	if body.Threshold != nil {
+		rule.Threshold = *body.Threshold
+	}
+
+	if body.WindowMinutes != nil {
+		rule.WindowMinutes = *body.WindowMinutes
+	}
+
+	if body.Multiplier != nil {
+		rule.Multiplier = *body.Multiplier
+	}
+
+	if body.MinHistory != nil {
+		rule.MinHistory = *body.MinHistory
+	}
+
+	if body.HomeCountry != nil {
+		rule.HomeCountry = domain.NormalizeCountry(*body.HomeCountry)
+	}
+
+	if body.Active != nil {
+		rule.Active = *body.Active
+	}
+
+	if err = validateFraudRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err = fs.FraudRepository.PersistRule(rule); err != nil {
+		fs.logger.Error(err)
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (fs *fraudService) DeleteFraudRule(id string) error {
+	if err := fs.FraudRepository.DeleteRule(id); err != nil {
+		fs.logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (fs *fraudService) GetFraudAlertsByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error) {
+	alerts, err := fs.FraudRepository.GetAlertsByCompanyID(id, pagination)
+	if err != nil {
+		fs.logger.Error(err)
+		return nil, err
+	}
+	return alerts, nil
+}
+
+// ReviewFraudAlert closes an open alert, a dismissed alert can unlock the card it locked
+func (fs *fraudService) ReviewFraudAlert(ctx context.Context, id string, body common.ReviewFraudAlertRequest) (*domain.FraudAlert, error) {
+	alert, err := fs.FraudRepository.GetAlertByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if alert.Status != domain.AlertOpen {
+		return nil, errors.New("alert has already been reviewed")
+	}
+
+	status := domain.FraudAlertStatus(strings.ToUpper(strings.TrimSpace(body.Status)))
+	if status != domain.AlertConfirmed && status != domain.AlertDismissed {
+		return nil, errors.New("status must be CONFIRMED or DISMISSED")
+	}
+
+	if body.UnlockCard && status == domain.AlertDismissed && alert.Action == domain.LockAction {
+		card, err := fs.CardRepository.GetByID(alert.Card.String())
+		if err != nil {
+			return nil, err
+		}
+
+		if domain.ParseCardStatus(card.Status) == domain.CardLocked {
+			if err = syncIssuerStatus(ctx, fs.CardIssuers, card, domain.CardActive, "fraud alert dismissed"); err != nil {
+				fs.logger.Error(err)
+				return nil, err
+			}
+
+			err = transitionCard(fs.CardRepository, card, domain.CardActive, body.ReviewedBy, "fraud alert dismissed")
+			if err != nil {
+				fs.logger.Error(err)
+				return nil, err
+			}
+		}
+	}
+
+	now := time.Now()
+	alert.Status = status
+	alert.ReviewedBy = body.ReviewedBy
+	alert.ReviewNote = body.Note
+	alert.ReviewedAt = &now
+
+	if err = fs.FraudRepository.PersistAlert(alert); err != nil {
+		fs.logger.Error(err)
+		return nil, err
+	}
+	return alert, nil
+}
+
+// EvaluateAuthorization runs the company fraud rules on an authorization request, every match raises an alert
+// and the authorization is declined when a matching rule declines or locks the card. The alerts and the lock are
+// saved right away, outside the transaction booking the authorization, so they stay when the authorization is
+// declined later for another reason such as insufficient credit
+func (fs *fraudService) EvaluateAuthorization(card *domain.Card, body *common.CreateTransactionRequest) error {
+	rules, err := fs.FraudRepository.GetRulesByCompanyID(card.Company.String())
+	if err != nil {
+		fs.logger.Error(err)
+		return err
+	}
+
+	if len(rules) == 0 {
+		rules = domain.DefaultFraudRules(card.Company)
+	}
+
+	payload := body.Data.Object
+	amount := float64(payload.PendingRequest.Amount)
+	action := domain.FraudAction("")
+
+	for _, rule := range rules {
+		if !rule.Active {
+			continue
+		}
+
+		reason, err := fs.match(rule, card, body, amount)
+		if err != nil {
+			fs.logger.Error(err)
+			return err
+		}
+
+		if reason == "" {
+			continue
+		}
+
+		err = fs.FraudRepository.PersistAlert(&domain.FraudAlert{
+			Company:         card.Company,
+			Card:            card.ID,
+			Rule:            rule.Type,
+			Action:          rule.Action,
+			Reason:          reason,
+			AuthorizationID: payload.Id,
+			Amount:          amount,
+			MerchantName:    payload.Merchant.Name,
+			MerchantCountry: payload.Merchant.Country,
+			Status:          domain.AlertOpen,
+		})
+		if err != nil {
+			fs.logger.Error(err)
+			return err
+		}
+
+		if severity(rule.Action) > severity(action) {
+			action = rule.Action
+		}
+	}
+
+	if action == domain.LockAction {
+		if err = lockCardForSystem(fs.CardIssuers, fs.CardRepository, card, "system:fraud", "fraud rule matched", fs.logger); err != nil {
+			fs.logger.Error(err)
+			return err
+		}
+	}
+
+	if action == domain.DeclineAction || action == domain.LockAction {
+		return ErrFraudDeclined
+	}
+	return nil
+}
+
+// match returns why the authorization matches the rule, or an empty reason when it does not
+func (fs *fraudService) match(rule domain.FraudRule, card *domain.Card, body *common.CreateTransactionRequest, amount float64) (string, error) {
+	payload := body.Data.Object
+	since := time.Now().Add(-time.Duration(rule.WindowMinutes) * time.Minute)
+
+	switch rule.Type {
+	case domain.VelocityRule:
+		count, err := fs.FraudRepository.CountCardTransactionsSince(card.ID.String(), "", since)
+		if err != nil || count+1 <= int64(rule.Threshold) {
+			return "", err
+		}
+		return fmt.Sprintf("%v transactions in %v minutes", count+1, rule.WindowMinutes), nil
+	case domain.AtmBurstRule:
+		if !strings.EqualFold(payload.TransactionMetadata.Channel, string(domain.AtmChannel)) {
+			return "", nil
+		}
+
+		count, err := fs.FraudRepository.CountCardTransactionsSince(card.ID.String(), domain.AtmChannel, since)
+		if err != nil || count+1 <= int64(rule.Threshold) {
+			return "", err
+		}
+		return fmt.Sprintf("%v ATM withdrawals in %v minutes", count+1, rule.WindowMinutes), nil
+	case domain.ForeignMerchantRule:
+		country := domain.NormalizeCountry(payload.Merchant.Country)
+		if country == "" || rule.HomeCountry == "" || country == domain.NormalizeCountry(rule.HomeCountry) {
+			return "", nil
+		}
+		return fmt.Sprintf("merchant %v is in %v", payload.Merchant.Name, country), nil
+	case domain.AmountAnomalyRule:
+		average, count, err := fs.FraudRepository.GetCardAverageAmount(card.ID.String())
+		if err != nil || count < int64(rule.MinHistory) || average <= 0 || amount <= rule.Multiplier*average {
+			return "", err
+		}
+		return fmt.Sprintf("%v is more than %v times the card average of %.2f", amount, rule.Multiplier, average), nil
+	case domain.NewMerchantCategoryRule:
+		if payload.Merchant.Category == "" {
+			return "", nil
+		}
+
+		_, history, err := fs.FraudRepository.GetCardAverageAmount(card.ID.String())
+		if err != nil || history < int64(rule.MinHistory) {
+			return "", err
+		}
+
+		count, err := fs.FraudRepository.CountCardMerchantCategory(card.ID.String(), payload.Merchant.Category)
+		if err != nil || count > 0 {
+			return "", err
+		}
+		return fmt.Sprintf("first purchase in merchant category %v", payload.Merchant.Category), nil
+	}
+	return "", nil
+}
+
+func validateFraudRule(rule *domain.FraudRule) error {
+	if severity(rule.Action) == 0 {
+		return fmt.Errorf("invalid fraud action %v", rule.Action)
+	}
+
+	switch rule.Type {
+	case domain.VelocityRule, domain.AtmBurstRule:
+		if rule.Threshold <= 0 || rule.WindowMinutes <= 0 {
+			return errors.New("threshold and window_minutes are required")
+		}
+	case domain.ForeignMerchantRule:
+		if rule.HomeCountry == "" {
+			return errors.New("home_country is required")
+		}
+
+		if len(rule.HomeCountry) != 2 {
+			return errors.New("home_country must be an ISO 3166 country code")
+		}
+	case domain.AmountAnomalyRule:
+		if rule.Multiplier <= 1 {
+			return errors.New("multiplier must be greater than 1")
+		}
+	case domain.NewMerchantCategoryRule:
+	default:
+		return fmt.Errorf("invalid fraud rule type %v", rule.Type)
+	}
+	return nil
+}
+
+// severity orders the fraud actions, 0 for an unknown action
+func severity(action domain.FraudAction) int {
+	for i, a := range domain.FraudActions {
+		if a == action {
+			return i + 1
+		}
+	}
+	return 0
+}
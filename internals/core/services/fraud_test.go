@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+	"core_business/internals/common"
+	"core_business/internals/core/domain"
+	"core_business/internals/repositories"
+	"core_business/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestForeignMerchantAlert(t *testing.T) {
+	fraudRepository := repositories.NewFraudRepository(DBConnection)
+
+	// the default rule is home in NG, merchant countries come as alpha-2 or alpha-3 codes
+	for country, alerted := range map[string]bool{"NG": false, "nga": false, "NGX": true, "GHA": true} {
+		card := createRandomCardholder(t, domain.GeneralCard)
+
+		body := &common.CreateTransactionRequest{Id: "auth-event-" + card.PartnerCardID, Type: "authorization.request"}
+		body.Data.Object.Id = "auth-" + card.PartnerCardID
+		body.Data.Object.Card.Id = card.PartnerCardID
+		body.Data.Object.Card.Type = "virtual"
+		body.Data.Object.TransactionMetadata.Channel = "web"
+		body.Data.Object.PendingRequest.Amount = 1000
+		body.Data.Object.PendingRequest.Currency = "NGN"
+		body.Data.Object.Merchant.Name = "store"
+		body.Data.Object.Merchant.MerchantId = "store"
+		body.Data.Object.Merchant.Country = country
+		require.NoError(t, TransactionService.CreateTransaction(context.Background(), body))
+
+		alerts, err := fraudRepository.GetAlertsByCompanyID(card.Company.String(), &utils.Pagination{Limit: 5, Page: 1})
+		require.NoError(t, err)
+		if alerted {
+			require.Len(t, alerts.Rows, 1, country)
+			require.Equal(t, domain.ForeignMerchantRule, alerts.Rows.([]domain.FraudAlert)[0].Rule)
+		} else {
+			require.Empty(t, alerts.Rows, country)
+		}
+	}
+}
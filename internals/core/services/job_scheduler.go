@@ -0,0 +1,61 @@
+package services
+
+import (
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"errors"
+	uuid "github.com/satori/go.uuid"
+	log "github.com/sirupsen/logrus"
+	"time"
+)
+
+// maxJobPoll longest an instance waits before checking whether a job is due, a job another instance ran is picked
+// up by the others within it once that instance stops
+const maxJobPoll = time.Minute
+
+type jobScheduler struct {
+	JobLeaseRepository ports.IJobLeaseRepository
+	holder             string
+	logger             *log.Logger
+}
+
+// NewJobScheduler function create a new instance for the job scheduler, every instance holds leases under its own id
+func NewJobScheduler(lr ports.IJobLeaseRepository, l *log.Logger) ports.IJobScheduler {
+	return &jobScheduler{
+		JobLeaseRepository: lr,
+		holder:             uuid.NewV4().String(),
+		logger:             l,
+	}
+}
+
+// Every runs the job in the background at startup and then once per interval, the instance that acquires the lease
+// of the job runs it and the others skip it until the lease expires
+func (s *jobScheduler) Every(name string, interval time.Duration, job func() error) {
+	poll := interval
+	if poll > maxJobPoll {
+		poll = maxJobPoll
+	}
+
+	go func() {
+		ticker := time.NewTicker(poll)
+		defer ticker.Stop()
+
+		for ; true; <-ticker.C {
+			s.run(name, interval, job)
+		}
+	}()
+}
+
+func (s *jobScheduler) run(name string, interval time.Duration, job func() error) {
+	now := time.Now()
+	if err := s.JobLeaseRepository.Acquire(name, s.holder, now, now.Add(interval)); err != nil {
+		if !errors.Is(err, domain.ErrJobLeaseHeld) {
+			s.logger.Error(err)
+		}
+		return
+	}
+
+	if err := job(); err != nil {
+		s.logger.Error(err)
+	}
+}
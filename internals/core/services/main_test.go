@@ -0,0 +1,78 @@
+package services
+
+import (
+	"core_business/internals/common"
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"core_business/internals/repositories"
+	"core_business/pkg/database"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var (
+	DBConnection          *gorm.DB
+	TransactionRepository ports.ITransactionRepository
+	CardRepository        ports.ICardRepository
+	CustomerRepository    ports.ICustomerRepository
+	WalletRepository      ports.IWalletRepository
+	TransactionService    ports.ITransactionService
+	DisputeService        ports.IDisputeService
+)
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "services")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	db := database.NewSqliteDatabase()
+
+	DBConnection = db.ConnectDB(filepath.Join(dir, "services.db"))
+	err = db.MigrateAll(DBConnection)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	instantiateServices()
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func instantiateServices() {
+	logger := log.New()
+
+	// card authorizations are charged a 1% fee
+	err := DBConnection.Create(&domain.Fee{
+		Channel:    "Transaction both WEB and POS - card",
+		Identifier: string(common.CardTransactionBOTH),
+		Fee:        1,
+		IsPercent:  true,
+	}).Error
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	TransactionRepository = repositories.NewTransactionRepository(DBConnection)
+	CardRepository = repositories.NewCardRepository(DBConnection)
+	CustomerRepository = repositories.NewCustomerRepository(DBConnection)
+	WalletRepository = repositories.NewWalletRepository(DBConnection)
+	outboxRepository := repositories.NewOutboxRepository(DBConnection)
+
+	walletService := NewWalletService(WalletRepository, outboxRepository, DBConnection, logger)
+	fraudService := NewFraudService(repositories.NewFraudRepository(DBConnection), CardRepository, nil, logger)
+
+	TransactionService = NewTransactionService(TransactionRepository, CustomerRepository, WalletRepository,
+		repositories.NewFeeRepository(DBConnection), repositories.NewCompanyRepository(DBConnection), CardRepository,
+		repositories.NewReceiptPolicyRepository(DBConnection), repositories.NewTagRepository(DBConnection),
+		walletService, fraudService, repositories.NewAccountingPeriodRepository(DBConnection), nil,
+		outboxRepository, nil, DBConnection, logger)
+
+	DisputeService = NewDisputeService(repositories.NewDisputeRepository(DBConnection), TransactionRepository,
+		WalletRepository, walletService, DBConnection, logger)
+}
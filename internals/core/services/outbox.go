@@ -0,0 +1,102 @@
+package services
+
+import (
+	"core_business/internals/common"
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	tx "core_business/pkg/unit_of_work"
+	"encoding/json"
+	uuid "github.com/satori/go.uuid"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"time"
+)
+
+type outboxRelay struct {
+	OutboxRepository ports.IOutboxRepository
+	Broker           ports.IBroker
+	logger           *log.Logger
+}
+
+// NewOutboxRelay function create a new instance for the outbox relay
+func NewOutboxRelay(or ports.IOutboxRepository, b ports.IBroker, l *log.Logger) ports.IOutboxRelay {
+	return &outboxRelay{
+		OutboxRepository: or,
+		Broker:           b,
+		logger:           l,
+	}
+}
+
+// RelayPending publishes unpublished events in the order they were written, it stops at the first failure so consumers never see events out of order.
+// The event id is the message id, a redelivered event is the same message to consumers
+func (r *outboxRelay) RelayPending() (int, error) {
+	events, err := r.OutboxRepository.GetUnpublished(100)
+	if err != nil {
+		r.logger.Error(err)
+		return 0, err
+	}
+
+	for i := range events {
+		message, err := json.Marshal(common.DomainEventMessage{
+			ID:          events[i].ID,
+			Type:        string(events[i].Type),
+			Aggregate:   events[i].Aggregate,
+			AggregateID: events[i].AggregateID,
+			Company:     events[i].Company,
+			OccurredAt:  events[i].CreatedAt,
+			Data:        json.RawMessage(events[i].Payload),
+		})
+		if err != nil {
+			r.logger.Error(err)
+			return i, err
+		}
+
+		events[i].Attempts++
+		if err = r.Broker.Publish(string(events[i].Type), events[i].AggregateID.String(), events[i].ID.String(), message); err != nil {
+			r.logger.Error(err)
+			events[i].LastError = err.Error()
+			_ = r.OutboxRepository.Persist(&events[i])
+			return i, err
+		}
+
+		now := time.Now()
+		events[i].PublishedAt = &now
+		events[i].LastError = ""
+		if err = r.OutboxRepository.Persist(&events[i]); err != nil {
+			r.logger.Error(err)
+			return i, err
+		}
+	}
+	return len(events), nil
+}
+
+// recordEvent writes a domain event to the outbox, pass the repository bound to the transaction of the state change
+func recordEvent(outbox ports.IOutboxRepository, event domain.DomainEvent, aggregate string, aggregateID uuid.UUID, company uuid.UUID, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return outbox.Persist(&domain.OutboxEvent{
+		Type:        event,
+		Aggregate:   aggregate,
+		AggregateID: aggregateID,
+		Company:     company,
+		Payload:     string(payload),
+	})
+}
+
+// inTransaction runs fn in a unit of work, everything fn writes is rolled back when it fails
+func inTransaction(db *gorm.DB, fn func(txx *gorm.DB) error) error {
+	uw := tx.NewGormUnitOfWork(db)
+	txx, err := uw.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err = fn(txx); err != nil {
+		uw.Rollback()
+		return err
+	}
+	return uw.Commit()
+}
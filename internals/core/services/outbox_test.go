@@ -0,0 +1,68 @@
+package services
+
+import (
+	"core_business/internals/core/domain"
+	"core_business/internals/repositories"
+	"core_business/pkg/broker"
+	"core_business/pkg/utils"
+	"errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestRelayOutbox(t *testing.T) {
+	outboxRepository := repositories.NewOutboxRepository(DBConnection)
+	require.NoError(t, outboxRepository.DeleteAll())
+
+	wallet := (&utils.Faker{}).RandomUUID()
+	company := (&utils.Faker{}).RandomUUID()
+	events := []domain.DomainEvent{domain.WalletDebited, domain.WalletCredited, domain.WalletDebited}
+	for _, event := range events {
+		err := outboxRepository.Persist(&domain.OutboxEvent{
+			Type:        event,
+			Aggregate:   "wallet",
+			AggregateID: wallet,
+			Company:     company,
+			Payload:     `{"amount":1000}`,
+		})
+		require.NoError(t, err)
+	}
+
+	messageBroker := broker.NewMemoryBroker()
+	relay := NewOutboxRelay(outboxRepository, messageBroker, log.New())
+
+	messageBroker.FailWith(errors.New("broker unavailable"))
+	published, err := relay.RelayPending()
+	require.Error(t, err)
+	require.Equal(t, 0, published)
+	require.Empty(t, messageBroker.Messages())
+
+	pending, err := outboxRepository.GetUnpublished(10)
+	require.NoError(t, err)
+	require.Len(t, pending, len(events))
+	require.Equal(t, 1, pending[0].Attempts)
+	require.Equal(t, "broker unavailable", pending[0].LastError)
+
+	messageBroker.FailWith(nil)
+	published, err = relay.RelayPending()
+	require.NoError(t, err)
+	require.Equal(t, len(events), published)
+
+	var topics []string
+	ids := map[string]bool{}
+	for _, message := range messageBroker.Messages() {
+		if message.Key == wallet.String() {
+			topics = append(topics, message.Topic)
+			ids[message.MessageID] = true
+		}
+	}
+	require.Equal(t, []string{"WalletDebited", "WalletCredited", "WalletDebited"}, topics)
+	for _, event := range pending {
+		require.True(t, ids[event.ID.String()])
+	}
+
+	pending, err = outboxRepository.GetUnpublished(10)
+	require.NoError(t, err)
+	require.Empty(t, pending)
+}
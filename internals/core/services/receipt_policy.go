@@ -0,0 +1,144 @@
+package services
+
+import (
+	"core_business/internals/common"
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"errors"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"time"
+)
+
+type receiptPolicyService struct {
+	ReceiptPolicyRepository ports.IReceiptPolicyRepository
+	logger                  *log.Logger
+}
+
+// NewReceiptPolicyService function create a new instance for service
+func NewReceiptPolicyService(rpr ports.IReceiptPolicyRepository, l *log.Logger) ports.IReceiptPolicyService {
+	return &receiptPolicyService{
+		ReceiptPolicyRepository: rpr,
+		logger:                  l,
+	}
+}
+
+func (rs *receiptPolicyService) GetReceiptPolicyByID(id string) (*domain.ReceiptPolicy, error) {
+	receiptPolicy, err := rs.ReceiptPolicyRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return receiptPolicy, nil
+}
+
+func (rs *receiptPolicyService) GetReceiptPolicyByCompanyID(id string) (*domain.ReceiptPolicy, error) {
+	receiptPolicy, err := rs.ReceiptPolicyRepository.GetByCompany(id)
+	if err != nil {
+		return nil, err
+	}
+	return receiptPolicy, nil
+}
+
+func (rs *receiptPolicyService) CreateReceiptPolicy(receiptPolicy *domain.ReceiptPolicy) error {
+	_, err := rs.ReceiptPolicyRepository.GetByCompany(receiptPolicy.Company.String())
+	if err == nil {
+		return errors.New("already exist")
+	}
+
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		rs.logger.Error(err)
+		return err
+	}
+
+	err = rs.ReceiptPolicyRepository.Persist(receiptPolicy)
+	if err != nil {
+		rs.logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (rs *receiptPolicyService) UpdateReceiptPolicy(id string, body common.UpdateReceiptPolicyRequest) (*domain.ReceiptPolicy, error) {
+	receiptPolicy, err := rs.ReceiptPolicyRepository.GetByID(id)
+	if err != nil {
+		rs.logger.Error(err)
+		return nil, err
+	}
+
+	if body.AmountThreshold != nil {
+		receiptPolicy.AmountThreshold = *body.AmountThreshold
+	}
+
+	if body.ExemptCategories != nil {
+		receiptPolicy.ExemptCategories = *body.ExemptCategories
+	}
+
+	if body.GraceDays != nil {
+		receiptPolicy.GraceDays = *body.GraceDays
+	}
+
+	if body.AutoLockCard != nil {
+		receiptPolicy.AutoLockCard = *body.AutoLockCard
+	}
+
+	if body.MaxOverdueReceipts != nil {
+		receiptPolicy.MaxOverdueReceipts = *body.MaxOverdueReceipts
+	}
+
+	err = rs.ReceiptPolicyRepository.Persist(receiptPolicy)
+	if err != nil {
+		rs.logger.Error(err)
+		return nil, err
+	}
+	return receiptPolicy, nil
+}
+
+func (rs *receiptPolicyService) DeleteReceiptPolicy(id string) error {
+	err := rs.ReceiptPolicyRepository.Delete(id)
+	if err != nil {
+		rs.logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// ReceiptStatus computes the receipt compliance of a transaction, a nil policy means no receipt is required
+func ReceiptStatus(policy *domain.ReceiptPolicy, transaction *domain.Transaction, now time.Time) domain.ReceiptStatus {
+	if transaction.Receipt != "" {
+		return domain.ReceiptCompliant
+	}
+
+	if policy == nil || !receiptRequired(policy, transaction) {
+		return domain.ReceiptNotRequired
+	}
+
+	if now.After(transaction.CreatedAt.AddDate(0, 0, policy.GraceDays)) {
+		return domain.ReceiptOverdue
+	}
+	return domain.ReceiptPending
+}
+
+func receiptRequired(policy *domain.ReceiptPolicy, transaction *domain.Transaction) bool {
+	if transaction.Type != domain.WithdrawalType || transaction.Entry != domain.DebitEntry {
+		return false
+	}
+
+	if transaction.Status != domain.PendingStatus && transaction.Status != domain.SuccessStatus {
+		return false
+	}
+
+	if transaction.VoidedAt != nil {
+		return false
+	}
+
+	if transaction.Debit < policy.AmountThreshold {
+		return false
+	}
+
+	for _, category := range policy.ExemptCategories {
+		if category == transaction.ExpenseCategory {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"core_business/internals/common"
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"core_business/pkg/utils"
+	"errors"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"strings"
+)
+
+type tagService struct {
+	TagRepository ports.ITagRepository
+	logger        *log.Logger
+}
+
+// NewTagService function create a new instance for service
+func NewTagService(tr ports.ITagRepository, l *log.Logger) ports.ITagService {
+	return &tagService{
+		TagRepository: tr,
+		logger:        l,
+	}
+}
+
+func (ts *tagService) GetTagByID(id string) (*domain.Tag, error) {
+	tag, err := ts.TagRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+func (ts *tagService) GetTagByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error) {
+	tags, err := ts.TagRepository.GetTagByCompanyID(id, pagination)
+	if err != nil {
+		ts.logger.Error(err)
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (ts *tagService) CreateTag(tag *domain.Tag) error {
+	tag.Name = strings.TrimSpace(tag.Name)
+
+	_, err := ts.TagRepository.GetByName(tag.Company.String(), tag.Name)
+	if err == nil {
+		return errors.New("already exist")
+	}
+
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		ts.logger.Error(err)
+		return err
+	}
+
+	err = ts.TagRepository.Persist(tag)
+	if err != nil {
+		ts.logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (ts *tagService) UpdateTag(id string, body common.UpdateTagRequest) (*domain.Tag, error) {
+	tag, err := ts.TagRepository.GetByID(id)
+	if err != nil {
+		ts.logger.Error(err)
+		return nil, err
+	}
+
+	if body.Name != nil {
+		tag.Name = strings.TrimSpace(*body.Name)
+	}
+
+	if body.Color != nil {
+		tag.Color = *body.Color
+	}
+
+	err = ts.TagRepository.Persist(tag)
+	if err != nil {
+		ts.logger.Error(err)
+		return nil, err
+	}
+	return tag, nil
+}
+
+func (ts *tagService) DeleteTag(id string) error {
+	err := ts.TagRepository.Delete(id)
+	if err != nil {
+		ts.logger.Error(err)
+		return err
+	}
+	return nil
+}
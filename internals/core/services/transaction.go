@@ -1,51 +1,88 @@
 package services
 
 import (
+	"bytes"
+	"context"
 	"core_business/internals/common"
 	"core_business/internals/core/domain"
 	"core_business/internals/core/ports"
 	"core_business/pkg/utils"
+	"encoding/csv"
 	"errors"
 	"fmt"
+	uuid "github.com/satori/go.uuid"
 	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"math"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type transactionService struct {
-	TransactionRepository ports.ITransactionRepository
-	CustomerRepository    ports.ICustomerRepository
-	WalletRepository      ports.IWalletRepository
-	WalletService         ports.IWalletService
-	FeeRepository         ports.IFeeRepository
-	CompanyRepository     ports.ICompanyRepository
-	CardRepository        ports.ICardRepository
-	logger                *log.Logger
+	TransactionRepository   ports.ITransactionRepository
+	CustomerRepository      ports.ICustomerRepository
+	WalletRepository        ports.IWalletRepository
+	WalletService           ports.IWalletService
+	FeeRepository           ports.IFeeRepository
+	CompanyRepository       ports.ICompanyRepository
+	CardRepository          ports.ICardRepository
+	ReceiptPolicyRepository ports.IReceiptPolicyRepository
+	TagRepository           ports.ITagRepository
+	FraudService            ports.IFraudService
+	PeriodRepository        ports.IAccountingPeriodRepository
+	EventPublisher          ports.IEventPublisher
+	OutboxRepository        ports.IOutboxRepository
+	CardIssuers             ports.ICardIssuers
+	DB                      *gorm.DB
+	logger                  *log.Logger
 }
 
 // NewTransactionService function create a new instance for service
 func NewTransactionService(tr ports.ITransactionRepository,
 	cr ports.ICustomerRepository, wr ports.IWalletRepository,
 	fr ports.IFeeRepository, cmr ports.ICompanyRepository,
-	cdr ports.ICardRepository,
-	ws ports.IWalletService, l *log.Logger) ports.ITransactionService {
+	cdr ports.ICardRepository, rpr ports.IReceiptPolicyRepository,
+	tgr ports.ITagRepository, ws ports.IWalletService, frs ports.IFraudService,
+	apr ports.IAccountingPeriodRepository, ep ports.IEventPublisher,
+	or ports.IOutboxRepository, ci ports.ICardIssuers, db *gorm.DB, l *log.Logger) ports.ITransactionService {
 	return &transactionService{
-		TransactionRepository: tr,
-		CustomerRepository:    cr,
-		WalletRepository:      wr,
-		WalletService:         ws,
-		FeeRepository:         fr,
-		CompanyRepository:     cmr,
-		CardRepository:        cdr,
-		logger:                l,
+		TransactionRepository:   tr,
+		CustomerRepository:      cr,
+		WalletRepository:        wr,
+		WalletService:           ws,
+		FeeRepository:           fr,
+		CompanyRepository:       cmr,
+		CardRepository:          cdr,
+		ReceiptPolicyRepository: rpr,
+		TagRepository:           tgr,
+		FraudService:            frs,
+		PeriodRepository:        apr,
+		EventPublisher:          ep,
+		OutboxRepository:        or,
+		CardIssuers:             ci,
+		DB:                      db,
+		logger:                  l,
 	}
 }
 
 func (ts *transactionService) GetTransactionByID(id string) (*domain.Transaction, error) {
-	address, err := ts.TransactionRepository.GetByID(id)
+	transaction, err := ts.TransactionRepository.GetByID(id)
 	if err != nil {
 		return nil, err
 	}
-	return address, nil
+
+	if err = ts.setReceiptStatus(transaction, map[uuid.UUID]*domain.ReceiptPolicy{}, time.Now()); err != nil {
+		ts.logger.Error(err)
+		return nil, err
+	}
+
+	transactions := []domain.Transaction{*transaction}
+	if err = ts.applyRefunds(transactions); err != nil {
+		ts.logger.Error(err)
+		return nil, err
+	}
+	return &transactions[0], nil
 }
 
 func (ts *transactionService) GetTransactionByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error) {
@@ -54,6 +91,16 @@ func (ts *transactionService) GetTransactionByCompanyID(id string, pagination *u
 		ts.logger.Error(err)
 		return nil, err
 	}
+
+	if err = ts.applyReceiptStatus(transactions.Rows.([]domain.Transaction)); err != nil {
+		ts.logger.Error(err)
+		return nil, err
+	}
+
+	if err = ts.applyRefunds(transactions.Rows.([]domain.Transaction)); err != nil {
+		ts.logger.Error(err)
+		return nil, err
+	}
 	return transactions, nil
 }
 
@@ -63,6 +110,35 @@ func (ts *transactionService) GetTransactionByCardID(id string, pagination *util
 		ts.logger.Error(err)
 		return nil, err
 	}
+
+	if err = ts.applyReceiptStatus(transactions.Rows.([]domain.Transaction)); err != nil {
+		ts.logger.Error(err)
+		return nil, err
+	}
+
+	if err = ts.applyRefunds(transactions.Rows.([]domain.Transaction)); err != nil {
+		ts.logger.Error(err)
+		return nil, err
+	}
+	return transactions, nil
+}
+
+func (ts *transactionService) GetTransactionByCustomerID(id string, pagination *utils.Pagination) (*utils.Pagination, error) {
+	transactions, err := ts.TransactionRepository.GetTransactionByCustomerID(id, pagination)
+	if err != nil {
+		ts.logger.Error(err)
+		return nil, err
+	}
+
+	if err = ts.applyReceiptStatus(transactions.Rows.([]domain.Transaction)); err != nil {
+		ts.logger.Error(err)
+		return nil, err
+	}
+
+	if err = ts.applyRefunds(transactions.Rows.([]domain.Transaction)); err != nil {
+		ts.logger.Error(err)
+		return nil, err
+	}
 	return transactions, nil
 }
 
@@ -72,22 +148,69 @@ func (ts *transactionService) GetAllTransaction(pagination *utils.Pagination) (*
 		ts.logger.Error(err)
 		return nil, err
 	}
+
+	if err = ts.applyReceiptStatus(transactions.Rows.([]domain.Transaction)); err != nil {
+		ts.logger.Error(err)
+		return nil, err
+	}
+
+	if err = ts.applyRefunds(transactions.Rows.([]domain.Transaction)); err != nil {
+		ts.logger.Error(err)
+		return nil, err
+	}
 	return transactions, nil
 }
 
-func (ts *transactionService) CreateTransaction(body *common.CreateTransactionRequest) error {
-	payload := body.Data.Object
-	customerEntity := domain.Customer{
-		PartnerCustomerID: payload.Customer.Id,
+func (ts *transactionService) GetMissingReceiptsByCustomerID(id string, pagination *utils.Pagination) (*utils.Pagination, error) {
+	customer, err := ts.CustomerRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := ts.ReceiptPolicyRepository.GetByCompany(customer.Company.String())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			pagination.Rows = []domain.Transaction{}
+			return pagination, nil
+		}
+		ts.logger.Error(err)
+		return nil, err
+	}
+
+	transactions, err := ts.TransactionRepository.GetMissingReceiptsByCustomerID(id, policy, pagination)
+	if err != nil {
+		ts.logger.Error(err)
+		return nil, err
+	}
+
+	now := time.Now()
+	rows := transactions.Rows.([]domain.Transaction)
+	for i := range rows {
+		rows[i].ReceiptStatus = ReceiptStatus(policy, &rows[i], now)
 	}
+	return transactions, nil
+}
+
+func (ts *transactionService) CreateTransaction(ctx context.Context, body *common.CreateTransactionRequest) (err error) {
+	payload := body.Data.Object
+
+	webhookType := strings.ToLower(strings.TrimSpace(body.Type))
 
 	card, err := ts.CardRepository.GetBy(payload.Card.Id)
 
-	if err != nil || card.Lock == true {
+	if err == nil && webhookType == "authorization.request" {
+		defer func() {
+			ts.publishAuthorization(card, body, err)
+		}()
+	}
+
+	// refunds and reversals are credited back even when the card has since been locked
+	if err != nil || (!card.Usable() && webhookType != "transaction.refund" && webhookType != "transaction.reversal") {
 		return errors.New("card is invalid")
 	}
 
-	customer, err := ts.CustomerRepository.GetBy(customerEntity)
+	// the cardholder of the card, their partner customer id changes when the company moves to another issuer
+	customer, err := ts.CustomerRepository.GetByID(card.Customer.String())
 	if err != nil {
 		return err
 	}
@@ -101,13 +224,42 @@ func (ts *transactionService) CreateTransaction(body *common.CreateTransactionRe
 	if strings.ToLower(strings.TrimSpace(body.Type)) == "transaction.created" {
 		fmt.Println("Just got here", body, "transaction.created")
 
+		if payload.Authorization != "" {
+			authorized, err := ts.TransactionRepository.GetBy(domain.Transaction{
+				AuthorizationID: payload.Authorization,
+				Card:            card.ID,
+				Type:            domain.WithdrawalType,
+			})
+			if err != nil {
+				return err
+			}
+
+			if len(authorized) > 0 {
+				switch strings.ToLower(strings.TrimSpace(payload.Status)) {
+				case "approved":
+					if err := ts.ProcessCapture(body, &authorized[0], wallet); err != nil {
+						return err
+					}
+
+					ts.cancelUsedCard(ctx, card)
+					return nil
+				case "failed":
+					return ts.failAuthorization(&authorized[0], body.Id, wallet)
+				}
+				return errors.New("invalid webhook")
+			}
+		}
+
 		err := ts.ProcessTransactionState(body, wallet)
 
 		if err != nil {
 			return err
 		}
 
+		ts.cancelUsedCard(ctx, card)
 		return nil
+	} else if webhookType == "transaction.refund" || webhookType == "transaction.reversal" {
+		return ts.ProcessRefund(body, card, customer, wallet)
 	} else if strings.ToLower(strings.TrimSpace(body.Type)) == "authorization.request" {
 
 		var (
@@ -124,12 +276,45 @@ func (ts *transactionService) CreateTransaction(body *common.CreateTransactionRe
 			return errors.New("invalid channel")
 		}
 
+		err = ts.EnforceReceiptPolicy(card)
+
+		if err != nil {
+			return err
+		}
+
 		identifier, err := ts.FeeRepository.GetByIdentifier(string(chargeIdentify))
 
 		if err != nil {
 			return err
 		}
 
+		if payload.Id != "" {
+			authorized, err := ts.TransactionRepository.GetBy(domain.Transaction{
+				AuthorizationID: payload.Id,
+				Card:            card.ID,
+				Type:            domain.WithdrawalType,
+			})
+			if err != nil {
+				return err
+			}
+
+			if len(authorized) > 0 {
+				return ts.ProcessIncrementalAuthorization(body, card, &authorized[0], identifier.Fee, wallet)
+			}
+		}
+
+		if err = ts.checkCardMode(card, body); err != nil {
+			return err
+		}
+
+		if err = checkSchedule(card, time.Now()); err != nil {
+			return err
+		}
+
+		if err = ts.FraudService.EvaluateAuthorization(card, body); err != nil {
+			return err
+		}
+
 		fee := identifier.Fee / 100 * float64(payload.PendingRequest.Amount)
 
 		totalAmount := fee + float64(payload.PendingRequest.Amount)
@@ -146,12 +331,6 @@ func (ts *transactionService) CreateTransaction(body *common.CreateTransactionRe
 			Entry:           &entryType,
 		}
 
-		_, err = ts.WalletService.UpdateBalance(wallet.ID.String(), debitWallet)
-
-		if err != nil {
-			return err
-		}
-
 		card, err := ts.CardRepository.GetBy(body.Data.Object.Card.Id)
 
 		if err != nil {
@@ -173,6 +352,7 @@ func (ts *transactionService) CreateTransaction(body *common.CreateTransactionRe
 			Channel:           domain.TransactionChannel(payload.TransactionMetadata.Channel),
 			Type:              domain.FeeType,
 			CardType:          domain.CardType(payload.Card.Type),
+			AuthorizationID:   payload.Id,
 		}
 
 		transaction := domain.Transaction{
@@ -190,139 +370,1116 @@ func (ts *transactionService) CreateTransaction(body *common.CreateTransactionRe
 			Channel:           domain.TransactionChannel(payload.TransactionMetadata.Channel),
 			Type:              domain.WithdrawalType,
 			CardType:          domain.CardType(payload.Card.Type),
+			AuthorizationID:   payload.Id,
+			AuthorizedAmount:  float64(payload.PendingRequest.Amount),
+			Currency:          payload.PendingRequest.Currency,
+			MerchantAmount:    float64(payload.PendingRequest.MerchantAmount),
+			MerchantCurrency:  payload.PendingRequest.MerchantCurrency,
+			MerchantName:      payload.Merchant.Name,
+			MerchantCategory:  payload.Merchant.Category,
+			MerchantCountry:   payload.Merchant.Country,
+			Vat:               float64(payload.Vat),
+			PartnerFee:        float64(payload.Fee),
+			FeeDetails:        partnerFeeDetails(body),
 		}
 
-		err = ts.TransactionRepository.Persist(&feeTransaction)
-		if err != nil {
-			return err
-		}
+		// the card claim, the debit and the rows are kept or rolled back together, a declined claim debits nothing
+		return inTransaction(ts.DB, func(txx *gorm.DB) error {
+			transactionRepository := ts.TransactionRepository.WithTx(txx)
 
-		err = ts.TransactionRepository.Persist(&transaction)
-		if err != nil {
-			return err
-		}
-		return nil
+			if err := claimCard(ts.CardRepository.WithTx(txx), card, body); err != nil {
+				return err
+			}
+
+			if _, err := ts.WalletService.WithTx(txx).UpdateBalance(wallet.ID.String(), debitWallet); err != nil {
+				return err
+			}
+
+			if err := transactionRepository.Persist(&feeTransaction); err != nil {
+				return err
+			}
+
+			if err := transactionRepository.Persist(&transaction); err != nil {
+				return err
+			}
+
+			err := transactionRepository.PersistAmountChange(&domain.AmountChange{
+				Transaction: transaction.ID,
+				Type:        domain.AuthorizationChange,
+				Amount:      transaction.Debit,
+				Difference:  transaction.Debit,
+				ReferenceID: body.Id,
+			})
+			if err != nil {
+				return err
+			}
+
+			return recordEvent(ts.OutboxRepository.WithTx(txx), domain.TransactionAuthorized, "transaction", transaction.ID, transaction.Company, transaction)
+		})
 	}
 
 	return errors.New("invalid webhook")
 }
 
-func (ts *transactionService) ProcessTransactionState(body *common.CreateTransactionRequest, wallet *domain.Wallet) error {
-	if strings.ToLower(strings.TrimSpace(body.Data.Object.Status)) == "approved" {
-		transactionEntity := domain.Transaction{
-			ReferenceID: body.Id,
+// checkCardMode declines what the mode of the card does not allow, a single use card approves one purchase and
+// a merchant locked card only the merchant it was first approved at, increments of the same authorization are allowed
+func (ts *transactionService) checkCardMode(card *domain.Card, body *common.CreateTransactionRequest) error {
+	switch card.Mode {
+	case domain.SingleUseCard:
+		if card.UsedBy != "" && card.UsedBy != body.Data.Object.Id {
+			return domain.ErrSingleUseCardUsed
 		}
 
-		transactions, err := ts.TransactionRepository.GetBy(transactionEntity)
-
+		authorized, err := ts.TransactionRepository.GetBy(domain.Transaction{Card: card.ID, Type: domain.WithdrawalType})
 		if err != nil {
 			return err
 		}
 
-		for _, transaction := range transactions {
-			transaction.Status = domain.SuccessStatus
-			ts.TransactionRepository.Persist(&transaction)
+		for _, transaction := range authorized {
+			if transaction.Status != domain.FailedStatus && transaction.AuthorizationID != body.Data.Object.Id {
+				return domain.ErrSingleUseCardUsed
+			}
 		}
+	case domain.MerchantLockedCard:
+		merchant := body.Data.Object.Merchant
+		if card.LockedMerchantID != "" && card.LockedMerchantID != domain.MerchantKey(merchant.MerchantId, merchant.Name) {
+			return domain.ErrMerchantNotAllowed
+		}
+	}
+	return nil
+}
+
+// claimCard claims a single use card or locks a merchant locked card to the merchant of its first approval, checkCardMode
+// only reads the card so two concurrent authorizations are told apart here
+func claimCard(cardRepository ports.ICardRepository, card *domain.Card, body *common.CreateTransactionRequest) error {
+	switch card.Mode {
+	case domain.SingleUseCard:
+		return cardRepository.ClaimSingleUse(card.ID.String(), body.Data.Object.Id)
+	case domain.MerchantLockedCard:
+		merchant := body.Data.Object.Merchant
+		return cardRepository.LockToMerchant(card.ID.String(), domain.MerchantKey(merchant.MerchantId, merchant.Name), merchant.Name)
+	}
+	return nil
+}
 
+// checkSchedule declines a card used outside its schedule, the reason tells when the card can be used
+func checkSchedule(card *domain.Card, at time.Time) error {
+	if card.Schedule == nil {
 		return nil
+	}
 
-	} else if strings.ToLower(strings.TrimSpace(body.Data.Object.Status)) == "failed" {
-		var charges float64
-		var err error
+	allowed, err := card.Schedule.Allows(at)
+	if err != nil {
+		return err
+	}
 
-		transactionEntity := domain.Transaction{
-			ReferenceID: body.Id,
-		}
+	if !allowed {
+		return fmt.Errorf("%w: allowed %v", domain.ErrOutsideSchedule, card.Schedule)
+	}
+	return nil
+}
 
-		transactions, err := ts.TransactionRepository.GetBy(transactionEntity)
+// cancelUsedCard cancels a single use card once its transaction settled, the settlement stands when the cancel fails
+func (ts *transactionService) cancelUsedCard(ctx context.Context, card *domain.Card) {
+	if card.Mode != domain.SingleUseCard || domain.ParseCardStatus(card.Status) == domain.CardCanceled {
+		return
+	}
 
-		if err != nil {
-			return err
-		}
+	reason := "single use card settled"
+	if err := syncIssuerStatus(ctx, ts.CardIssuers, card, domain.CardCanceled, reason); err != nil {
+		ts.logger.Error(err)
+		return
+	}
 
-		for _, transaction := range transactions {
-			transaction.Status = domain.FailedStatus
-			charges += transaction.Debit
-			ts.TransactionRepository.Persist(&transaction)
+	if err := transitionCard(ts.CardRepository, card, domain.CardCanceled, "system", reason); err != nil {
+		ts.logger.Error(err)
+	}
+}
 
-			newTransaction := &domain.Transaction{
-				Company:           transaction.Company,
-				Wallet:            wallet.ID,
-				Card:              transaction.Card,
-				PartnerCardID:     transaction.PartnerCardID,
-				Customer:          transaction.Customer,
-				PartnerCustomerID: transaction.PartnerCustomerID,
-				Debit:             transaction.Debit,
-				Note:              fmt.Sprintf("%v was refunded for failed transaction", transaction.Debit),
-				ReferenceID:       body.Id,
-				Status:            domain.SuccessStatus,
-				Entry:             domain.CreditEntry,
-				Channel:           transaction.Channel,
-				Type:              domain.RefundType,
-				ParentID:          transaction.ID.String(),
-			}
+// publishAuthorization tells the company whether the partner authorization request was approved or declined
+func (ts *transactionService) publishAuthorization(card *domain.Card, body *common.CreateTransactionRequest, err error) {
+	data := map[string]interface{}{
+		"card":          card.ID,
+		"authorization": body.Data.Object.Id,
+		"amount":        body.Data.Object.PendingRequest.Amount,
+		"currency":      body.Data.Object.PendingRequest.Currency,
+		"merchant":      body.Data.Object.Merchant.Name,
+		"channel":       body.Data.Object.TransactionMetadata.Channel,
+	}
 
-			ts.TransactionRepository.Persist(newTransaction)
+	if err != nil {
+		data["reason"] = err.Error()
+		publish(ts.EventPublisher, card.Company, domain.TransactionDeclinedEvent, data)
+		return
+	}
+	publish(ts.EventPublisher, card.Company, domain.TransactionAuthorizedEvent, data)
+}
 
-		}
+// ProcessIncrementalAuthorization debits the wallet for an increase on an existing authorization, e.g. hotels and car rentals
+func (ts *transactionService) ProcessIncrementalAuthorization(body *common.CreateTransactionRequest, card *domain.Card, transaction *domain.Transaction, feeRate float64, wallet *domain.Wallet) error {
+	increment := float64(body.Data.Object.PendingRequest.Amount)
+	if increment <= 0 {
+		return errors.New("invalid incremental authorization amount")
+	}
 
-		chargesInKobo := utils.ToMinorUnit(charges)
+	processed, err := ts.TransactionRepository.GetAmountChangesByReference(body.Id)
+	if err != nil {
+		return err
+	}
 
-		ts.WalletService.CreditWallet(wallet, chargesInKobo)
+	if len(processed) > 0 {
+		return nil
 	}
 
-	return errors.New("invalid webhook")
-}
+	if transaction.VoidedAt != nil {
+		return errors.New("authorization was voided")
+	}
 
-func (ts *transactionService) UpdateTransaction(id string, body common.UpdateTransactionRequest) (*domain.Transaction, error) {
-	transaction, err := ts.TransactionRepository.GetByID(id)
-	if err != nil {
-		return nil, err
+	// an increment is evaluated like a new authorization of its amount
+	if err = ts.checkCardMode(card, body); err != nil {
+		return err
 	}
 
-	if body.Receipt != nil {
-		transaction.Receipt = *body.Receipt
+	if err = checkSchedule(card, time.Now()); err != nil {
+		return err
 	}
 
-	if body.ExpenseCategory != nil {
-		transaction.ExpenseCategory = *body.ExpenseCategory
+	if err = ts.FraudService.EvaluateAuthorization(card, body); err != nil {
+		return err
 	}
 
-	err = ts.TransactionRepository.Persist(transaction)
+	fee := feeRate / 100 * increment
+
+	fees, err := ts.TransactionRepository.GetBy(domain.Transaction{
+		AuthorizationID: transaction.AuthorizationID,
+		Card:            transaction.Card,
+		Type:            domain.FeeType,
+	})
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return transaction, nil
+	// the debit and the amount change are kept or rolled back together, a redelivered webhook finds the change
+	return inTransaction(ts.DB, func(txx *gorm.DB) error {
+		transactionRepository := ts.TransactionRepository.WithTx(txx)
+
+		if _, err := ts.WalletService.WithTx(txx).DebitWallet(wallet, utils.ToMinorUnit(increment+fee)); err != nil {
+			return err
+		}
+
+		if len(fees) > 0 {
+			fees[0].Debit += fee
+			fees[0].Note = fmt.Sprintf("%v was debitted for transaction fee", fees[0].Debit)
+			if err := transactionRepository.Persist(&fees[0]); err != nil {
+				return err
+			}
+		}
+
+		transaction.AuthorizedAmount += increment
+		return changeAmount(transactionRepository, transaction, transaction.Debit+increment, domain.IncrementalChange, body.Id)
+	})
 }
 
-func (ts *transactionService) DeleteTransaction(id string) error {
-	err := ts.TransactionRepository.Delete(id)
+// ProcessCapture settles an authorization for the final amount, adjusting the wallet by the difference, e.g. fuel pumps
+func (ts *transactionService) ProcessCapture(body *common.CreateTransactionRequest, transaction *domain.Transaction, wallet *domain.Wallet) error {
+	processed, err := ts.TransactionRepository.GetAmountChangesByReference(body.Id)
 	if err != nil {
-		ts.logger.Error(err)
 		return err
 	}
-	return nil
-}
 
-func (ts *transactionService) LockTransaction(id string) (*domain.Transaction, error) {
-	transaction, err := ts.TransactionRepository.GetByID(id)
+	if len(processed) > 0 {
+		return nil
+	}
+
+	if transaction.VoidedAt != nil {
+		return errors.New("authorization was voided")
+	}
+
+	settled := math.Abs(float64(body.Data.Object.Amount))
+	difference := settled - transaction.Debit
 
+	fees, err := ts.TransactionRepository.GetBy(domain.Transaction{
+		AuthorizationID: transaction.AuthorizationID,
+		Card:            transaction.Card,
+		Type:            domain.FeeType,
+	})
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	if transaction.Lock == false {
-		transaction.Lock = true
-	} else {
-		transaction.Lock = false
+	// the fee is charged on the settled amount at the rate of the authorization
+	for i := range fees {
+		fee := fees[i].Debit
+		if transaction.Debit > 0 {
+			fees[i].Debit = fee * settled / transaction.Debit
+		}
+		fees[i].Note = fmt.Sprintf("%v was debitted for transaction fee", fees[i].Debit)
+		fees[i].Status = domain.SuccessStatus
+		difference += fees[i].Debit - fee
 	}
 
-	err = ts.TransactionRepository.Persist(transaction)
+	payload := body.Data.Object
+	if payload.Currency != "" {
+		transaction.Currency = payload.Currency
+	}
+
+	if payload.MerchantCurrency != "" {
+		transaction.MerchantAmount = math.Abs(float64(payload.MerchantAmount))
+		transaction.MerchantCurrency = payload.MerchantCurrency
+	}
+
+	transaction.Vat = float64(payload.Vat)
+	transaction.PartnerFee = float64(payload.Fee)
+
+	transaction.Status = domain.SuccessStatus
+
+	// the wallet adjustment and the capture are kept or rolled back together, a redelivered webhook finds the change.
+	// The partner already settled the amount, it is booked even past the available credit of the wallet
+	return inTransaction(ts.DB, func(txx *gorm.DB) error {
+		transactionRepository := ts.TransactionRepository.WithTx(txx)
+		walletService := ts.WalletService.WithTx(txx)
+
+		if difference > 0 {
+			if _, err := walletService.OverdrawWallet(wallet, utils.ToMinorUnit(difference)); err != nil {
+				return err
+			}
+		} else if difference < 0 {
+			if _, err := walletService.CreditWallet(wallet, utils.ToMinorUnit(-difference)); err != nil {
+				return err
+			}
+		}
+
+		for i := range fees {
+			if err := transactionRepository.Persist(&fees[i]); err != nil {
+				return err
+			}
+		}
+
+		if len(payload.FeeDetails) > 0 {
+			details := partnerFeeDetails(body)
+			for i := range details {
+				details[i].Transaction = transaction.ID
+			}
 
+			if err := transactionRepository.ReplaceFeeDetails(transaction.ID.String(), details); err != nil {
+				return err
+			}
+		}
+
+		return changeAmount(transactionRepository, transaction, settled, domain.CaptureChange, body.Id)
+	})
+}
+
+// failAuthorization fails the authorization and its fee when the partner could not settle it
+func (ts *transactionService) failAuthorization(transaction *domain.Transaction, reference string, wallet *domain.Wallet) error {
+	transactions, err := ts.TransactionRepository.GetBy(domain.Transaction{
+		AuthorizationID: transaction.AuthorizationID,
+		Card:            transaction.Card,
+	})
 	if err != nil {
-		ts.logger.Error(err)
-		return nil, err
+		return err
+	}
+
+	var held []domain.Transaction
+	for _, t := range transactions {
+		if t.Type == domain.WithdrawalType || t.Type == domain.FeeType {
+			held = append(held, t)
+		}
+	}
+
+	if err = ts.failTransactions(held, reference, wallet); err != nil {
+		return err
+	}
+
+	// a single use card whose only authorization failed can be used again
+	return ts.CardRepository.ReleaseSingleUse(transaction.Card.String(), transaction.AuthorizationID)
+}
+
+// failTransactions marks the transactions failed and refunds what they debited, failed and voided ones are skipped
+// so a redelivered webhook or a void is not refunded twice
+func (ts *transactionService) failTransactions(transactions []domain.Transaction, reference string, wallet *domain.Wallet) error {
+	return inTransaction(ts.DB, func(txx *gorm.DB) error {
+		transactionRepository := ts.TransactionRepository.WithTx(txx)
+
+		var charges float64
+		for _, transaction := range transactions {
+			if transaction.Status == domain.FailedStatus || transaction.VoidedAt != nil {
+				continue
+			}
+
+			transaction.Status = domain.FailedStatus
+			charges += transaction.Debit
+			if err := transactionRepository.Persist(&transaction); err != nil {
+				return err
+			}
+
+			newTransaction := &domain.Transaction{
+				Company:           transaction.Company,
+				Wallet:            wallet.ID,
+				Card:              transaction.Card,
+				PartnerCardID:     transaction.PartnerCardID,
+				Customer:          transaction.Customer,
+				PartnerCustomerID: transaction.PartnerCustomerID,
+				Debit:             transaction.Debit,
+				Note:              fmt.Sprintf("%v was refunded for failed transaction", transaction.Debit),
+				ReferenceID:       reference,
+				Status:            domain.SuccessStatus,
+				Entry:             domain.CreditEntry,
+				Channel:           transaction.Channel,
+				Type:              domain.RefundType,
+				ParentID:          transaction.ID.String(),
+			}
+
+			if err := transactionRepository.Persist(newTransaction); err != nil {
+				return err
+			}
+		}
+
+		if charges == 0 {
+			return nil
+		}
+
+		_, err := ts.WalletService.WithTx(txx).CreditWallet(wallet, utils.ToMinorUnit(charges))
+		return err
+	})
+}
+
+// partnerFeeDetails maps the fee lines the partner charged on the webhook transaction
+func partnerFeeDetails(body *common.CreateTransactionRequest) []domain.PartnerFeeDetail {
+	var details []domain.PartnerFeeDetail
+	for _, fee := range body.Data.Object.FeeDetails {
+		details = append(details, domain.PartnerFeeDetail{
+			Contract:    fee.Contract,
+			Currency:    fee.Currency,
+			Amount:      float64(fee.Amount),
+			Description: fee.Description,
+		})
+	}
+	return details
+}
+
+func changeAmount(transactionRepository ports.ITransactionRepository, transaction *domain.Transaction, amount float64, changeType domain.AmountChangeType, reference string) error {
+	change := &domain.AmountChange{
+		Transaction:    transaction.ID,
+		Type:           changeType,
+		PreviousAmount: transaction.Debit,
+		Amount:         amount,
+		Difference:     amount - transaction.Debit,
+		ReferenceID:    reference,
+	}
+
+	transaction.Debit = amount
+	transaction.Note = fmt.Sprintf("%v was debitted for transaction", amount)
+
+	if err := transactionRepository.Persist(transaction); err != nil {
+		return err
+	}
+
+	return transactionRepository.PersistAmountChange(change)
+}
+
+// ProcessRefund credits a merchant refund, reversal or partial reversal back to the wallet as a REFUND transaction linked to the original purchase
+func (ts *transactionService) ProcessRefund(body *common.CreateTransactionRequest, card *domain.Card, customer *domain.Customer, wallet *domain.Wallet) error {
+	payload := body.Data.Object
+
+	if payload.Authorization == "" {
+		return errors.New("original transaction not found")
+	}
+
+	processed, err := ts.TransactionRepository.GetBy(domain.Transaction{ReferenceID: body.Id, Type: domain.RefundType})
+	if err != nil {
+		return err
+	}
+
+	if len(processed) > 0 {
+		return nil
+	}
+
+	originals, err := ts.TransactionRepository.GetBy(domain.Transaction{
+		AuthorizationID: payload.Authorization,
+		Card:            card.ID,
+		Type:            domain.WithdrawalType,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(originals) == 0 {
+		return errors.New("original transaction not found")
+	}
+
+	original := originals[0]
+	if original.Status == domain.FailedStatus {
+		return errors.New("original transaction already failed")
+	}
+
+	if original.VoidedAt != nil {
+		return errors.New("original transaction was voided")
+	}
+
+	amount := math.Abs(float64(payload.Amount))
+
+	refunds, err := ts.TransactionRepository.GetRefundsByParentID([]string{original.ID.String()})
+	if err != nil {
+		return err
+	}
+
+	var refunded float64
+	for _, refund := range refunds {
+		refunded += refund.Debit
+	}
+
+	// what open and won disputes credited back was already returned to the wallet and is not refunded twice
+	credited, err := disputeCredits(ts.TransactionRepository, &original)
+	if err != nil {
+		return err
+	}
+
+	if amount <= 0 || refunded+credited+amount > original.Debit {
+		return errors.New("refund exceeds the original transaction amount")
+	}
+
+	refund := &domain.Transaction{
+		Company:           original.Company,
+		Wallet:            wallet.ID,
+		Card:              card.ID,
+		PartnerCardID:     card.PartnerCardID,
+		Customer:          customer.ID,
+		PartnerCustomerID: customer.PartnerCustomerID,
+		Debit:             amount,
+		Note:              fmt.Sprintf("%v was refunded by merchant", amount),
+		ReferenceID:       body.Id,
+		Status:            domain.SuccessStatus,
+		Entry:             domain.CreditEntry,
+		Channel:           original.Channel,
+		Reason:            strings.ToLower(strings.TrimSpace(body.Type)),
+		Type:              domain.RefundType,
+		CardType:          original.CardType,
+		ParentID:          original.ID.String(),
+		AuthorizationID:   payload.Id,
+		Currency:          payload.Currency,
+		MerchantAmount:    math.Abs(float64(payload.MerchantAmount)),
+		MerchantCurrency:  payload.MerchantCurrency,
+		Vat:               math.Abs(float64(payload.Vat)),
+	}
+
+	// the refund row and the credit are kept or rolled back together, a redelivered webhook finds the row
+	return inTransaction(ts.DB, func(txx *gorm.DB) error {
+		if err := ts.TransactionRepository.WithTx(txx).Persist(refund); err != nil {
+			return err
+		}
+
+		_, err := ts.WalletService.WithTx(txx).CreditWallet(wallet, utils.ToMinorUnit(amount))
+		return err
+	})
+}
+
+// disputeCredits what disputes of the transaction still have credited back, the provisional credits of open and won
+// disputes less the reversals of lost and withdrawn ones
+func disputeCredits(transactionRepository ports.ITransactionRepository, transaction *domain.Transaction) (float64, error) {
+	rows, err := transactionRepository.GetBy(domain.Transaction{ParentID: transaction.ID.String(), Type: domain.DisputeType})
+	if err != nil {
+		return 0, err
+	}
+
+	var credited float64
+	for _, row := range rows {
+		if row.Entry == domain.CreditEntry {
+			credited += row.Debit
+		} else {
+			credited -= row.Debit
+		}
+	}
+	return credited, nil
+}
+
+func (ts *transactionService) applyRefunds(transactions []domain.Transaction) error {
+	var ids []string
+	index := map[string]int{}
+	for i := range transactions {
+		if transactions[i].Type == domain.WithdrawalType {
+			ids = append(ids, transactions[i].ID.String())
+			index[transactions[i].ID.String()] = i
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	refunds, err := ts.TransactionRepository.GetRefundsByParentID(ids)
+	if err != nil {
+		return err
+	}
+
+	for _, refund := range refunds {
+		transaction := &transactions[index[refund.ParentID]]
+		transaction.Refunds = append(transaction.Refunds, refund)
+		transaction.RefundedAmount += refund.Debit
+	}
+	return nil
+}
+
+// EnforceReceiptPolicy locks the card once it has as many overdue receipts as the company policy allows
+func (ts *transactionService) EnforceReceiptPolicy(card *domain.Card) error {
+	policy, err := ts.ReceiptPolicyRepository.GetByCompany(card.Company.String())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if !policy.AutoLockCard || policy.MaxOverdueReceipts < 1 {
+		return nil
+	}
+
+	overdueBefore := time.Now().AddDate(0, 0, -policy.GraceDays)
+
+	count, err := ts.TransactionRepository.CountOverdueReceiptsByCardID(card.ID.String(), policy, overdueBefore)
+	if err != nil {
+		return err
+	}
+
+	if count < int64(policy.MaxOverdueReceipts) {
+		return nil
+	}
+
+	err = lockCardForSystem(ts.CardIssuers, ts.CardRepository, card, "system:receipt_policy", fmt.Sprintf("%v overdue receipts", count), ts.logger)
+	if err != nil {
+		return err
+	}
+
+	return fmt.Errorf("card locked: %v overdue receipts", count)
+}
+
+// setReceiptStatus a company without a receipt policy requires no receipts
+func (ts *transactionService) setReceiptStatus(transaction *domain.Transaction, policies map[uuid.UUID]*domain.ReceiptPolicy, now time.Time) error {
+	policy, ok := policies[transaction.Company]
+	if !ok {
+		var err error
+		policy, err = ts.ReceiptPolicyRepository.GetByCompany(transaction.Company.String())
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		policies[transaction.Company] = policy
+	}
+	transaction.ReceiptStatus = ReceiptStatus(policy, transaction, now)
+	return nil
+}
+
+func (ts *transactionService) applyReceiptStatus(transactions []domain.Transaction) error {
+	policies := map[uuid.UUID]*domain.ReceiptPolicy{}
+	now := time.Now()
+	for i := range transactions {
+		if err := ts.setReceiptStatus(&transactions[i], policies, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ts *transactionService) ProcessTransactionState(body *common.CreateTransactionRequest, wallet *domain.Wallet) error {
+	if strings.ToLower(strings.TrimSpace(body.Data.Object.Status)) == "approved" {
+		transactionEntity := domain.Transaction{
+			ReferenceID: body.Id,
+		}
+
+		transactions, err := ts.TransactionRepository.GetBy(transactionEntity)
+
+		if err != nil {
+			return err
+		}
+
+		for _, transaction := range transactions {
+			transaction.Status = domain.SuccessStatus
+			ts.TransactionRepository.Persist(&transaction)
+		}
+
+		return nil
+
+	} else if strings.ToLower(strings.TrimSpace(body.Data.Object.Status)) == "failed" {
+		transactionEntity := domain.Transaction{
+			ReferenceID: body.Id,
+		}
+
+		transactions, err := ts.TransactionRepository.GetBy(transactionEntity)
+
+		if err != nil {
+			return err
+		}
+
+		var held []domain.Transaction
+		for _, transaction := range transactions {
+			if transaction.Entry == domain.DebitEntry {
+				held = append(held, transaction)
+			}
+		}
+
+		return ts.failTransactions(held, body.Id, wallet)
+	}
+
+	return errors.New("invalid webhook")
+}
+
+func (ts *transactionService) UpdateTransaction(id string, body common.UpdateTransactionRequest) (*domain.Transaction, error) {
+	transaction, err := ts.TransactionRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = ts.ensureMutable(transaction); err != nil {
+		return nil, err
+	}
+
+	if body.Receipt != nil {
+		transaction.Receipt = *body.Receipt
+	}
+
+	if body.ExpenseCategory != nil {
+		transaction.ExpenseCategory = *body.ExpenseCategory
+	}
+
+	err = ts.TransactionRepository.Persist(transaction)
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// VoidTransaction offsets a transaction with a REVERSAL entry and gives the wallet back what the transaction moved, the original is kept and marked voided
+func (ts *transactionService) VoidTransaction(id string, body common.VoidTransactionRequest) (*domain.Transaction, error) {
+	transaction, err := ts.TransactionRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = ts.ensureMutable(transaction); err != nil {
+		return nil, err
+	}
+
+	if transaction.VoidedAt != nil {
+		return nil, errors.New("transaction is already voided")
+	}
+
+	if transaction.Type == domain.ReversalType {
+		return nil, errors.New("a reversal cannot be voided")
+	}
+
+	if transaction.Status == domain.FailedStatus || transaction.Status == domain.CancelledStatus {
+		return nil, errors.New("only pending or successful transactions can be voided")
+	}
+
+	entry := domain.CreditEntry
+	if transaction.Entry == domain.CreditEntry {
+		entry = domain.DebitEntry
+	}
+
+	// the debit holds the captured amount, merchant refunds already credited back are not reversed again. A charge
+	// with an open or won dispute keeps its provisional credit, it is settled by the dispute and not by a void
+	outstanding := transaction.Debit
+	if transaction.Type == domain.WithdrawalType {
+		credited, err := disputeCredits(ts.TransactionRepository, transaction)
+		if err != nil {
+			return nil, err
+		}
+
+		if credited > 0 {
+			return nil, errors.New("transaction has an open or won dispute, it can not be voided")
+		}
+
+		refunds, err := ts.TransactionRepository.GetRefundsByParentID([]string{transaction.ID.String()})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, refund := range refunds {
+			outstanding -= refund.Debit
+		}
+	}
+
+	if outstanding <= 0 {
+		return nil, errors.New("transaction was fully refunded, nothing to void")
+	}
+
+	// adjusting entries only correct the books, every other transaction moved wallet money
+	var wallet *domain.Wallet
+	if transaction.Type != domain.AdjustmentType && !uuid.Equal(transaction.Wallet, uuid.Nil) {
+		wallet, err = ts.WalletRepository.GetByID(transaction.Wallet.String())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	reversal := &domain.Transaction{
+		Company:           transaction.Company,
+		Wallet:            transaction.Wallet,
+		Card:              transaction.Card,
+		PartnerCardID:     transaction.PartnerCardID,
+		Customer:          transaction.Customer,
+		PartnerCustomerID: transaction.PartnerCustomerID,
+		Debit:             outstanding,
+		Note:              fmt.Sprintf("%v was reversed for voided transaction", outstanding),
+		ReferenceID:       "void-" + transaction.ID.String(),
+		Status:            domain.SuccessStatus,
+		Entry:             entry,
+		Channel:           transaction.Channel,
+		Reason:            body.Reason,
+		Type:              domain.ReversalType,
+		CardType:          transaction.CardType,
+		ParentID:          transaction.ID.String(),
+		ExpenseCategory:   transaction.ExpenseCategory,
+	}
+
+	now := time.Now()
+	transaction.VoidedAt = &now
+	transaction.VoidReason = body.Reason
+
+	// the wallet move and the reversal rows are kept or rolled back together
+	err = inTransaction(ts.DB, func(txx *gorm.DB) error {
+		if wallet != nil {
+			walletService := ts.WalletService.WithTx(txx)
+			if entry == domain.CreditEntry {
+				if _, err := walletService.CreditWallet(wallet, utils.ToMinorUnit(outstanding)); err != nil {
+					return err
+				}
+			} else if _, err := walletService.DebitWallet(wallet, utils.ToMinorUnit(outstanding)); err != nil {
+				return err
+			}
+		}
+
+		transactionRepository := ts.TransactionRepository.WithTx(txx)
+		if err := transactionRepository.Persist(reversal); err != nil {
+			return err
+		}
+		return transactionRepository.Persist(transaction)
+	})
+	if err != nil {
+		ts.logger.Error(err)
+		return nil, err
+	}
+	return reversal, nil
+}
+
+func (ts *transactionService) LockTransaction(id string) (*domain.Transaction, error) {
+	transaction, err := ts.TransactionRepository.GetByID(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// locking and unlocking are changes too, a transaction of a closed period stays as it was closed
+	if err = ts.ensureOpenPeriod(transaction); err != nil {
+		return nil, err
+	}
+
+	if transaction.Lock == false {
+		transaction.Lock = true
+	} else {
+		transaction.Lock = false
+	}
+
+	err = ts.TransactionRepository.Persist(transaction)
+
+	if err != nil {
+		ts.logger.Error(err)
+		return nil, err
 	}
 	return transaction, nil
 }
+
+// AdjustTransaction posts an adjusting entry against a transaction in the current period, it corrects the books only and does not move wallet money
+func (ts *transactionService) AdjustTransaction(id string, body common.AdjustTransactionRequest) (*domain.Transaction, error) {
+	transaction, err := ts.TransactionRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := domain.TransactionEntry(strings.ToUpper(strings.TrimSpace(body.Entry)))
+	if entry != domain.DebitEntry && entry != domain.CreditEntry {
+		return nil, errors.New("entry must be DEBIT or CREDIT")
+	}
+
+	closed, err := ts.PeriodRepository.IsClosed(transaction.Company.String(), time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if closed {
+		return nil, domain.ErrPeriodClosed
+	}
+
+	category := body.ExpenseCategory
+	if category == "" {
+		category = transaction.ExpenseCategory
+	}
+
+	adjustment := &domain.Transaction{
+		Company:           transaction.Company,
+		Wallet:            transaction.Wallet,
+		Card:              transaction.Card,
+		PartnerCardID:     transaction.PartnerCardID,
+		Customer:          transaction.Customer,
+		PartnerCustomerID: transaction.PartnerCustomerID,
+		Debit:             body.Amount,
+		Note:              body.Note,
+		Status:            domain.SuccessStatus,
+		Entry:             entry,
+		Channel:           transaction.Channel,
+		Reason:            fmt.Sprintf("adjustment of %v", transaction.ID),
+		Type:              domain.AdjustmentType,
+		CardType:          transaction.CardType,
+		ParentID:          transaction.ID.String(),
+		ExpenseCategory:   category,
+	}
+
+	if err = ts.TransactionRepository.Persist(adjustment); err != nil {
+		ts.logger.Error(err)
+		return nil, err
+	}
+	return adjustment, nil
+}
+
+// ensureMutable rejects changes to locked transactions and transactions of a closed accounting period
+func (ts *transactionService) ensureMutable(transaction *domain.Transaction) error {
+	if transaction.Lock {
+		return domain.ErrTransactionLocked
+	}
+	return ts.ensureOpenPeriod(transaction)
+}
+
+// ensureOpenPeriod rejects changes to transactions of a closed accounting period
+func (ts *transactionService) ensureOpenPeriod(transaction *domain.Transaction) error {
+	closed, err := ts.PeriodRepository.IsClosed(transaction.Company.String(), transaction.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	if closed {
+		return domain.ErrPeriodClosed
+	}
+	return nil
+}
+
+// SplitTransaction replaces the expense category splits of a transaction, the lines must sum to the transaction amount
+func (ts *transactionService) SplitTransaction(id string, body common.SplitTransactionRequest) (*domain.Transaction, error) {
+	transaction, err := ts.TransactionRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = ts.ensureMutable(transaction); err != nil {
+		return nil, err
+	}
+
+	if transaction.Entry != domain.DebitEntry {
+		return nil, errors.New("only debit transactions can be split")
+	}
+
+	var (
+		splits []domain.TransactionSplit
+		total  float64
+	)
+
+	for _, line := range body.Splits {
+		total += line.Amount
+		splits = append(splits, domain.TransactionSplit{
+			Transaction: transaction.ID,
+			Amount:      line.Amount,
+			Category:    strings.TrimSpace(line.Category),
+			Memo:        line.Memo,
+			CostCenter:  line.CostCenter,
+		})
+	}
+
+	// compare to the kobo, float sums of naira amounts are not exact
+	if len(splits) > 0 && math.Abs(total-transaction.Debit) >= 0.005 {
+		return nil, fmt.Errorf("splits sum to %v but the transaction amount is %v", total, transaction.Debit)
+	}
+
+	err = ts.TransactionRepository.ReplaceSplits(transaction.ID.String(), splits)
+	if err != nil {
+		ts.logger.Error(err)
+		return nil, err
+	}
+
+	return ts.GetTransactionByID(id)
+}
+
+func (ts *transactionService) GetCategoryTotalsByCompanyID(id string) ([]domain.CategoryTotal, error) {
+	totals, err := ts.TransactionRepository.GetCategoryTotalsByCompanyID(id)
+	if err != nil {
+		ts.logger.Error(err)
+		return nil, err
+	}
+	return totals, nil
+}
+
+// ExportTransactionsByCompanyID the successful transactions of the period as a CSV for the accounting system, a split
+// transaction is exported as one line per split so every line carries a single category, the merchant amount, VAT
+// and partner fees are on the first line only so the columns sum per transaction
+func (ts *transactionService) ExportTransactionsByCompanyID(id string, query common.ExportTransactionsRequest) ([]byte, error) {
+	to := query.To
+	if to.IsZero() {
+		to = time.Now()
+	}
+
+	from := query.From
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -30)
+	}
+
+	if !from.Before(to) {
+		return nil, errors.New("from must be before to")
+	}
+
+	transactions, err := ts.TransactionRepository.GetForExport(id, from, to)
+	if err != nil {
+		ts.logger.Error(err)
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+
+	header := []string{"date", "transaction", "reference", "type", "entry", "merchant", "category", "memo", "cost_center",
+		"amount", "currency", "merchant_amount", "merchant_currency", "vat", "partner_fee", "fee_details", "tags"}
+	if err = writer.Write(header); err != nil {
+		return nil, err
+	}
+
+	for _, transaction := range transactions {
+		var tags []string
+		for _, tag := range transaction.Tags {
+			tags = append(tags, tag.Name)
+		}
+
+		var fees []string
+		for _, fee := range transaction.FeeDetails {
+			fees = append(fees, fmt.Sprintf("%v %v %v", fee.Description, strconv.FormatFloat(fee.Amount, 'f', 2, 64), fee.Currency))
+		}
+
+		first := true
+		line := func(category, memo, costCenter string, amount float64) []string {
+			var merchantAmount, merchantCurrency, vat, partnerFee, feeDetails string
+			if first {
+				merchantAmount = strconv.FormatFloat(transaction.MerchantAmount, 'f', 2, 64)
+				merchantCurrency = transaction.MerchantCurrency
+				vat = strconv.FormatFloat(transaction.Vat, 'f', 2, 64)
+				partnerFee = strconv.FormatFloat(transaction.PartnerFee, 'f', 2, 64)
+				feeDetails = strings.Join(fees, ";")
+				first = false
+			}
+
+			return []string{
+				transaction.CreatedAt.Format("2006-01-02"),
+				transaction.ID.String(),
+				transaction.ReferenceID,
+				string(transaction.Type),
+				string(transaction.Entry),
+				transaction.MerchantName,
+				category,
+				memo,
+				costCenter,
+				strconv.FormatFloat(amount, 'f', 2, 64),
+				transaction.Currency,
+				merchantAmount,
+				merchantCurrency,
+				vat,
+				partnerFee,
+				feeDetails,
+				strings.Join(tags, ";"),
+			}
+		}
+
+		if len(transaction.Splits) == 0 {
+			if err = writer.Write(line(transaction.ExpenseCategory, "", "", transaction.Debit)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		for _, split := range transaction.Splits {
+			if err = writer.Write(line(split.Category, split.Memo, split.CostCenter, split.Amount)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	writer.Flush()
+	if err = writer.Error(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// TagTransaction sets the tags of a transaction, the tags must be defined by the company of the transaction
+func (ts *transactionService) TagTransaction(id string, body common.TagTransactionRequest) (*domain.Transaction, error) {
+	transaction, err := ts.TransactionRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []domain.Tag
+	if len(body.Tags) > 0 {
+		var ids []string
+		for _, tag := range body.Tags {
+			ids = append(ids, tag.String())
+		}
+
+		tags, err = ts.TagRepository.GetByIDs(ids)
+		if err != nil {
+			ts.logger.Error(err)
+			return nil, err
+		}
+
+		if len(tags) != len(ids) {
+			return nil, errors.New("tag not found")
+		}
+
+		for _, tag := range tags {
+			if tag.Company != transaction.Company {
+				return nil, fmt.Errorf("tag %v does not belong to the company of the transaction", tag.Name)
+			}
+		}
+	}
+
+	err = ts.TransactionRepository.ReplaceTags(transaction, tags)
+	if err != nil {
+		ts.logger.Error(err)
+		return nil, err
+	}
+
+	return ts.GetTransactionByID(id)
+}
+
+func (ts *transactionService) GetCommentsByTransactionID(id string, pagination *utils.Pagination) (*utils.Pagination, error) {
+	comments, err := ts.TransactionRepository.GetCommentsByTransactionID(id, pagination)
+	if err != nil {
+		ts.logger.Error(err)
+		return nil, err
+	}
+	return comments, nil
+}
+
+// CreateTransactionComment adds a comment of the author to the thread of the transaction
+func (ts *transactionService) CreateTransactionComment(id string, author string, body common.CreateTransactionCommentRequest) (*domain.TransactionComment, error) {
+	transaction, err := ts.TransactionRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &domain.TransactionComment{
+		Transaction: transaction.ID,
+		Author:      author,
+		Body:        strings.TrimSpace(body.Body),
+	}
+
+	err = ts.TransactionRepository.PersistComment(comment)
+	if err != nil {
+		ts.logger.Error(err)
+		return nil, err
+	}
+	return comment, nil
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"core_business/internals/common"
+	"core_business/internals/core/domain"
+	"core_business/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+const cardholderCreditLimit = 10000000
+
+func createRandomCardholder(t *testing.T, mode domain.CardMode) *domain.Card {
+	company := (&utils.Faker{}).RandomUUID()
+
+	customer := &domain.Customer{
+		Company:           company,
+		FirstName:         "Ada",
+		LastName:          "Obi",
+		PartnerCustomerID: (&utils.Faker{}).RandomObjectID(),
+	}
+	require.NoError(t, CustomerRepository.Persist(customer))
+
+	wallet := &domain.Wallet{
+		Company:         company,
+		CreditLimit:     cardholderCreditLimit,
+		AvailableCredit: cardholderCreditLimit,
+		Status:          true,
+	}
+	require.NoError(t, WalletRepository.Persist(wallet))
+
+	card := &domain.Card{
+		Company:       company,
+		Customer:      customer.ID,
+		Name:          (&utils.Faker{}).RandomName(),
+		Status:        string(domain.CardActive),
+		Mode:          mode,
+		PartnerCardID: (&utils.Faker{}).RandomObjectID(),
+		MaskedPan:     "506321*******1234",
+		ExpiryMonth:   "12",
+		ExpiryYear:    "2030",
+	}
+	require.NoError(t, CardRepository.Persist(card))
+
+	return card
+}
+
+// spent how much of its credit limit the wallet of the card used, in kobo
+func spent(t *testing.T, card *domain.Card) int64 {
+	wallet, err := WalletRepository.GetByCompany(card.Company.String())
+	require.NoError(t, err)
+	return cardholderCreditLimit - wallet.AvailableCredit
+}
+
+func authorize(card *domain.Card, id string, authorization string, amount int, merchant string) error {
+	body := &common.CreateTransactionRequest{Id: id, Type: "authorization.request"}
+	body.Data.Object.Id = authorization
+	body.Data.Object.Card.Id = card.PartnerCardID
+	body.Data.Object.Card.Type = "virtual"
+	body.Data.Object.TransactionMetadata.Channel = "web"
+	body.Data.Object.PendingRequest.Amount = amount
+	body.Data.Object.PendingRequest.Currency = "NGN"
+	body.Data.Object.Merchant.Name = merchant
+	body.Data.Object.Merchant.MerchantId = merchant
+	return TransactionService.CreateTransaction(context.Background(), body)
+}
+
+func settle(card *domain.Card, id string, authorization string, amount int, status string) error {
+	body := &common.CreateTransactionRequest{Id: id, Type: "transaction.created"}
+	body.Data.Object.Id = id
+	body.Data.Object.Authorization = authorization
+	body.Data.Object.Card.Id = card.PartnerCardID
+	body.Data.Object.Amount = -amount
+	body.Data.Object.Status = status
+	return TransactionService.CreateTransaction(context.Background(), body)
+}
+
+func refund(card *domain.Card, id string, authorization string, amount int) error {
+	body := &common.CreateTransactionRequest{Id: id, Type: "transaction.refund"}
+	body.Data.Object.Id = id
+	body.Data.Object.Authorization = authorization
+	body.Data.Object.Card.Id = card.PartnerCardID
+	body.Data.Object.Amount = amount
+	return TransactionService.CreateTransaction(context.Background(), body)
+}
+
+func authorized(t *testing.T, card *domain.Card, authorization string, kind domain.TransactionType) domain.Transaction {
+	transactions, err := TransactionRepository.GetBy(domain.Transaction{AuthorizationID: authorization, Card: card.ID, Type: kind})
+	require.NoError(t, err)
+	require.Len(t, transactions, 1)
+	return transactions[0]
+}
+
+func TestCaptureIsIdempotent(t *testing.T) {
+	card := createRandomCardholder(t, domain.GeneralCard)
+
+	require.NoError(t, authorize(card, "auth-event-"+card.PartnerCardID, "auth-"+card.PartnerCardID, 1000, "fuel"))
+	require.Equal(t, utils.ToMinorUnit(1010), spent(t, card))
+
+	// the capture debits the difference and the fee on it once, the redelivered webhook finds its amount change
+	for i := 0; i < 2; i++ {
+		require.NoError(t, settle(card, "capture-event-"+card.PartnerCardID, "auth-"+card.PartnerCardID, 1500, "approved"))
+		require.Equal(t, utils.ToMinorUnit(1515), spent(t, card))
+	}
+
+	withdrawal := authorized(t, card, "auth-"+card.PartnerCardID, domain.WithdrawalType)
+	require.Equal(t, float64(1500), withdrawal.Debit)
+	require.Equal(t, domain.SuccessStatus, withdrawal.Status)
+}
+
+func TestIncrementIsIdempotent(t *testing.T) {
+	card := createRandomCardholder(t, domain.GeneralCard)
+
+	require.NoError(t, authorize(card, "auth-event-"+card.PartnerCardID, "auth-"+card.PartnerCardID, 1000, "hotel"))
+
+	// the increment and its fee are debited once, the redelivered webhook finds its amount change
+	for i := 0; i < 2; i++ {
+		require.NoError(t, authorize(card, "increment-event-"+card.PartnerCardID, "auth-"+card.PartnerCardID, 500, "hotel"))
+		require.Equal(t, utils.ToMinorUnit(1515), spent(t, card))
+	}
+
+	withdrawal := authorized(t, card, "auth-"+card.PartnerCardID, domain.WithdrawalType)
+	require.Equal(t, float64(1500), withdrawal.Debit)
+	require.Equal(t, float64(1500), withdrawal.AuthorizedAmount)
+	require.Equal(t, float64(15), authorized(t, card, "auth-"+card.PartnerCardID, domain.FeeType).Debit)
+}
+
+func TestFailedSettlementRefundsAuthorization(t *testing.T) {
+	card := createRandomCardholder(t, domain.GeneralCard)
+
+	require.NoError(t, authorize(card, "auth-event-"+card.PartnerCardID, "auth-"+card.PartnerCardID, 1000, "airline"))
+	require.Equal(t, utils.ToMinorUnit(1010), spent(t, card))
+
+	// the authorization and its fee are refunded once, the redelivered webhook finds them failed
+	for i := 0; i < 2; i++ {
+		require.NoError(t, settle(card, "failed-event-"+card.PartnerCardID, "auth-"+card.PartnerCardID, 1000, "failed"))
+		require.Equal(t, int64(0), spent(t, card))
+	}
+
+	require.Equal(t, domain.FailedStatus, authorized(t, card, "auth-"+card.PartnerCardID, domain.WithdrawalType).Status)
+
+	refunds, err := TransactionRepository.GetBy(domain.Transaction{ReferenceID: "failed-event-" + card.PartnerCardID, Type: domain.RefundType})
+	require.NoError(t, err)
+	require.Len(t, refunds, 2)
+}
+
+func TestVoidAfterRefund(t *testing.T) {
+	card := createRandomCardholder(t, domain.GeneralCard)
+
+	require.NoError(t, authorize(card, "auth-event-"+card.PartnerCardID, "auth-"+card.PartnerCardID, 1000, "store"))
+	require.NoError(t, settle(card, "capture-event-"+card.PartnerCardID, "auth-"+card.PartnerCardID, 1000, "approved"))
+	require.NoError(t, refund(card, "refund-event-"+card.PartnerCardID, "auth-"+card.PartnerCardID, 400))
+	require.Equal(t, utils.ToMinorUnit(610), spent(t, card))
+
+	// only what the merchant did not refund is credited back, the fee stays charged
+	withdrawal := authorized(t, card, "auth-"+card.PartnerCardID, domain.WithdrawalType)
+	reversal, err := TransactionService.VoidTransaction(withdrawal.ID.String(), common.VoidTransactionRequest{Reason: "duplicate"})
+	require.NoError(t, err)
+	require.Equal(t, float64(600), reversal.Debit)
+	require.Equal(t, "void-"+withdrawal.ID.String(), reversal.ReferenceID)
+	require.Equal(t, utils.ToMinorUnit(10), spent(t, card))
+
+	_, err = TransactionService.VoidTransaction(withdrawal.ID.String(), common.VoidTransactionRequest{Reason: "duplicate"})
+	require.Error(t, err)
+	require.Equal(t, utils.ToMinorUnit(10), spent(t, card))
+
+	// a refund of the voided transaction is not credited again
+	require.Error(t, refund(card, "late-refund-event-"+card.PartnerCardID, "auth-"+card.PartnerCardID, 100))
+	require.Equal(t, utils.ToMinorUnit(10), spent(t, card))
+}
+
+func TestMerchantLockDecline(t *testing.T) {
+	card := createRandomCardholder(t, domain.MerchantLockedCard)
+
+	require.NoError(t, authorize(card, "first-event-"+card.PartnerCardID, "first-"+card.PartnerCardID, 1000, "grocer"))
+	require.Equal(t, utils.ToMinorUnit(1010), spent(t, card))
+
+	locked, err := CardRepository.GetByID(card.ID.String())
+	require.NoError(t, err)
+	require.Equal(t, "grocer", locked.LockedMerchantID)
+
+	err = authorize(card, "other-event-"+card.PartnerCardID, "other-"+card.PartnerCardID, 1000, "casino")
+	require.ErrorIs(t, err, domain.ErrMerchantNotAllowed)
+	require.Equal(t, utils.ToMinorUnit(1010), spent(t, card))
+
+	withdrawals, err := TransactionRepository.GetBy(domain.Transaction{AuthorizationID: "other-" + card.PartnerCardID})
+	require.NoError(t, err)
+	require.Empty(t, withdrawals)
+}
+
+func TestCaptureOverdrawsWallet(t *testing.T) {
+	card := createRandomCardholder(t, domain.GeneralCard)
+
+	require.NoError(t, authorize(card, "auth-event-"+card.PartnerCardID, "auth-"+card.PartnerCardID, 1000, "fuel"))
+
+	// the settlement is past the credit limit, the partner already paid it so it is booked anyway
+	require.NoError(t, settle(card, "capture-event-"+card.PartnerCardID, "auth-"+card.PartnerCardID, 200000, "approved"))
+	require.Equal(t, utils.ToMinorUnit(202000), spent(t, card))
+
+	withdrawal := authorized(t, card, "auth-"+card.PartnerCardID, domain.WithdrawalType)
+	require.Equal(t, domain.SuccessStatus, withdrawal.Status)
+	require.Equal(t, float64(200000), withdrawal.Debit)
+
+	// the fee is charged on the settled amount
+	fee := authorized(t, card, "auth-"+card.PartnerCardID, domain.FeeType)
+	require.Equal(t, domain.SuccessStatus, fee.Status)
+	require.Equal(t, float64(2000), fee.Debit)
+}
@@ -13,19 +13,33 @@ import (
 
 type walletService struct {
 	WalletRepository ports.IWalletRepository
+	OutboxRepository ports.IOutboxRepository
 	DB               *gorm.DB
+	tx               *gorm.DB // set when the balance moves as part of a caller transaction
 	logger           *log.Logger
 }
 
 // NewWalletService function create a new instance for service
-func NewWalletService(cr ports.IWalletRepository, db *gorm.DB, l *log.Logger) ports.IWalletService {
+func NewWalletService(cr ports.IWalletRepository, or ports.IOutboxRepository, db *gorm.DB, l *log.Logger) ports.IWalletService {
 	return &walletService{
 		WalletRepository: cr,
+		OutboxRepository: or,
 		DB:               db,
 		logger:           l,
 	}
 }
 
+// WithTx moves balances inside the caller transaction, the caller commits or rolls back
+func (ws *walletService) WithTx(txx *gorm.DB) ports.IWalletService {
+	return &walletService{
+		WalletRepository: ws.WalletRepository,
+		OutboxRepository: ws.OutboxRepository,
+		DB:               ws.DB,
+		tx:               txx,
+		logger:           ws.logger,
+	}
+}
+
 func (ws *walletService) GetWalletByID(id string) (*domain.Wallet, error) {
 	wallet, err := ws.WalletRepository.GetByID(id)
 	if err != nil {
@@ -43,13 +57,30 @@ func (ws *walletService) CreateWallet(wallet *domain.Wallet) error {
 	return nil
 }
 
-func (ws *walletService) DeleteWallet(id string) error {
-	err := ws.WalletRepository.Delete(id)
+// CloseWallet closes a settled wallet instead of deleting it, the wallet and its history are kept
+func (ws *walletService) CloseWallet(id string, body common.CloseWalletRequest) (*domain.Wallet, error) {
+	wallet, err := ws.WalletRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if wallet.Closed {
+		return nil, errors.New("wallet is already closed")
+	}
+
+	if wallet.TotalBalance != 0 {
+		return nil, errors.New("wallet has an outstanding balance, settle it before closing")
+	}
+
+	wallet.Closed = true
+	wallet.ClosedReason = body.Reason
+
+	err = ws.WalletRepository.Persist(wallet)
 	if err != nil {
 		ws.logger.Error(err)
-		return err
+		return nil, err
 	}
-	return nil
+	return wallet, nil
 }
 
 func (ws *walletService) UpdateWallet(id string, body common.UpdateWalletRequest) (*domain.Wallet, error) {
@@ -110,16 +141,51 @@ func (ws *walletService) CreditWallet(wallet *domain.Wallet, chargesInKobo int64
 	return wallet, nil
 }
 
+// OverdrawWallet debits a charge that already happened, e.g. a settlement or a lost dispute, the debit is booked even
+// past the available credit or on a closed wallet
+func (ws *walletService) OverdrawWallet(wallet *domain.Wallet, chargesInKobo int64) (*domain.Wallet, error) {
+	entryType := string(domain.DebitEntry)
+	walletEntity := common.UpdateWalletRequest{
+		CreditLimit:     &wallet.CreditLimit,
+		PreviousBalance: &wallet.PreviousBalance,
+		CurrentSpending: &wallet.CurrentSpending,
+		Entry:           &entryType,
+		Payment:         &chargesInKobo,
+		Overdraw:        true,
+	}
+
+	wallet, err := ws.UpdateBalance(wallet.ID.String(), walletEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	return wallet, nil
+}
+
 func (ws *walletService) UpdateBalance(id string, body common.UpdateWalletRequest) (*domain.Wallet, error) {
+	if ws.tx != nil {
+		return ws.updateBalance(ws.tx, id, body)
+	}
+
 	uw := tx.NewGormUnitOfWork(ws.DB)
 	txx, err := uw.Begin()
+	if err != nil {
+		return nil, err
+	}
 
-	defer func() {
-		if err != nil {
-			txx.Rollback()
-		}
-	}()
+	wallet, err := ws.updateBalance(txx, id, body)
+	if err != nil {
+		uw.Rollback()
+		return nil, err
+	}
+
+	if err = uw.Commit(); err != nil {
+		return nil, err
+	}
+	return wallet, nil
+}
 
+func (ws *walletService) updateBalance(txx *gorm.DB, id string, body common.UpdateWalletRequest) (*domain.Wallet, error) {
 	wallet, err := ws.WalletRepository.WithTx(txx).GetByIDForUpdate(id)
 	if err != nil {
 		ws.logger.Error(err)
@@ -127,7 +193,11 @@ func (ws *walletService) UpdateBalance(id string, body common.UpdateWalletReques
 	}
 
 	if strings.ToLower(*body.Entry) == "debit" {
-		if wallet.AvailableCredit > *body.Payment {
+		if wallet.Closed && !body.Overdraw {
+			return nil, errors.New("wallet is closed")
+		}
+
+		if body.Overdraw || wallet.AvailableCredit > *body.Payment {
 			wallet.CurrentSpending += *body.Payment
 			wallet.TotalBalance = wallet.CurrentSpending + (wallet.PreviousBalance - wallet.CashBackPayment)
 			wallet.AvailableCredit = wallet.CreditLimit - wallet.TotalBalance
@@ -148,6 +218,21 @@ func (ws *walletService) UpdateBalance(id string, body common.UpdateWalletReques
 		return nil, err
 	}
 
-	uw.Commit()
+	event := domain.WalletCredited
+	if strings.ToLower(*body.Entry) == "debit" {
+		event = domain.WalletDebited
+	}
+
+	err = recordEvent(ws.OutboxRepository.WithTx(txx), event, "wallet", wallet.ID, wallet.Company, map[string]interface{}{
+		"amount":           *body.Payment,
+		"available_credit": wallet.AvailableCredit,
+		"total_balance":    wallet.TotalBalance,
+	})
+
+	if err != nil {
+		ws.logger.Error(err)
+		return nil, err
+	}
+
 	return wallet, nil
 }
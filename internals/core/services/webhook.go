@@ -0,0 +1,286 @@
+package services
+
+import (
+	"core_business/internals/common"
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"core_business/pkg/utils"
+	"core_business/pkg/webhook"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	uuid "github.com/satori/go.uuid"
+	log "github.com/sirupsen/logrus"
+	"time"
+)
+
+type webhookService struct {
+	WebhookRepository ports.IWebhookRepository
+	Sender            *webhook.Sender
+	logger            *log.Logger
+}
+
+// NewWebhookService function create a new instance for service
+func NewWebhookService(wr ports.IWebhookRepository, s *webhook.Sender, l *log.Logger) ports.IWebhookService {
+	return &webhookService{
+		WebhookRepository: wr,
+		Sender:            s,
+		logger:            l,
+	}
+}
+
+func (ws *webhookService) GetWebhookByID(id string) (*domain.WebhookSubscription, error) {
+	subscription, err := ws.WebhookRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (ws *webhookService) GetWebhookByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error) {
+	subscriptions, err := ws.WebhookRepository.GetWebhookByCompanyID(id, pagination)
+	if err != nil {
+		ws.logger.Error(err)
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+func (ws *webhookService) CreateWebhook(body common.CreateWebhookRequest) (*domain.WebhookSubscription, error) {
+	eventTypes, err := toEventTypes(body.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := body.Secret
+	if secret == "" {
+		secret, err = generateSecret()
+		if err != nil {
+			ws.logger.Error(err)
+			return nil, err
+		}
+	}
+
+	subscription := &domain.WebhookSubscription{
+		Company:    body.Company,
+		URL:        body.URL,
+		EventTypes: eventTypes,
+		Secret:     secret,
+		Active:     true,
+	}
+
+	err = ws.WebhookRepository.Persist(subscription)
+	if err != nil {
+		ws.logger.Error(err)
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (ws *webhookService) UpdateWebhook(id string, body common.UpdateWebhookRequest) (*domain.WebhookSubscription, error) {
+	subscription, err := ws.WebhookRepository.GetByID(id)
+	if err != nil {
+		ws.logger.Error(err)
+		return nil, err
+	}
+
+	if body.URL != nil {
+		subscription.URL = *body.URL
+	}
+
+	if body.EventTypes != nil {
+		subscription.EventTypes, err = toEventTypes(*body.EventTypes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if body.Secret != nil {
+		subscription.Secret = *body.Secret
+	}
+
+	if body.Active != nil {
+		subscription.Active = *body.Active
+	}
+
+	err = ws.WebhookRepository.Persist(subscription)
+	if err != nil {
+		ws.logger.Error(err)
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (ws *webhookService) DeleteWebhook(id string) error {
+	err := ws.WebhookRepository.Delete(id)
+	if err != nil {
+		ws.logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (ws *webhookService) GetDeliveriesByWebhookID(id string, pagination *utils.Pagination) (*utils.Pagination, error) {
+	deliveries, err := ws.WebhookRepository.GetDeliveriesBySubscriptionID(id, pagination)
+	if err != nil {
+		ws.logger.Error(err)
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// Publish logs a delivery for every active subscription of the company listening to the event and sends them in the background
+func (ws *webhookService) Publish(company uuid.UUID, event domain.EventType, data interface{}) {
+	subscriptions, err := ws.WebhookRepository.GetActiveByCompany(company.String())
+	if err != nil {
+		ws.logger.Error(err)
+		return
+	}
+
+	for i := range subscriptions {
+		if !subscribes(&subscriptions[i], event) {
+			continue
+		}
+
+		payload, err := json.Marshal(common.WebhookEvent{
+			ID:        uuid.NewV4(),
+			Type:      string(event),
+			Company:   company,
+			CreatedAt: time.Now(),
+			Data:      data,
+		})
+		if err != nil {
+			ws.logger.Error(err)
+			continue
+		}
+
+		// the retry loop picks the delivery up if the first attempt never completes
+		retryAt := time.Now().Add(webhook.Backoff(1))
+		delivery := &domain.WebhookDelivery{
+			Subscription:  subscriptions[i].ID,
+			Company:       company,
+			EventType:     event,
+			Payload:       string(payload),
+			Status:        domain.DeliveryPending,
+			NextAttemptAt: &retryAt,
+		}
+
+		if err = ws.WebhookRepository.PersistDelivery(delivery); err != nil {
+			ws.logger.Error(err)
+			continue
+		}
+
+		go ws.deliver(delivery, &subscriptions[i])
+	}
+}
+
+// RedeliverWebhook sends a logged delivery again, whatever its status
+func (ws *webhookService) RedeliverWebhook(id string) (*domain.WebhookDelivery, error) {
+	delivery, err := ws.WebhookRepository.GetDeliveryByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription, err := ws.WebhookRepository.GetByID(delivery.Subscription.String())
+	if err != nil {
+		return nil, err
+	}
+
+	ws.deliver(delivery, subscription)
+	return delivery, nil
+}
+
+// RetryDueDeliveries sends the pending deliveries whose backoff has elapsed
+func (ws *webhookService) RetryDueDeliveries() {
+	deliveries, err := ws.WebhookRepository.GetDueDeliveries(time.Now(), 100)
+	if err != nil {
+		ws.logger.Error(err)
+		return
+	}
+
+	for i := range deliveries {
+		subscription, err := ws.WebhookRepository.GetByID(deliveries[i].Subscription.String())
+		if err != nil || !subscription.Active {
+			deliveries[i].Status = domain.DeliveryFailed
+			deliveries[i].LastError = "subscription removed or disabled"
+			deliveries[i].NextAttemptAt = nil
+			_ = ws.WebhookRepository.PersistDelivery(&deliveries[i])
+			continue
+		}
+
+		ws.deliver(&deliveries[i], subscription)
+	}
+}
+
+func (ws *webhookService) deliver(delivery *domain.WebhookDelivery, subscription *domain.WebhookSubscription) {
+	status, err := ws.Sender.Send(subscription.URL, subscription.Secret, delivery.ID.String(), string(delivery.EventType), []byte(delivery.Payload))
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.ResponseCode = status
+
+	if err == nil {
+		delivery.Status = domain.DeliveryDelivered
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+	} else if delivery.Attempts >= domain.MaxDeliveryAttempts {
+		delivery.Status = domain.DeliveryFailed
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = nil
+	} else {
+		next := now.Add(webhook.Backoff(delivery.Attempts))
+		delivery.Status = domain.DeliveryPending
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = &next
+	}
+
+	if err = ws.WebhookRepository.PersistDelivery(delivery); err != nil {
+		ws.logger.Error(err)
+	}
+}
+
+// publish sends an event when a publisher is configured
+func publish(publisher ports.IEventPublisher, company uuid.UUID, event domain.EventType, data interface{}) {
+	if publisher == nil {
+		return
+	}
+	publisher.Publish(company, event, data)
+}
+
+func subscribes(subscription *domain.WebhookSubscription, event domain.EventType) bool {
+	for _, eventType := range subscription.EventTypes {
+		if eventType == event {
+			return true
+		}
+	}
+	return false
+}
+
+func toEventTypes(values []string) ([]domain.EventType, error) {
+	var eventTypes []domain.EventType
+	for _, value := range values {
+		valid := false
+		for _, eventType := range domain.EventTypes {
+			if string(eventType) == value {
+				valid = true
+				break
+			}
+		}
+
+		if !valid {
+			return nil, fmt.Errorf("unknown event type %v", value)
+		}
+		eventTypes = append(eventTypes, domain.EventType(value))
+	}
+	return eventTypes, nil
+}
+
+func generateSecret() (string, error) {
+	key := make([]byte, 24)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(key), nil
+}
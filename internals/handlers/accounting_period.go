@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"core_business/internals/common"
+	"core_business/internals/common/types"
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"core_business/pkg/utils"
+	"errors"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+type accountingPeriodHandler struct {
+	AccountingPeriodService ports.IAccountingPeriodService
+	logger                  *log.Logger
+	handlerName             string
+}
+
+// NewAccountingPeriodHandler function creates a new instance for accounting period handler
+func NewAccountingPeriodHandler(as ports.IAccountingPeriodService, l *log.Logger, n string) ports.IAccountingPeriodHandler {
+	return &accountingPeriodHandler{
+		AccountingPeriodService: as,
+		logger:                  l,
+		handlerName:             n,
+	}
+}
+
+// GetPeriodByID godoc
+// @Summary      Get an accounting period
+// @Description  get accounting period by ID with its close and reopen audit trail
+// @Tags         period
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Accounting period ID"
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /period/{id} [get]
+func (ah *accountingPeriodHandler) GetPeriodByID(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		ah.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	period, err := ah.AccountingPeriodService.GetPeriodByID(params.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ah.logger.Error(err)
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		ah.logger.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(period, message.GetResponseMessage(ah.handlerName, types.OKAY)))
+}
+
+// GetPeriodByCompanyID godoc
+// @Summary      Get accounting periods by company id
+// @Description  gets the closed and reopened accounting periods of a company
+// @Tags         period
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Company ID"
+// @Param        limit   query  int  false  "Page size"
+// @Param        page   query  int  false  "Page no"
+// @Param        sort   query  string  false  "Sort by"
+// @Success      200  {object}  common.GetAllResponse
+// @Failure      500  {object}  common.Error
+// @Router       /period/company/{id} [get]
+func (ah *accountingPeriodHandler) GetPeriodByCompanyID(c *gin.Context) {
+	var (
+		params common.GetByIDRequest
+		query  utils.Pagination
+	)
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		ah.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		ah.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	periods, err := ah.AccountingPeriodService.GetPeriodByCompanyID(params.ID, &query)
+	if err != nil {
+		ah.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(periods, message.GetResponseMessage(ah.handlerName, types.OKAY)))
+}
+
+// ClosePeriod godoc
+// @Summary      Close an accounting period
+// @Description  closes a month of a company, its transactions can no longer be updated or deleted
+// @Tags         period
+// @Accept       json
+// @Produce      json
+// @Param period body common.ClosePeriodRequest true "Close period"
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Router       /period/close [post]
+func (ah *accountingPeriodHandler) ClosePeriod(c *gin.Context) {
+	var body common.ClosePeriodRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		ah.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	period, err := ah.AccountingPeriodService.ClosePeriod(body)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ah.logger.Error(err)
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		ah.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusCreated, result.ReturnSuccessResult(period, message.GetResponseMessage(ah.handlerName, types.CREATED)))
+}
+
+// ReopenPeriod godoc
+// @Summary      Reopen an accounting period
+// @Description  reopens a closed month, only the company owner can reopen with their bearer token and the reason is kept in the audit trail
+// @Tags         period
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Accounting period ID"
+// @Param period body common.ReopenPeriodRequest true "Reopen period"
+// @Failure      400  {object}  common.Error
+// @Failure      401  {object}  common.Error
+// @Failure      403  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Router       /period/{id}/reopen [patch]
+func (ah *accountingPeriodHandler) ReopenPeriod(c *gin.Context) {
+	var (
+		body   common.ReopenPeriodRequest
+		params common.GetByIDRequest
+	)
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		ah.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		ah.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	period, err := ah.AccountingPeriodService.ReopenPeriod(params.ID, c.GetString(ActorKey), body)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ah.logger.Error(err)
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		if errors.Is(err, domain.ErrReopenNotAuthorized) {
+			ah.logger.Error(err)
+			c.JSON(http.StatusForbidden, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		ah.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(period, message.GetResponseMessage(ah.handlerName, types.UPDATED)))
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"core_business/internals/common"
+	"core_business/internals/common/types"
+	"core_business/internals/core/ports"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+)
+
+type analyticsHandler struct {
+	AnalyticsService ports.IAnalyticsService
+	logger           *log.Logger
+	handlerName      string
+}
+
+// NewAnalyticsHandler function creates a new instance for analytics handler
+func NewAnalyticsHandler(as ports.IAnalyticsService, l *log.Logger, n string) ports.IAnalyticsHandler {
+	return &analyticsHandler{
+		AnalyticsService: as,
+		logger:           l,
+		handlerName:      n,
+	}
+}
+
+// GetSpendByCompanyID godoc
+// @Summary      Get spend analytics by company id
+// @Description  top card spend groups of a company by category, card, cardholder, merchant, channel or month, compared with the previous period of the same length
+// @Tags         analytics
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Company ID"
+// @Param        group_by   query  string  false  "category, card, cardholder, merchant, channel or month"
+// @Param        from   query  string  false  "Start date, YYYY-MM-DD"
+// @Param        to   query  string  false  "End date exclusive, YYYY-MM-DD"
+// @Param        limit   query  int  false  "Top N groups"
+// @Failure      400  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /analytics/company/{id}/spend [get]
+func (ah *analyticsHandler) GetSpendByCompanyID(c *gin.Context) {
+	var (
+		params common.GetByIDRequest
+		query  common.SpendAnalyticsRequest
+	)
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		ah.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		ah.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	report, err := ah.AnalyticsService.GetSpendByCompanyID(params.ID, query)
+	if err != nil {
+		ah.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(report, message.GetResponseMessage(ah.handlerName, types.OKAY)))
+}
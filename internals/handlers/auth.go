@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"net/http"
+	"strings"
+)
+
+// ActorKey context key of the user the bearer token of the request was issued to
+const ActorKey = "actor"
+
+// Authenticate verifies the bearer token of the request with the secret and keeps its subject as the actor of the
+// request, requests without a valid token are rejected
+func Authenticate(secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if secret == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, result.ReturnErrorResult("authentication is not configured"))
+			return
+		}
+
+		header := c.GetHeader("Authorization")
+		if !strings.HasPrefix(header, "Bearer ") {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, result.ReturnErrorResult("missing bearer token"))
+			return
+		}
+
+		claims := &jwt.RegisteredClaims{}
+		_, err := jwt.ParseWithClaims(strings.TrimPrefix(header, "Bearer "), claims, func(token *jwt.Token) (interface{}, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+			}
+			return []byte(secret), nil
+		})
+		if err != nil || strings.TrimSpace(claims.Subject) == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, result.ReturnErrorResult("invalid bearer token"))
+			return
+		}
+
+		c.Set(ActorKey, claims.Subject)
+		c.Next()
+	}
+}
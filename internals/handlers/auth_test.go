@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func signedToken(t *testing.T, secret string, subject string) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   subject,
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	})
+
+	signed, err := token.SignedString([]byte(secret))
+	require.NoError(t, err)
+	return signed
+}
+
+func TestAuthenticate(t *testing.T) {
+	r := SetupRouter()
+	r.GET("/v1/me", Authenticate("secret"), func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString(ActorKey))
+	})
+
+	tests := []struct {
+		name   string
+		header string
+		code   int
+		actor  string
+	}{
+		{name: "valid token", header: "Bearer " + signedToken(t, "secret", "owner-001"), code: http.StatusOK, actor: "owner-001"},
+		{name: "missing token", header: "", code: http.StatusUnauthorized},
+		{name: "other secret", header: "Bearer " + signedToken(t, "other", "owner-001"), code: http.StatusUnauthorized},
+		{name: "no subject", header: "Bearer " + signedToken(t, "secret", ""), code: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := http.NewRequest("GET", "/v1/me", nil)
+			require.NoError(t, err)
+			if tt.header != "" {
+				request.Header.Set("Authorization", tt.header)
+			}
+
+			response := httptest.NewRecorder()
+			r.ServeHTTP(response, request)
+
+			require.Equal(t, tt.code, response.Code)
+			if tt.code == http.StatusOK {
+				require.Equal(t, tt.actor, response.Body.String())
+			}
+		})
+	}
+}
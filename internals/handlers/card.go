@@ -3,6 +3,7 @@ package handlers
 import (
 	"core_business/internals/common"
 	"core_business/internals/common/types"
+	"core_business/internals/core/domain"
 	"core_business/internals/core/ports"
 	"core_business/pkg/utils"
 	"errors"
@@ -105,6 +106,48 @@ func (ch *cardHandler) GetCardByCompanyID(c *gin.Context) {
 
 }
 
+// GetCardByCustomerID godoc
+// @Summary      Get the cards of a cardholder
+// @Description  gets all cards issued to a cardholder
+// @Tags         card
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Customer ID"
+// @Param        limit   query  int  false  "Page size"
+// @Param        page   query  int  false  "Page no"
+// @Param        sort   query  string  false  "Sort by"
+// @Param        filter   query  string  false  "Status"
+// @Success      200  {object}  common.GetAllResponse
+// @Failure      500  {object}  common.Error
+// @Router       /card/customer/{id} [get]
+func (ch *cardHandler) GetCardByCustomerID(c *gin.Context) {
+	var (
+		params common.GetByIDRequest
+		query  utils.Pagination
+	)
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		ch.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		ch.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	cards, err := ch.CardService.GetCardByCustomerID(params.ID, &query)
+
+	if err != nil {
+		ch.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(cards, message.GetResponseMessage(ch.handlerName, types.OKAY)))
+}
+
 // GetAllCard godoc
 // @Summary      Get all cards
 // @Description  gets all cards
@@ -155,7 +198,7 @@ func (ch *cardHandler) CreateCard(c *gin.Context) {
 		return
 	}
 
-	card, err := ch.CardService.CreateCard(body)
+	card, err := ch.CardService.CreateCard(c.Request.Context(), body)
 
 	if err != nil {
 		ch.logger.Error(err)
@@ -177,6 +220,7 @@ func (ch *cardHandler) CreateCard(c *gin.Context) {
 // @Success      200  {object}  common.GetSingleCardResponse
 // @Failure      400  {object}  common.Error
 // @Failure      404  {object}  common.Error
+// @Failure      409  {object}  common.Error
 // @Failure      500  {object}  common.Error
 // @Router       /card/{id} [patch]
 func (ch *cardHandler) UpdateCard(c *gin.Context) {
@@ -195,13 +239,18 @@ func (ch *cardHandler) UpdateCard(c *gin.Context) {
 		return
 	}
 
-	card, err := ch.CardService.UpdateCard(params.ID, body)
+	card, err := ch.CardService.UpdateCard(c.Request.Context(), params.ID, body)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			ch.logger.Error(err)
 			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
 			return
 		}
+		if errors.Is(err, domain.ErrInvalidCardTransition) || errors.Is(err, domain.ErrCardNotDelivered) {
+			ch.logger.Error(err)
+			c.JSON(http.StatusConflict, result.ReturnErrorResult(err.Error()))
+			return
+		}
 		ch.logger.Error(err)
 		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
 		return
@@ -238,7 +287,7 @@ func (ch *cardHandler) CancelCard(c *gin.Context) {
 		return
 	}
 
-	err := ch.CardService.CancelCard(params.ID, body)
+	err := ch.CardService.CancelCard(c.Request.Context(), params.ID, body)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			ch.logger.Error(err)
@@ -286,9 +335,228 @@ func (ch *cardHandler) LockCard(c *gin.Context) {
 		return
 	}
 
-	card, err := ch.CardService.LockCard(params.ID, body)
+	card, err := ch.CardService.LockCard(c.Request.Context(), params.ID, body)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidCardTransition) || errors.Is(err, domain.ErrCardNotDelivered) {
+			ch.logger.Error(err)
+			c.JSON(http.StatusConflict, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		ch.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(card, message.GetResponseMessage(ch.handlerName, types.UPDATED)))
+}
+
+// ChangeCardStatus godoc
+// @Summary      Change a card status by ID
+// @Description  Move a card through its lifecycle, the change is kept in the card status history
+// @Tags         card
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Card ID"
+// @Param card body common.ChangeCardStatusRequest true "Change card status"
+// @Success      200  {object}  common.GetSingleCardResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      409  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /card/{id}/status [patch]
+func (ch *cardHandler) ChangeCardStatus(c *gin.Context) {
+	var body common.ChangeCardStatusRequest
+	var params common.GetByIDRequest
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		ch.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		ch.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	card, err := ch.CardService.ChangeCardStatus(c.Request.Context(), params.ID, body)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ch.logger.Error(err)
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidCardTransition) || errors.Is(err, domain.ErrCardNotDelivered) {
+			ch.logger.Error(err)
+			c.JSON(http.StatusConflict, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		ch.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(card, message.GetResponseMessage(ch.handlerName, types.UPDATED)))
+}
+
+// GetCardStatusHistory godoc
+// @Summary      Get the status history of a card
+// @Description  get every status change of a card, oldest first
+// @Tags         card
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Card ID"
+// @Success      200  {array}   domain.CardStatusChange
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /card/{id}/status_history [get]
+func (ch *cardHandler) GetCardStatusHistory(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		ch.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	history, err := ch.CardService.GetCardStatusHistory(params.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ch.logger.Error(err)
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		ch.logger.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(history, message.GetResponseMessage(ch.handlerName, types.OKAY)))
+}
+
+// ReconcileCardLocks godoc
+// @Summary      Reconcile card locks with the issuer
+// @Description  compare the status of active and frozen cards with the issuer and repair the differences, cards the issuer froze while active here are left frozen and the company is alerted
+// @Tags         card
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  domain.LockReconciliation
+// @Failure      500  {object}  common.Error
+// @Router       /card/reconcile_locks [post]
+func (ch *cardHandler) ReconcileCardLocks(c *gin.Context) {
+	report, err := ch.CardService.ReconcileCardLocks(c.Request.Context())
+	if err != nil {
+		ch.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(report, message.GetResponseMessage(ch.handlerName, types.OKAY)))
+}
+
+// RenewExpiringCards godoc
+// @Summary      Run the card expiry job
+// @Description  notify companies of the cards expiring in the next days, replace them when the company renews cards automatically and expire the cards past their expiry
+// @Tags         card
+// @Accept       json
+// @Produce      json
+// @Param        days   query  int  false  "Days before expiry"
+// @Success      200  {object}  domain.CardRenewalRun
+// @Failure      400  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /card/renew_expiring [post]
+func (ch *cardHandler) RenewExpiringCards(c *gin.Context) {
+	var query common.RenewExpiringCardsRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		ch.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	run, err := ch.CardService.RenewExpiringCards(c.Request.Context(), query.Days)
+	if err != nil {
+		ch.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(run, message.GetResponseMessage(ch.handlerName, types.OKAY)))
+}
+
+// GetCardRecurringMerchants godoc
+// @Summary      Get the recurring merchants of a renewed card
+// @Description  get the merchants that billed the card this card replaced month after month
+// @Tags         card
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Card ID"
+// @Success      200  {array}   domain.CardRecurringMerchant
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /card/{id}/recurring_merchants [get]
+func (ch *cardHandler) GetCardRecurringMerchants(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		ch.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	merchants, err := ch.CardService.GetCardRecurringMerchants(params.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ch.logger.Error(err)
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		ch.logger.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(merchants, message.GetResponseMessage(ch.handlerName, types.OKAY)))
+}
+
+// UpdateCardSchedule godoc
+// @Summary      Set when a card can be used
+// @Description  days and times of day the card is authorized in, e.g. weekdays 08:00 to 20:00 Africa/Lagos, a schedule without windows removes it
+// @Tags         card
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Card ID"
+// @Param card body common.UpdateCardScheduleRequest true "Card schedule"
+// @Success      200  {object}  common.GetSingleCardResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /card/{id}/schedule [patch]
+func (ch *cardHandler) UpdateCardSchedule(c *gin.Context) {
+	var body common.UpdateCardScheduleRequest
+	var params common.GetByIDRequest
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		ch.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		ch.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	card, err := ch.CardService.UpdateCardSchedule(params.ID, body)
 	if err != nil {
 		ch.logger.Error(err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidSchedule) {
+			c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
 		return
 	}
@@ -307,7 +575,7 @@ func (ch *cardHandler) LockCard(c *gin.Context) {
 // @Failure      400  {object}  common.Error
 // @Failure      404  {object}  common.Error
 // @Failure      500  {object}  common.Error
-// @Router       /card/{id}/change-pin [patch]
+// @Router       /card/{id}/change_pin [patch]
 func (ch *cardHandler) ChangeCardPin(c *gin.Context) {
 	var body common.ChangeCardPinRequest
 	var params common.GetByIDRequest
@@ -324,7 +592,7 @@ func (ch *cardHandler) ChangeCardPin(c *gin.Context) {
 		return
 	}
 
-	err := ch.CardService.ChangeCardPin(params.ID, body)
+	err := ch.CardService.ChangeCardPin(c.Request.Context(), params.ID, body)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			ch.logger.Error(err)
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"core_business/internals/common"
+	"core_business/internals/common/types"
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"core_business/pkg/utils"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+type cardBatchHandler struct {
+	CardBatchService ports.ICardBatchService
+	logger           *log.Logger
+	handlerName      string
+}
+
+// NewCardBatchHandler function creates a new instance for card batch handler
+func NewCardBatchHandler(bs ports.ICardBatchService, l *log.Logger, n string) ports.ICardBatchHandler {
+	return &cardBatchHandler{
+		CardBatchService: bs,
+		logger:           l,
+		handlerName:      n,
+	}
+}
+
+// GetCardBatchByID godoc
+// @Summary      Get a card batch
+// @Description  get card batch by ID with the status of every row
+// @Tags         card_batch
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Card batch ID"
+// @Success      200  {object}  common.GetCardBatchDataResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /card_batch/{id} [get]
+func (bh *cardBatchHandler) GetCardBatchByID(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		bh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	batch, err := bh.CardBatchService.GetCardBatchByID(params.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			bh.logger.Error(err)
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		bh.logger.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(batch, message.GetResponseMessage(bh.handlerName, types.OKAY)))
+}
+
+// GetCardBatchByCompanyID godoc
+// @Summary      Get card batches by company id
+// @Description  gets all card batches by company id, filter by status
+// @Tags         card_batch
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Company ID"
+// @Param        limit   query  int  false  "Page size"
+// @Param        page   query  int  false  "Page no"
+// @Param        sort   query  string  false  "Sort by"
+// @Param        filter   query  string  false  "Status"
+// @Success      200  {object}  common.GetAllResponse
+// @Failure      500  {object}  common.Error
+// @Router       /card_batch/company/{id} [get]
+func (bh *cardBatchHandler) GetCardBatchByCompanyID(c *gin.Context) {
+	var (
+		params common.GetByIDRequest
+		query  utils.Pagination
+	)
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		bh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		bh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	batches, err := bh.CardBatchService.GetCardBatchByCompanyID(params.ID, &query)
+	if err != nil {
+		bh.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(batches, message.GetResponseMessage(bh.handlerName, types.OKAY)))
+}
+
+// CreateCardBatch godoc
+// @Summary      Issue cards from a CSV
+// @Description  validates every row of the CSV (name, email, phone, type, brand, template) and the wallet balance for the card fees, then issues the cards in the background
+// @Tags         card_batch
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        company  formData  string  true  "Company ID"
+// @Param        file     formData  file    true  "CSV of employees"
+// @Success      201  {object}  common.GetCardBatchDataResponse
+// @Failure      400  {object}  common.CardBatchValidationResponse
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /card_batch/ [post]
+func (bh *cardBatchHandler) CreateCardBatch(c *gin.Context) {
+	var body common.CreateCardBatchRequest
+	if err := c.ShouldBind(&body); err != nil {
+		bh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		bh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		bh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	defer file.Close()
+
+	batch, err := bh.CardBatchService.CreateCardBatch(body.Company, file)
+	if err != nil {
+		bh.logger.Error(err)
+
+		var invalid *domain.CardBatchValidationError
+		if errors.As(err, &invalid) {
+			res := result.ReturnErrorResult(err.Error())
+			res.Data = invalid.Rows
+			c.JSON(http.StatusBadRequest, res)
+			return
+		}
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, result.ReturnSuccessResult(batch, message.GetResponseMessage(bh.handlerName, types.CREATED)))
+}
+
+// DownloadCardBatchResult godoc
+// @Summary      Download the result of a card batch
+// @Description  the rows of the batch with the issued card or the reason it failed, as a CSV
+// @Tags         card_batch
+// @Produce      text/csv
+// @Param        id   path      string  true  "Card batch ID"
+// @Success      200  {file}    file
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /card_batch/{id}/result [get]
+func (bh *cardBatchHandler) DownloadCardBatchResult(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		bh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	file, err := bh.CardBatchService.GetCardBatchResult(params.ID)
+	if err != nil {
+		bh.logger.Error(err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=card_batch_%v.csv", params.ID))
+	c.Data(http.StatusOK, "text/csv", file)
+}
@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"core_business/internals/common"
+	"core_business/internals/common/types"
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"core_business/pkg/utils"
+	"errors"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+type cardFulfillmentHandler struct {
+	FulfillmentService ports.ICardFulfillmentService
+	logger             *log.Logger
+	handlerName        string
+}
+
+// NewCardFulfillmentHandler function creates a new instance for card fulfillment handler
+func NewCardFulfillmentHandler(fs ports.ICardFulfillmentService, l *log.Logger, n string) ports.ICardFulfillmentHandler {
+	return &cardFulfillmentHandler{
+		FulfillmentService: fs,
+		logger:             l,
+		handlerName:        n,
+	}
+}
+
+// GetFulfillmentByID godoc
+// @Summary      Get a card fulfillment
+// @Description  get the printing and delivery of a physical card by ID with its history
+// @Tags         fulfillment
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Fulfillment ID"
+// @Success      200  {object}  common.GetCardFulfillmentDataResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /fulfillment/{id} [get]
+func (fh *cardFulfillmentHandler) GetFulfillmentByID(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		fh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	fulfillment, err := fh.FulfillmentService.GetFulfillmentByID(params.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			fh.logger.Error(err)
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		fh.logger.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(fulfillment, message.GetResponseMessage(fh.handlerName, types.OKAY)))
+}
+
+// GetFulfillmentByCardID godoc
+// @Summary      Get the fulfillment of a card
+// @Description  where a physical card is, with the courier and tracking reference once dispatched
+// @Tags         fulfillment
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Card ID"
+// @Success      200  {object}  common.GetCardFulfillmentDataResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /fulfillment/card/{id} [get]
+func (fh *cardFulfillmentHandler) GetFulfillmentByCardID(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		fh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	fulfillment, err := fh.FulfillmentService.GetFulfillmentByCardID(params.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			fh.logger.Error(err)
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		fh.logger.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(fulfillment, message.GetResponseMessage(fh.handlerName, types.OKAY)))
+}
+
+// GetFulfillmentByCompanyID godoc
+// @Summary      Get card fulfillments by company id
+// @Description  gets all card fulfillments of a company, filter by status
+// @Tags         fulfillment
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Company ID"
+// @Param        limit   query  int  false  "Page size"
+// @Param        page   query  int  false  "Page no"
+// @Param        sort   query  string  false  "Sort by"
+// @Param        filter   query  string  false  "Status"
+// @Success      200  {object}  common.GetAllResponse
+// @Failure      500  {object}  common.Error
+// @Router       /fulfillment/company/{id} [get]
+func (fh *cardFulfillmentHandler) GetFulfillmentByCompanyID(c *gin.Context) {
+	var (
+		params common.GetByIDRequest
+		query  utils.Pagination
+	)
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		fh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		fh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	fulfillments, err := fh.FulfillmentService.GetFulfillmentByCompanyID(params.ID, &query)
+	if err != nil {
+		fh.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(fulfillments, message.GetResponseMessage(fh.handlerName, types.OKAY)))
+}
+
+// ChangeFulfillmentStatus godoc
+// @Summary      Change a card fulfillment status
+// @Description  move a physical card through printed, dispatched, delivered or returned, dispatching needs the courier and tracking reference
+// @Tags         fulfillment
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Fulfillment ID"
+// @Param fulfillment body common.ChangeFulfillmentStatusRequest true "Change fulfillment status"
+// @Success      200  {object}  common.GetCardFulfillmentDataResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      409  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /fulfillment/{id}/status [patch]
+func (fh *cardFulfillmentHandler) ChangeFulfillmentStatus(c *gin.Context) {
+	var (
+		body   common.ChangeFulfillmentStatusRequest
+		params common.GetByIDRequest
+	)
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		fh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		fh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	fulfillment, err := fh.FulfillmentService.ChangeFulfillmentStatus(params.ID, body)
+	if err != nil {
+		fh.logger.Error(err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		if errors.Is(err, domain.ErrTrackingReferenceRequired) {
+			c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidFulfillmentTransition) {
+			c.JSON(http.StatusConflict, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(fulfillment, message.GetResponseMessage(fh.handlerName, types.UPDATED)))
+}
+
+// UpdateShippingAddress godoc
+// @Summary      Update the shipping address of a card
+// @Description  change where a physical card is sent, only before it is dispatched or once the courier returned it
+// @Tags         fulfillment
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Fulfillment ID"
+// @Param fulfillment body common.ShippingAddress true "Shipping address"
+// @Success      200  {object}  common.GetCardFulfillmentDataResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      409  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /fulfillment/{id}/address [patch]
+func (fh *cardFulfillmentHandler) UpdateShippingAddress(c *gin.Context) {
+	var (
+		body   common.ShippingAddress
+		params common.GetByIDRequest
+	)
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		fh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		fh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	fulfillment, err := fh.FulfillmentService.UpdateShippingAddress(params.ID, body)
+	if err != nil {
+		fh.logger.Error(err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		if errors.Is(err, domain.ErrShippingAddressLocked) {
+			c.JSON(http.StatusConflict, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(fulfillment, message.GetResponseMessage(fh.handlerName, types.UPDATED)))
+}
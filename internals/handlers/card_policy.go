@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"core_business/internals/common"
+	"core_business/internals/common/types"
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"core_business/pkg/utils"
+	"errors"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+type cardPolicyHandler struct {
+	CardPolicyService ports.ICardPolicyService
+	logger            *log.Logger
+	handlerName       string
+}
+
+// NewCardPolicyHandler function creates a new instance for card policy handler
+func NewCardPolicyHandler(ps ports.ICardPolicyService, l *log.Logger, n string) ports.ICardPolicyHandler {
+	return &cardPolicyHandler{
+		CardPolicyService: ps,
+		logger:            l,
+		handlerName:       n,
+	}
+}
+
+// GetCardPolicyByID godoc
+// @Summary      Get a card policy
+// @Description  get card policy by ID
+// @Tags         card_policy
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Card policy ID"
+// @Success      200  {object}  common.GetCardPolicyDataResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /card_policy/{id} [get]
+func (ph *cardPolicyHandler) GetCardPolicyByID(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		ph.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	policy, err := ph.CardPolicyService.GetCardPolicyByID(params.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ph.logger.Error(err)
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		ph.logger.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(policy, message.GetResponseMessage(ph.handlerName, types.OKAY)))
+}
+
+// GetCardPolicyByCompanyID godoc
+// @Summary      Get card policies by company id
+// @Description  gets all card policies of a company
+// @Tags         card_policy
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Company ID"
+// @Param        limit   query  int  false  "Page size"
+// @Param        page   query  int  false  "Page no"
+// @Param        sort   query  string  false  "Sort by"
+// @Success      200  {object}  common.GetAllResponse
+// @Failure      500  {object}  common.Error
+// @Router       /card_policy/company/{id} [get]
+func (ph *cardPolicyHandler) GetCardPolicyByCompanyID(c *gin.Context) {
+	var (
+		params common.GetByIDRequest
+		query  utils.Pagination
+	)
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		ph.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		ph.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	policies, err := ph.CardPolicyService.GetCardPolicyByCompanyID(params.ID, &query)
+	if err != nil {
+		ph.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(policies, message.GetResponseMessage(ph.handlerName, types.OKAY)))
+}
+
+// CreateCardPolicy godoc
+// @Summary      Create card policy
+// @Description  adds named spending controls and validity period to a company, cards issued on the policy take them, start from the sales-travel, marketing-saas or fleet-fuel preset
+// @Tags         card_policy
+// @Accept       json
+// @Produce      json
+// @Param policy body common.CreateCardPolicyRequest true "Add card policy"
+// @Success      201  {object}  common.GetCardPolicyDataResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      409  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /card_policy [post]
+func (ph *cardPolicyHandler) CreateCardPolicy(c *gin.Context) {
+	var body common.CreateCardPolicyRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		ph.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	policy, err := ph.CardPolicyService.CreateCardPolicy(body)
+	if err != nil {
+		ph.logger.Error(err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		if errors.Is(err, domain.ErrCardPolicyExists) {
+			c.JSON(http.StatusConflict, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, result.ReturnSuccessResult(policy, message.GetResponseMessage(ph.handlerName, types.CREATED)))
+}
+
+// UpdateCardPolicy godoc
+// @Summary      Update a card policy by ID
+// @Description  update a card policy, with propagate the spending controls of every card on it are changed at the issuer too
+// @Tags         card_policy
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Card policy ID"
+// @Param policy body common.UpdateCardPolicyRequest true "Update card policy"
+// @Success      200  {object}  common.UpdateCardPolicyDataResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      409  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /card_policy/{id} [patch]
+func (ph *cardPolicyHandler) UpdateCardPolicy(c *gin.Context) {
+	var (
+		body   common.UpdateCardPolicyRequest
+		params common.GetByIDRequest
+	)
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		ph.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		ph.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	update, err := ph.CardPolicyService.UpdateCardPolicy(c.Request.Context(), params.ID, body)
+	if err != nil {
+		ph.logger.Error(err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		if errors.Is(err, domain.ErrCardPolicyExists) {
+			c.JSON(http.StatusConflict, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidSchedule) {
+			c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(update, message.GetResponseMessage(ph.handlerName, types.UPDATED)))
+}
+
+// DeleteCardPolicy godoc
+// @Summary      Delete a card policy by ID
+// @Description  deletes card policy by id, the cards issued on it keep their spending controls
+// @Tags         card_policy
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Card policy ID"
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /card_policy/{id} [delete]
+func (ph *cardPolicyHandler) DeleteCardPolicy(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		ph.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	err := ph.CardPolicyService.DeleteCardPolicy(params.ID)
+	if err != nil {
+		ph.logger.Error(err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusNoContent, result.ReturnSuccessMessage(types.DELETED))
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"core_business/internals/common"
+	"core_business/internals/common/types"
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"errors"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+type cardRevealHandler struct {
+	CardRevealService ports.ICardRevealService
+	logger            *log.Logger
+	handlerName       string
+}
+
+// NewCardRevealHandler function creates a new instance for card reveal handler
+func NewCardRevealHandler(rs ports.ICardRevealService, l *log.Logger, n string) ports.ICardRevealHandler {
+	return &cardRevealHandler{
+		CardRevealService: rs,
+		logger:            l,
+		handlerName:       n,
+	}
+}
+
+// CreateRevealToken godoc
+// @Summary      Get a token to reveal a card
+// @Description  issues a single use token, valid for a minute, for the bearer of the request to reveal the number, CVV and expiry of an active card
+// @Tags         card
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Card ID"
+// @Success      201  {object}  common.RevealTokenResponse
+// @Failure      400  {object}  common.Error
+// @Failure      401  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      409  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /card/{id}/reveal_token [post]
+func (rh *cardRevealHandler) CreateRevealToken(c *gin.Context) {
+	var params common.GetByIDRequest
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		rh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	token, err := rh.CardRevealService.CreateRevealToken(params.ID, c.GetString(ActorKey), c.ClientIP())
+	if err != nil {
+		rh.logger.Error(err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		if errors.Is(err, domain.ErrCardNotRevealable) {
+			c.JSON(http.StatusConflict, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, result.ReturnSuccessResult(token, message.GetResponseMessage(rh.handlerName, types.CREATED)))
+}
+
+// RevealCard godoc
+// @Summary      Reveal a card
+// @Description  uses a reveal token issued to the bearer of the request to read the number, CVV and expiry of the card from the issuer, every reveal is logged with the actor and IP
+// @Tags         card
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param card body common.RevealCardRequest true "Reveal card"
+// @Success      200  {object}  common.RevealCardResponse
+// @Failure      400  {object}  common.Error
+// @Failure      401  {object}  common.Error
+// @Failure      403  {object}  common.Error
+// @Failure      409  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /card/reveal [post]
+func (rh *cardRevealHandler) RevealCard(c *gin.Context) {
+	var body common.RevealCardRequest
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		rh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	secrets, err := rh.CardRevealService.RevealCard(c.Request.Context(), c.GetString(ActorKey), body, c.ClientIP())
+	if err != nil {
+		rh.logger.Error(err)
+		if errors.Is(err, domain.ErrInvalidRevealToken) {
+			c.JSON(http.StatusForbidden, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		if errors.Is(err, domain.ErrCardNotRevealable) {
+			c.JSON(http.StatusConflict, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(secrets, message.GetResponseMessage(rh.handlerName, types.OKAY)))
+}
+
+// GetCardReveals godoc
+// @Summary      Get the reveals of a card
+// @Description  get every reveal of a card with the actor and IP, latest first
+// @Tags         card
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Card ID"
+// @Success      200  {array}   domain.CardReveal
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /card/{id}/reveals [get]
+func (rh *cardRevealHandler) GetCardReveals(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		rh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	reveals, err := rh.CardRevealService.GetCardReveals(params.ID)
+	if err != nil {
+		rh.logger.Error(err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(reveals, message.GetResponseMessage(rh.handlerName, types.OKAY)))
+}
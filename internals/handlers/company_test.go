@@ -28,7 +28,8 @@ var (
 	companyRepository   = repositories.NewCompanyRepository(DBConnection)
 	walletRepository    = repositories.NewWalletRepository(DBConnection)
 	creditLimitIncrease = repositories.NewCreditLimitRequestRepository(DBConnection)
-	companyService      = services.NewCompanyService(companyRepository, companyProfileRepository, walletRepository, creditLimitIncrease, logging)
+	outboxRepository    = repositories.NewOutboxRepository(DBConnection)
+	companyService      = services.NewCompanyService(companyRepository, companyProfileRepository, walletRepository, creditLimitIncrease, nil, outboxRepository, DBConnection, logging)
 	handler             = NewCompanyHandler(companyService, logging, "Company")
 )
 
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"core_business/internals/common"
+	"core_business/internals/common/types"
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"core_business/pkg/utils"
+	"errors"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+type customerHandler struct {
+	CustomerService ports.ICustomerService
+	logger          *log.Logger
+	handlerName     string
+}
+
+// NewCustomerHandler function creates a new instance for customer handler
+func NewCustomerHandler(cs ports.ICustomerService, l *log.Logger, n string) ports.ICustomerHandler {
+	return &customerHandler{
+		CustomerService: cs,
+		logger:          l,
+		handlerName:     n,
+	}
+}
+
+// GetCustomerByID godoc
+// @Summary      Get a cardholder
+// @Description  get cardholder by ID
+// @Tags         customer
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Customer ID"
+// @Success      200  {object}  common.GetCustomerDataResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /customer/{id} [get]
+func (ch *customerHandler) GetCustomerByID(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		ch.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	customer, err := ch.CustomerService.GetCustomerByID(params.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ch.logger.Error(err)
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		ch.logger.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(customer, message.GetResponseMessage(ch.handlerName, types.OKAY)))
+}
+
+// GetCustomerByCompanyID godoc
+// @Summary      Get cardholders by company id
+// @Description  gets all cardholders of a company
+// @Tags         customer
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Company ID"
+// @Param        limit   query  int  false  "Page size"
+// @Param        page   query  int  false  "Page no"
+// @Param        sort   query  string  false  "Sort by"
+// @Success      200  {object}  common.GetAllResponse
+// @Failure      500  {object}  common.Error
+// @Router       /customer/company/{id} [get]
+func (ch *customerHandler) GetCustomerByCompanyID(c *gin.Context) {
+	var (
+		params common.GetByIDRequest
+		query  utils.Pagination
+	)
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		ch.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		ch.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	customers, err := ch.CustomerService.GetCustomerByCompanyID(params.ID, &query)
+	if err != nil {
+		ch.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(customers, message.GetResponseMessage(ch.handlerName, types.OKAY)))
+}
+
+// CreateCustomer godoc
+// @Summary      Create cardholder
+// @Description  adds an employee of a company as a cardholder, every card issued to them reuses it
+// @Tags         customer
+// @Accept       json
+// @Produce      json
+// @Param customer body common.CreateCustomerRequest true "Add cardholder"
+// @Success      201  {object}  common.GetCustomerDataResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      409  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /customer [post]
+func (ch *customerHandler) CreateCustomer(c *gin.Context) {
+	var body common.CreateCustomerRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		ch.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	customer, err := ch.CustomerService.CreateCustomer(body)
+	if err != nil {
+		ch.logger.Error(err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		if errors.Is(err, domain.ErrCustomerExists) {
+			c.JSON(http.StatusConflict, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, result.ReturnSuccessResult(customer, message.GetResponseMessage(ch.handlerName, types.CREATED)))
+}
+
+// UpdateCustomer godoc
+// @Summary      Update a cardholder by ID
+// @Description  update the details of a cardholder
+// @Tags         customer
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Customer ID"
+// @Param customer body common.UpdateCustomerRequest true "Update cardholder"
+// @Success      200  {object}  common.GetCustomerDataResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      409  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /customer/{id} [patch]
+func (ch *customerHandler) UpdateCustomer(c *gin.Context) {
+	var (
+		body   common.UpdateCustomerRequest
+		params common.GetByIDRequest
+	)
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		ch.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		ch.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	customer, err := ch.CustomerService.UpdateCustomer(params.ID, body)
+	if err != nil {
+		ch.logger.Error(err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		if errors.Is(err, domain.ErrCustomerExists) {
+			c.JSON(http.StatusConflict, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(customer, message.GetResponseMessage(ch.handlerName, types.UPDATED)))
+}
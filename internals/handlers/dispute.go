@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"core_business/internals/common"
+	"core_business/internals/common/types"
+	"core_business/internals/core/ports"
+	"core_business/pkg/utils"
+	"errors"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+type disputeHandler struct {
+	DisputeService ports.IDisputeService
+	logger         *log.Logger
+	handlerName    string
+}
+
+// NewDisputeHandler function creates a new instance for dispute handler
+func NewDisputeHandler(ds ports.IDisputeService, l *log.Logger, n string) ports.IDisputeHandler {
+	return &disputeHandler{
+		DisputeService: ds,
+		logger:         l,
+		handlerName:    n,
+	}
+}
+
+// GetDisputeByID godoc
+// @Summary      Get a dispute
+// @Description  get dispute by ID
+// @Tags         dispute
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Dispute ID"
+// @Success      200  {object}  common.GetDisputeDataResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /dispute/{id} [get]
+func (dh *disputeHandler) GetDisputeByID(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		dh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	dispute, err := dh.DisputeService.GetDisputeByID(params.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			dh.logger.Error(err)
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		dh.logger.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(dispute, message.GetResponseMessage(dh.handlerName, types.OKAY)))
+}
+
+// GetDisputeByCompanyID godoc
+// @Summary      Get disputes by company id
+// @Description  gets all disputes by company id, filter by status
+// @Tags         dispute
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Company ID"
+// @Param        limit   query  int  false  "Page size"
+// @Param        page   query  int  false  "Page no"
+// @Param        sort   query  string  false  "Sort by"
+// @Param        filter   query  string  false  "Status"
+// @Success      200  {object}  common.GetAllResponse
+// @Failure      500  {object}  common.Error
+// @Router       /dispute/company/{id} [get]
+func (dh *disputeHandler) GetDisputeByCompanyID(c *gin.Context) {
+	var (
+		params common.GetByIDRequest
+		query  utils.Pagination
+	)
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		dh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		dh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	disputes, err := dh.DisputeService.GetDisputeByCompanyID(params.ID, &query)
+	if err != nil {
+		dh.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(disputes, message.GetResponseMessage(dh.handlerName, types.OKAY)))
+}
+
+// OpenDispute godoc
+// @Summary      Open a dispute
+// @Description  disputes a card transaction and provisionally credits the wallet
+// @Tags         dispute
+// @Accept       json
+// @Produce      json
+// @Param dispute body common.OpenDisputeRequest true "Open dispute"
+// @Success      201  {object}  common.GetDisputeDataResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Router       /dispute [post]
+func (dh *disputeHandler) OpenDispute(c *gin.Context) {
+	var body common.OpenDisputeRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		dh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	dispute, err := dh.DisputeService.OpenDispute(body)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			dh.logger.Error(err)
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		dh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, result.ReturnSuccessResult(dispute, message.GetResponseMessage(dh.handlerName, types.CREATED)))
+}
+
+// AddDisputeEvidence godoc
+// @Summary      Attach evidence to a dispute
+// @Description  adds an evidence attachment to an open dispute
+// @Tags         dispute
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Dispute ID"
+// @Param evidence body common.DisputeEvidenceRequest true "Add evidence"
+// @Success      201  {object}  common.GetDisputeDataResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Router       /dispute/{id}/evidence [post]
+func (dh *disputeHandler) AddDisputeEvidence(c *gin.Context) {
+	var (
+		body   common.DisputeEvidenceRequest
+		params common.GetByIDRequest
+	)
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		dh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		dh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	dispute, err := dh.DisputeService.AddDisputeEvidence(params.ID, body)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			dh.logger.Error(err)
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		dh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, result.ReturnSuccessResult(dispute, message.GetResponseMessage(dh.handlerName, types.CREATED)))
+}
+
+// ChangeDisputeStatus godoc
+// @Summary      Change dispute status
+// @Description  moves a dispute to submitted, won, lost or withdrawn, lost and withdrawn reverse the provisional credit
+// @Tags         dispute
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Dispute ID"
+// @Param status body common.ChangeDisputeStatusRequest true "Change status"
+// @Success      200  {object}  common.GetDisputeDataResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Router       /dispute/{id}/status [patch]
+func (dh *disputeHandler) ChangeDisputeStatus(c *gin.Context) {
+	var (
+		body   common.ChangeDisputeStatusRequest
+		params common.GetByIDRequest
+	)
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		dh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		dh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	dispute, err := dh.DisputeService.ChangeDisputeStatus(params.ID, body)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			dh.logger.Error(err)
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		dh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(dispute, message.GetResponseMessage(dh.handlerName, types.UPDATED)))
+}
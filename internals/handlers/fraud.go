@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"core_business/internals/common"
+	"core_business/internals/common/types"
+	"core_business/internals/core/ports"
+	"core_business/pkg/utils"
+	"errors"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+type fraudHandler struct {
+	FraudService ports.IFraudService
+	logger       *log.Logger
+	handlerName  string
+}
+
+// NewFraudHandler function creates a new instance for fraud handler
+func NewFraudHandler(fs ports.IFraudService, l *log.Logger, n string) ports.IFraudHandler {
+	return &fraudHandler{
+		FraudService: fs,
+		logger:       l,
+		handlerName:  n,
+	}
+}
+
+// GetFraudRulesByCompanyID godoc
+// @Summary      Get fraud rules by company id
+// @Description  gets the fraud rules a company runs on card authorizations, the default rules run while it has none
+// @Tags         fraud
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Company ID"
+// @Failure      400  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /fraud/rule/company/{id} [get]
+func (fh *fraudHandler) GetFraudRulesByCompanyID(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		fh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	rules, err := fh.FraudService.GetFraudRulesByCompanyID(params.ID)
+	if err != nil {
+		fh.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(rules, message.GetResponseMessage(fh.handlerName, types.OKAY)))
+}
+
+// CreateFraudRule godoc
+// @Summary      Create fraud rule
+// @Description  adds a fraud rule to a company, rules raise alerts and can decline the authorization or lock the card
+// @Tags         fraud
+// @Accept       json
+// @Produce      json
+// @Param rule body common.CreateFraudRuleRequest true "Add fraud rule"
+// @Failure      400  {object}  common.Error
+// @Router       /fraud/rule [post]
+func (fh *fraudHandler) CreateFraudRule(c *gin.Context) {
+	var body common.CreateFraudRuleRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		fh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	rule, err := fh.FraudService.CreateFraudRule(body)
+	if err != nil {
+		fh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusCreated, result.ReturnSuccessResult(rule, message.GetResponseMessage(fh.handlerName, types.CREATED)))
+}
+
+// UpdateFraudRule godoc
+// @Summary      Update a fraud rule by ID
+// @Description  updates the settings of a fraud rule or turns it off
+// @Tags         fraud
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Fraud rule ID"
+// @Param rule body common.UpdateFraudRuleRequest true "Update fraud rule"
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Router       /fraud/rule/{id} [patch]
+func (fh *fraudHandler) UpdateFraudRule(c *gin.Context) {
+	var (
+		body   common.UpdateFraudRuleRequest
+		params common.GetByIDRequest
+	)
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		fh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		fh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	rule, err := fh.FraudService.UpdateFraudRule(params.ID, body)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			fh.logger.Error(err)
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		fh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(rule, message.GetResponseMessage(fh.handlerName, types.UPDATED)))
+}
+
+// DeleteFraudRule godoc
+// @Summary      Delete a fraud rule by ID
+// @Description  deletes fraud rule by id
+// @Tags         fraud
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Fraud rule ID"
+// @Failure      400  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /fraud/rule/{id} [delete]
+func (fh *fraudHandler) DeleteFraudRule(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		fh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := fh.FraudService.DeleteFraudRule(params.ID); err != nil {
+		fh.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusNoContent, result.ReturnSuccessMessage(types.DELETED))
+}
+
+// GetFraudAlertsByCompanyID godoc
+// @Summary      Get fraud alerts by company id
+// @Description  the fraud alerts queue of a company, filter by status OPEN, CONFIRMED or DISMISSED
+// @Tags         fraud
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Company ID"
+// @Param        limit   query  int  false  "Page size"
+// @Param        page   query  int  false  "Page no"
+// @Param        sort   query  string  false  "Sort by"
+// @Param        filter   query  string  false  "Alert status"
+// @Success      200  {object}  common.GetAllResponse
+// @Failure      500  {object}  common.Error
+// @Router       /fraud/alert/company/{id} [get]
+func (fh *fraudHandler) GetFraudAlertsByCompanyID(c *gin.Context) {
+	var (
+		params common.GetByIDRequest
+		query  utils.Pagination
+	)
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		fh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		fh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	alerts, err := fh.FraudService.GetFraudAlertsByCompanyID(params.ID, &query)
+	if err != nil {
+		fh.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(alerts, message.GetResponseMessage(fh.handlerName, types.OKAY)))
+}
+
+// ReviewFraudAlert godoc
+// @Summary      Review a fraud alert by ID
+// @Description  confirms or dismisses an open fraud alert, dismissing can unlock the card the alert locked
+// @Tags         fraud
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Fraud alert ID"
+// @Param review body common.ReviewFraudAlertRequest true "Review fraud alert"
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Router       /fraud/alert/{id}/review [patch]
+func (fh *fraudHandler) ReviewFraudAlert(c *gin.Context) {
+	var (
+		body   common.ReviewFraudAlertRequest
+		params common.GetByIDRequest
+	)
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		fh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		fh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	alert, err := fh.FraudService.ReviewFraudAlert(c.Request.Context(), params.ID, body)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			fh.logger.Error(err)
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		fh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(alert, message.GetResponseMessage(fh.handlerName, types.UPDATED)))
+}
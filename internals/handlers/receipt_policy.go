@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"core_business/internals/common"
+	"core_business/internals/common/types"
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"errors"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+type receiptPolicyHandler struct {
+	ReceiptPolicyService ports.IReceiptPolicyService
+	logger               *log.Logger
+	handlerName          string
+}
+
+// NewReceiptPolicyHandler function creates a new instance for receipt policy handler
+func NewReceiptPolicyHandler(rs ports.IReceiptPolicyService, l *log.Logger, n string) ports.IReceiptPolicyHandler {
+	return &receiptPolicyHandler{
+		ReceiptPolicyService: rs,
+		logger:               l,
+		handlerName:          n,
+	}
+}
+
+// GetReceiptPolicyByID godoc
+// @Summary      Get a receipt policy
+// @Description  get receipt policy by ID
+// @Tags         receipt_policy
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Receipt policy ID"
+// @Success      200  {object}  common.GetReceiptPolicyDataResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /receipt_policy/{id} [get]
+func (rh *receiptPolicyHandler) GetReceiptPolicyByID(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		rh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	receiptPolicy, err := rh.ReceiptPolicyService.GetReceiptPolicyByID(params.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			rh.logger.Error(err)
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		rh.logger.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(receiptPolicy, message.GetResponseMessage(rh.handlerName, types.OKAY)))
+}
+
+// GetReceiptPolicyByCompanyID godoc
+// @Summary      Get a company receipt policy
+// @Description  get receipt policy by company ID
+// @Tags         receipt_policy
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Company ID"
+// @Success      200  {object}  common.GetReceiptPolicyDataResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /receipt_policy/company/{id} [get]
+func (rh *receiptPolicyHandler) GetReceiptPolicyByCompanyID(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		rh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	receiptPolicy, err := rh.ReceiptPolicyService.GetReceiptPolicyByCompanyID(params.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			rh.logger.Error(err)
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		rh.logger.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(receiptPolicy, message.GetResponseMessage(rh.handlerName, types.OKAY)))
+}
+
+// CreateReceiptPolicy godoc
+// @Summary      Create receipt policy
+// @Description  creates the receipt policy of a company
+// @Tags         receipt_policy
+// @Accept       json
+// @Produce      json
+// @Param receipt_policy body common.CreateReceiptPolicyRequest true "Add receipt policy"
+// @Success      201  {object}  common.GetReceiptPolicyDataResponse
+// @Failure      400  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /receipt_policy [post]
+func (rh *receiptPolicyHandler) CreateReceiptPolicy(c *gin.Context) {
+	var body common.CreateReceiptPolicyRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		rh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	receiptPolicy := &domain.ReceiptPolicy{
+		Company:            body.Company,
+		AmountThreshold:    body.AmountThreshold,
+		ExemptCategories:   body.ExemptCategories,
+		GraceDays:          body.GraceDays,
+		AutoLockCard:       body.AutoLockCard,
+		MaxOverdueReceipts: body.MaxOverdueReceipts,
+	}
+
+	err := rh.ReceiptPolicyService.CreateReceiptPolicy(receiptPolicy)
+
+	if err != nil {
+		rh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, result.ReturnSuccessResult(receiptPolicy, message.GetResponseMessage(rh.handlerName, types.CREATED)))
+}
+
+// UpdateReceiptPolicy godoc
+// @Summary      Update a receipt policy by ID
+// @Description  update receipt policy by id
+// @Tags         receipt_policy
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Receipt policy ID"
+// @Param receipt_policy body common.UpdateReceiptPolicyRequest true "Update receipt policy"
+// @Success      200  {object}  common.GetReceiptPolicyDataResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /receipt_policy/{id} [patch]
+func (rh *receiptPolicyHandler) UpdateReceiptPolicy(c *gin.Context) {
+	var (
+		body   common.UpdateReceiptPolicyRequest
+		params common.GetByIDRequest
+	)
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		rh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		rh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	receiptPolicy, err := rh.ReceiptPolicyService.UpdateReceiptPolicy(params.ID, body)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			rh.logger.Error(err)
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		rh.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(receiptPolicy, message.GetResponseMessage(rh.handlerName, types.UPDATED)))
+}
+
+// DeleteReceiptPolicy godoc
+// @Summary      Delete a receipt policy by ID
+// @Description  deletes receipt policy by id
+// @Tags         receipt_policy
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Receipt policy ID"
+// @Failure      400  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /receipt_policy/{id} [delete]
+func (rh *receiptPolicyHandler) DeleteReceiptPolicy(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		rh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	err := rh.ReceiptPolicyService.DeleteReceiptPolicy(params.ID)
+	if err != nil {
+		rh.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusNoContent, result.ReturnSuccessMessage(types.DELETED))
+}
@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"core_business/internals/common"
+	"core_business/internals/common/types"
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"core_business/pkg/utils"
+	"errors"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+type tagHandler struct {
+	TagService  ports.ITagService
+	logger      *log.Logger
+	handlerName string
+}
+
+// NewTagHandler function creates a new instance for tag handler
+func NewTagHandler(ts ports.ITagService, l *log.Logger, n string) ports.ITagHandler {
+	return &tagHandler{
+		TagService:  ts,
+		logger:      l,
+		handlerName: n,
+	}
+}
+
+// GetTagByID godoc
+// @Summary      Get a tag
+// @Description  get tag by ID
+// @Tags         tag
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Tag ID"
+// @Success      200  {object}  common.GetTagDataResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /tag/{id} [get]
+func (th *tagHandler) GetTagByID(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		th.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	tag, err := th.TagService.GetTagByID(params.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			th.logger.Error(err)
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		th.logger.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(tag, message.GetResponseMessage(th.handlerName, types.OKAY)))
+}
+
+// GetTagByCompanyID godoc
+// @Summary      Get tags by company id
+// @Description  gets all tags defined by a company
+// @Tags         tag
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Company ID"
+// @Param        limit   query  int  false  "Page size"
+// @Param        page   query  int  false  "Page no"
+// @Param        sort   query  string  false  "Sort by"
+// @Success      200  {object}  common.GetAllResponse
+// @Failure      500  {object}  common.Error
+// @Router       /tag/company/{id} [get]
+func (th *tagHandler) GetTagByCompanyID(c *gin.Context) {
+	var (
+		params common.GetByIDRequest
+		query  utils.Pagination
+	)
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		th.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		th.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	tags, err := th.TagService.GetTagByCompanyID(params.ID, &query)
+	if err != nil {
+		th.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(tags, message.GetResponseMessage(th.handlerName, types.OKAY)))
+}
+
+// CreateTag godoc
+// @Summary      Create tag
+// @Description  creates a company tag for transactions
+// @Tags         tag
+// @Accept       json
+// @Produce      json
+// @Param tag body common.CreateTagRequest true "Add tag"
+// @Success      201  {object}  common.GetTagDataResponse
+// @Failure      400  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /tag [post]
+func (th *tagHandler) CreateTag(c *gin.Context) {
+	var body common.CreateTagRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		th.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	tag := &domain.Tag{
+		Company: body.Company,
+		Name:    body.Name,
+		Color:   body.Color,
+	}
+
+	err := th.TagService.CreateTag(tag)
+	if err != nil {
+		th.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, result.ReturnSuccessResult(tag, message.GetResponseMessage(th.handlerName, types.CREATED)))
+}
+
+// UpdateTag godoc
+// @Summary      Update a tag by ID
+// @Description  update tag by id
+// @Tags         tag
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Tag ID"
+// @Param tag body common.UpdateTagRequest true "Update tag"
+// @Success      200  {object}  common.GetTagDataResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /tag/{id} [patch]
+func (th *tagHandler) UpdateTag(c *gin.Context) {
+	var (
+		body   common.UpdateTagRequest
+		params common.GetByIDRequest
+	)
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		th.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		th.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	tag, err := th.TagService.UpdateTag(params.ID, body)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			th.logger.Error(err)
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		th.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(tag, message.GetResponseMessage(th.handlerName, types.UPDATED)))
+}
+
+// DeleteTag godoc
+// @Summary      Delete a tag by ID
+// @Description  deletes tag by id and removes it from transactions
+// @Tags         tag
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Tag ID"
+// @Failure      400  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /tag/{id} [delete]
+func (th *tagHandler) DeleteTag(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		th.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	err := th.TagService.DeleteTag(params.ID)
+	if err != nil {
+		th.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusNoContent, result.ReturnSuccessMessage(types.DELETED))
+}
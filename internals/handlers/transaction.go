@@ -3,9 +3,11 @@ package handlers
 import (
 	"core_business/internals/common"
 	"core_business/internals/common/types"
+	"core_business/internals/core/domain"
 	"core_business/internals/core/ports"
 	"core_business/pkg/utils"
 	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
 	"gorm.io/gorm"
@@ -73,6 +75,7 @@ func (th *transactionHandler) GetTransactionByID(c *gin.Context) {
 // @Param        limit   query  int  false  "Page size"
 // @Param        page   query  int  false  "Page no"
 // @Param        sort   query  string  false  "Sort by"
+// @Param        filter   query  string  false  "Tag ID"
 // @Success      200  {object}  common.GetAllResponse
 // @Failure      500  {object}  common.Error
 // @Router       /transaction/company/{id} [get]
@@ -116,6 +119,7 @@ func (th *transactionHandler) GetTransactionByCompanyID(c *gin.Context) {
 // @Param        limit   query  int  false  "Page size"
 // @Param        page   query  int  false  "Page no"
 // @Param        sort   query  string  false  "Sort by"
+// @Param        filter   query  string  false  "Tag ID"
 // @Success      200  {object}  common.GetAllResponse
 // @Failure      500  {object}  common.Error
 // @Router       /transaction/card/{id} [get]
@@ -147,6 +151,48 @@ func (th *transactionHandler) GetTransactionByCardID(c *gin.Context) {
 	c.JSON(http.StatusOK, result.ReturnSuccessResult(transactions, message.GetResponseMessage(th.handlerName, types.OKAY)))
 }
 
+// GetTransactionByCustomerID godoc
+// @Summary      Get transactions by cardholder id
+// @Description  gets all transactions of every card of a cardholder
+// @Tags         transaction
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Customer ID"
+// @Param        limit   query  int  false  "Page size"
+// @Param        page   query  int  false  "Page no"
+// @Param        sort   query  string  false  "Sort by"
+// @Param        filter   query  string  false  "Tag ID"
+// @Success      200  {object}  common.GetAllResponse
+// @Failure      500  {object}  common.Error
+// @Router       /transaction/customer/{id} [get]
+func (th *transactionHandler) GetTransactionByCustomerID(c *gin.Context) {
+	var (
+		params common.GetByIDRequest
+		query  utils.Pagination
+	)
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		th.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		th.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	transactions, err := th.TransactionService.GetTransactionByCustomerID(params.ID, &query)
+
+	if err != nil {
+		th.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(transactions, message.GetResponseMessage(th.handlerName, types.OKAY)))
+}
+
 // GetAllTransaction godoc
 // @Summary      Get transactions
 // @Description  gets all transactions
@@ -156,6 +202,7 @@ func (th *transactionHandler) GetTransactionByCardID(c *gin.Context) {
 // @Param        limit   query  int  false  "Page size"
 // @Param        page   query  int  false  "Page no"
 // @Param        sort   query  string  false  "Sort by"
+// @Param        filter   query  string  false  "Tag ID"
 // @Success      200  {object}  common.GetAllResponse
 // @Failure      500  {object}  common.Error
 // @Router       /transaction [get]
@@ -178,6 +225,54 @@ func (th *transactionHandler) GetAllTransaction(c *gin.Context) {
 	c.JSON(http.StatusOK, result.ReturnSuccessResult(transactions, message.GetResponseMessage(th.handlerName, types.OKAY)))
 }
 
+// GetMissingReceiptsByCustomerID godoc
+// @Summary      Get transactions missing a receipt by customer id
+// @Description  gets the cardholder's transactions that still need a receipt under the company receipt policy
+// @Tags         transaction
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Customer ID"
+// @Param        limit   query  int  false  "Page size"
+// @Param        page   query  int  false  "Page no"
+// @Param        sort   query  string  false  "Sort by"
+// @Success      200  {object}  common.GetAllResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /transaction/customer/{id}/missing_receipts [get]
+func (th *transactionHandler) GetMissingReceiptsByCustomerID(c *gin.Context) {
+	var (
+		params common.GetByIDRequest
+		query  utils.Pagination
+	)
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		th.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		th.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	transactions, err := th.TransactionService.GetMissingReceiptsByCustomerID(params.ID, &query)
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			th.logger.Error(err)
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		th.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(transactions, message.GetResponseMessage(th.handlerName, types.OKAY)))
+}
+
 // CreateTransaction godoc
 // @Summary      Get transactions
 // @Description  gets all transactions
@@ -196,13 +291,15 @@ func (th *transactionHandler) CreateTransaction(c *gin.Context) {
 		return
 	}
 
-	if strings.ToLower(body.Type) != "authorization.request" {
+	webhookType := strings.ToLower(body.Type)
+	if webhookType != "authorization.request" && webhookType != "transaction.created" &&
+		webhookType != "transaction.refund" && webhookType != "transaction.reversal" {
 		th.logger.Error("invalid webhook")
 		c.JSON(http.StatusBadRequest, result.ReturnErrorResult("invalid webhook"))
 		return
 	}
 
-	err := th.TransactionService.CreateTransaction(&body)
+	err := th.TransactionService.CreateTransaction(c.Request.Context(), &body)
 
 	if err != nil {
 		th.logger.Error(err)
@@ -252,6 +349,11 @@ func (th *transactionHandler) UpdateTransaction(c *gin.Context) {
 			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
 			return
 		}
+		if errors.Is(err, domain.ErrPeriodClosed) || errors.Is(err, domain.ErrTransactionLocked) {
+			th.logger.Error(err)
+			c.JSON(http.StatusConflict, result.ReturnErrorResult(err.Error()))
+			return
+		}
 		th.logger.Error(err)
 		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
 		return
@@ -259,19 +361,24 @@ func (th *transactionHandler) UpdateTransaction(c *gin.Context) {
 	c.JSON(http.StatusOK, result.ReturnSuccessResult(transaction, message.GetResponseMessage(th.handlerName, types.UPDATED)))
 }
 
-// DeleteTransaction godoc
-// @Summary      Delete a transaction by ID
-// @Description  Delete transaction by id
+// VoidTransaction godoc
+// @Summary      Void a transaction by ID
+// @Description  offsets the transaction with a REVERSAL entry and keeps the original marked voided, transactions are never deleted
 // @Tags         transaction
 // @Accept       json
 // @Produce      json
 // @Param        id   path      string  true  "Transaction ID"
+// @Param void body common.VoidTransactionRequest true "Void transaction"
+// @Success      201  {object}  common.GetSingleTransactionResponse
 // @Failure      400  {object}  common.Error
 // @Failure      404  {object}  common.Error
-// @Failure      500  {object}  common.Error
-// @Router       /transaction/{id} [delete]
-func (th *transactionHandler) DeleteTransaction(c *gin.Context) {
-	var params common.GetByIDRequest
+// @Failure      409  {object}  common.Error
+// @Router       /transaction/{id}/void [post]
+func (th *transactionHandler) VoidTransaction(c *gin.Context) {
+	var (
+		body   common.VoidTransactionRequest
+		params common.GetByIDRequest
+	)
 
 	if err := c.ShouldBindUri(&params); err != nil {
 		th.logger.Error(err)
@@ -279,18 +386,29 @@ func (th *transactionHandler) DeleteTransaction(c *gin.Context) {
 		return
 	}
 
-	err := th.TransactionService.DeleteTransaction(params.ID)
+	if err := c.ShouldBindJSON(&body); err != nil {
+		th.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	reversal, err := th.TransactionService.VoidTransaction(params.ID, body)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			th.logger.Error(err)
 			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
 			return
 		}
+		if errors.Is(err, domain.ErrPeriodClosed) || errors.Is(err, domain.ErrTransactionLocked) {
+			th.logger.Error(err)
+			c.JSON(http.StatusConflict, result.ReturnErrorResult(err.Error()))
+			return
+		}
 		th.logger.Error(err)
-		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
 		return
 	}
-	c.JSON(http.StatusOK, result.ReturnSuccessMessage(message.GetResponseMessage(th.handlerName, types.DELETED)))
+	c.JSON(http.StatusCreated, result.ReturnSuccessResult(reversal, message.GetResponseMessage(th.handlerName, types.CREATED)))
 }
 
 // LockTransaction godoc
@@ -302,6 +420,7 @@ func (th *transactionHandler) DeleteTransaction(c *gin.Context) {
 // @Param        id   path      string  true  "Transaction ID"
 // @Failure      400  {object}  common.Error
 // @Failure      404  {object}  common.Error
+// @Failure      409  {object}  common.Error
 // @Failure      500  {object}  common.Error
 // @Router       /transaction/{id}/lock [patch]
 func (th *transactionHandler) LockTransaction(c *gin.Context) {
@@ -321,9 +440,310 @@ func (th *transactionHandler) LockTransaction(c *gin.Context) {
 			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
 			return
 		}
+		if errors.Is(err, domain.ErrPeriodClosed) {
+			th.logger.Error(err)
+			c.JSON(http.StatusConflict, result.ReturnErrorResult(err.Error()))
+			return
+		}
 		th.logger.Error(err)
 		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
 		return
 	}
 	c.JSON(http.StatusOK, result.ReturnSuccessResult(transaction, message.GetResponseMessage(th.handlerName, types.OKAY)))
 }
+
+// AdjustTransaction godoc
+// @Summary      Post an adjusting entry against a transaction
+// @Description  corrects a transaction of a closed period with an ADJUSTMENT entry posted in the current period, no wallet money moves
+// @Tags         transaction
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Transaction ID"
+// @Param adjustment body common.AdjustTransactionRequest true "Adjusting entry"
+// @Success      201  {object}  common.GetSingleTransactionResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      409  {object}  common.Error
+// @Router       /transaction/{id}/adjustments [post]
+func (th *transactionHandler) AdjustTransaction(c *gin.Context) {
+	var (
+		body   common.AdjustTransactionRequest
+		params common.GetByIDRequest
+	)
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		th.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		th.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	adjustment, err := th.TransactionService.AdjustTransaction(params.ID, body)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			th.logger.Error(err)
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		if errors.Is(err, domain.ErrPeriodClosed) {
+			th.logger.Error(err)
+			c.JSON(http.StatusConflict, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		th.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusCreated, result.ReturnSuccessResult(adjustment, message.GetResponseMessage(th.handlerName, types.CREATED)))
+}
+
+// SplitTransaction godoc
+// @Summary      Split a transaction by ID
+// @Description  splits a transaction across expense categories, the lines must sum to the transaction amount
+// @Tags         transaction
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Transaction ID"
+// @Param splits body common.SplitTransactionRequest true "Split transaction"
+// @Success      200  {object}  common.GetSingleTransactionResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Router       /transaction/{id}/splits [patch]
+func (th *transactionHandler) SplitTransaction(c *gin.Context) {
+	var (
+		body   common.SplitTransactionRequest
+		params common.GetByIDRequest
+	)
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		th.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		th.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	transaction, err := th.TransactionService.SplitTransaction(params.ID, body)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			th.logger.Error(err)
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		if errors.Is(err, domain.ErrPeriodClosed) || errors.Is(err, domain.ErrTransactionLocked) {
+			th.logger.Error(err)
+			c.JSON(http.StatusConflict, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		th.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(transaction, message.GetResponseMessage(th.handlerName, types.UPDATED)))
+}
+
+// GetCategoryTotalsByCompanyID godoc
+// @Summary      Get spend per expense category
+// @Description  sums successful card spend per expense category by company id, split transactions count their lines
+// @Tags         transaction
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Company ID"
+// @Failure      400  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /transaction/company/{id}/categories [get]
+func (th *transactionHandler) GetCategoryTotalsByCompanyID(c *gin.Context) {
+	var params common.GetByIDRequest
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		th.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	totals, err := th.TransactionService.GetCategoryTotalsByCompanyID(params.ID)
+	if err != nil {
+		th.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(totals, message.GetResponseMessage(th.handlerName, types.OKAY)))
+}
+
+// ExportTransactionsByCompanyID godoc
+// @Summary      Export transactions for accounting
+// @Description  the successful transactions of the period by company id as a CSV with merchant currency, VAT and partner fees, split transactions are exported one line per split
+// @Tags         transaction
+// @Produce      text/csv
+// @Param        id    path      string  true   "Company ID"
+// @Param        from  query     string  false  "Start date, 2006-01-02"
+// @Param        to    query     string  false  "End date, exclusive, 2006-01-02"
+// @Success      200   {file}    file
+// @Failure      400   {object}  common.Error
+// @Router       /transaction/company/{id}/export [get]
+func (th *transactionHandler) ExportTransactionsByCompanyID(c *gin.Context) {
+	var (
+		params common.GetByIDRequest
+		query  common.ExportTransactionsRequest
+	)
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		th.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		th.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	file, err := th.TransactionService.ExportTransactionsByCompanyID(params.ID, query)
+	if err != nil {
+		th.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=transactions-%v.csv", params.ID))
+	c.Data(http.StatusOK, "text/csv", file)
+}
+
+// TagTransaction godoc
+// @Summary      Tag a transaction by ID
+// @Description  sets the company tags of a transaction, an empty list removes all tags
+// @Tags         transaction
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Transaction ID"
+// @Param tags body common.TagTransactionRequest true "Tag transaction"
+// @Success      200  {object}  common.GetSingleTransactionResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Router       /transaction/{id}/tags [patch]
+func (th *transactionHandler) TagTransaction(c *gin.Context) {
+	var (
+		body   common.TagTransactionRequest
+		params common.GetByIDRequest
+	)
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		th.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		th.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	transaction, err := th.TransactionService.TagTransaction(params.ID, body)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			th.logger.Error(err)
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		th.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(transaction, message.GetResponseMessage(th.handlerName, types.UPDATED)))
+}
+
+// GetCommentsByTransactionID godoc
+// @Summary      Get the comment thread of a transaction
+// @Description  gets all comments on a transaction
+// @Tags         transaction
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Transaction ID"
+// @Param        limit   query  int  false  "Page size"
+// @Param        page   query  int  false  "Page no"
+// @Param        sort   query  string  false  "Sort by"
+// @Success      200  {object}  common.GetAllResponse
+// @Failure      500  {object}  common.Error
+// @Router       /transaction/{id}/comments [get]
+func (th *transactionHandler) GetCommentsByTransactionID(c *gin.Context) {
+	var (
+		params common.GetByIDRequest
+		query  utils.Pagination
+	)
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		th.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		th.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	comments, err := th.TransactionService.GetCommentsByTransactionID(params.ID, &query)
+	if err != nil {
+		th.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(comments, message.GetResponseMessage(th.handlerName, types.OKAY)))
+}
+
+// CreateTransactionComment godoc
+// @Summary      Comment on a transaction
+// @Description  adds a comment of the bearer of the request to the thread of a transaction
+// @Tags         transaction
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Transaction ID"
+// @Param comment body common.CreateTransactionCommentRequest true "Add comment"
+// @Failure      400  {object}  common.Error
+// @Failure      401  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Router       /transaction/{id}/comments [post]
+func (th *transactionHandler) CreateTransactionComment(c *gin.Context) {
+	var (
+		body   common.CreateTransactionCommentRequest
+		params common.GetByIDRequest
+	)
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		th.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		th.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	comment, err := th.TransactionService.CreateTransactionComment(params.ID, c.GetString(ActorKey), body)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			th.logger.Error(err)
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		th.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusCreated, result.ReturnSuccessResult(comment, message.GetResponseMessage(th.handlerName, types.CREATED)))
+}
@@ -109,25 +109,36 @@ func (wh *walletHandler) CreateWallet(c *gin.Context) {
 	c.JSON(http.StatusCreated, result.ReturnSuccessResult(wallet, message.GetResponseMessage(wh.handlerName, types.CREATED)))
 }
 
-// DeleteWallet godoc
-// @Summary      Delete a wallet by ID
-// @Description  deletes wallet by id
+// CloseWallet godoc
+// @Summary      Close a wallet by ID
+// @Description  closes a settled wallet, closed wallets are kept for retention and refuse new debits
 // @Tags         wallet
 // @Accept       json
 // @Produce      json
 // @Param        id   path      string  true  "Wallet ID"
+// @Param wallet body common.CloseWalletRequest true "Close wallet"
 // @Failure      400  {object}  common.Error
 // @Failure      404  {object}  common.Error
-// @Failure      500  {object}  common.Error
-// @Router       /wallet/{id} [delete]
-func (wh *walletHandler) DeleteWallet(c *gin.Context) {
-	var query common.GetByIDRequest
+// @Router       /wallet/{id}/close [patch]
+func (wh *walletHandler) CloseWallet(c *gin.Context) {
+	var (
+		body  common.CloseWalletRequest
+		query common.GetByIDRequest
+	)
+
 	if err := c.ShouldBindUri(&query); err != nil {
 		wh.logger.Error(err)
 		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
 		return
 	}
-	err := wh.WalletService.DeleteWallet(query.ID)
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		wh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	wallet, err := wh.WalletService.CloseWallet(query.ID, body)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			wh.logger.Error(err)
@@ -138,7 +149,7 @@ func (wh *walletHandler) DeleteWallet(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
 		return
 	}
-	c.JSON(http.StatusNoContent, result.ReturnSuccessMessage(types.DELETED))
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(wallet, message.GetResponseMessage(wh.handlerName, types.UPDATED)))
 }
 
 // UpdateWallet godoc
@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"core_business/internals/common"
+	"core_business/internals/common/types"
+	"core_business/internals/core/ports"
+	"core_business/pkg/utils"
+	"errors"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+type webhookHandler struct {
+	WebhookService ports.IWebhookService
+	logger         *log.Logger
+	handlerName    string
+}
+
+// NewWebhookHandler function creates a new instance for webhook handler
+func NewWebhookHandler(ws ports.IWebhookService, l *log.Logger, n string) ports.IWebhookHandler {
+	return &webhookHandler{
+		WebhookService: ws,
+		logger:         l,
+		handlerName:    n,
+	}
+}
+
+// GetWebhookByID godoc
+// @Summary      Get a webhook subscription
+// @Description  get webhook subscription by ID
+// @Tags         webhook
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Webhook ID"
+// @Success      200  {object}  common.GetWebhookDataResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /webhook/{id} [get]
+func (wh *webhookHandler) GetWebhookByID(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		wh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	subscription, err := wh.WebhookService.GetWebhookByID(params.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			wh.logger.Error(err)
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		wh.logger.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(subscription, message.GetResponseMessage(wh.handlerName, types.OKAY)))
+}
+
+// GetWebhookByCompanyID godoc
+// @Summary      Get webhook subscriptions by company id
+// @Description  gets all webhook subscriptions of a company
+// @Tags         webhook
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Company ID"
+// @Param        limit   query  int  false  "Page size"
+// @Param        page   query  int  false  "Page no"
+// @Param        sort   query  string  false  "Sort by"
+// @Success      200  {object}  common.GetAllResponse
+// @Failure      500  {object}  common.Error
+// @Router       /webhook/company/{id} [get]
+func (wh *webhookHandler) GetWebhookByCompanyID(c *gin.Context) {
+	var (
+		params common.GetByIDRequest
+		query  utils.Pagination
+	)
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		wh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		wh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	subscriptions, err := wh.WebhookService.GetWebhookByCompanyID(params.ID, &query)
+	if err != nil {
+		wh.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(subscriptions, message.GetResponseMessage(wh.handlerName, types.OKAY)))
+}
+
+// CreateWebhook godoc
+// @Summary      Create webhook subscription
+// @Description  subscribes a company url to events, deliveries are signed with the subscription secret which is only returned here
+// @Tags         webhook
+// @Accept       json
+// @Produce      json
+// @Param webhook body common.CreateWebhookRequest true "Add webhook"
+// @Success      201  {object}  common.CreateWebhookDataResponse
+// @Failure      400  {object}  common.Error
+// @Router       /webhook [post]
+func (wh *webhookHandler) CreateWebhook(c *gin.Context) {
+	var body common.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		wh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	subscription, err := wh.WebhookService.CreateWebhook(body)
+	if err != nil {
+		wh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	response := common.CreateWebhookResponse{WebhookSubscription: subscription, Secret: subscription.Secret}
+	c.JSON(http.StatusCreated, result.ReturnSuccessResult(response, message.GetResponseMessage(wh.handlerName, types.CREATED)))
+}
+
+// UpdateWebhook godoc
+// @Summary      Update a webhook subscription by ID
+// @Description  update webhook subscription by id
+// @Tags         webhook
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Webhook ID"
+// @Param webhook body common.UpdateWebhookRequest true "Update webhook"
+// @Success      200  {object}  common.GetWebhookDataResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Router       /webhook/{id} [patch]
+func (wh *webhookHandler) UpdateWebhook(c *gin.Context) {
+	var (
+		body   common.UpdateWebhookRequest
+		params common.GetByIDRequest
+	)
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		wh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		wh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	subscription, err := wh.WebhookService.UpdateWebhook(params.ID, body)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			wh.logger.Error(err)
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		wh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(subscription, message.GetResponseMessage(wh.handlerName, types.UPDATED)))
+}
+
+// DeleteWebhook godoc
+// @Summary      Delete a webhook subscription by ID
+// @Description  deletes webhook subscription by id
+// @Tags         webhook
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Webhook ID"
+// @Failure      400  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Router       /webhook/{id} [delete]
+func (wh *webhookHandler) DeleteWebhook(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		wh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	err := wh.WebhookService.DeleteWebhook(params.ID)
+	if err != nil {
+		wh.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusNoContent, result.ReturnSuccessMessage(types.DELETED))
+}
+
+// GetDeliveriesByWebhookID godoc
+// @Summary      Get the delivery log of a webhook subscription
+// @Description  gets all deliveries of a webhook subscription, filter by status
+// @Tags         webhook
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Webhook ID"
+// @Param        limit   query  int  false  "Page size"
+// @Param        page   query  int  false  "Page no"
+// @Param        sort   query  string  false  "Sort by"
+// @Param        filter   query  string  false  "Status"
+// @Success      200  {object}  common.GetAllResponse
+// @Failure      500  {object}  common.Error
+// @Router       /webhook/{id}/deliveries [get]
+func (wh *webhookHandler) GetDeliveriesByWebhookID(c *gin.Context) {
+	var (
+		params common.GetByIDRequest
+		query  utils.Pagination
+	)
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		wh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		wh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	deliveries, err := wh.WebhookService.GetDeliveriesByWebhookID(params.ID, &query)
+	if err != nil {
+		wh.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(deliveries, message.GetResponseMessage(wh.handlerName, types.OKAY)))
+}
+
+// RedeliverWebhook godoc
+// @Summary      Redeliver a webhook delivery
+// @Description  sends a logged delivery again
+// @Tags         webhook
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Delivery ID"
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Router       /webhook/delivery/{id}/redeliver [post]
+func (wh *webhookHandler) RedeliverWebhook(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		wh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	delivery, err := wh.WebhookService.RedeliverWebhook(params.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			wh.logger.Error(err)
+			c.JSON(http.StatusNotFound, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		wh.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(delivery, message.GetResponseMessage(wh.handlerName, types.OKAY)))
+}
@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"core_business/pkg/utils"
+	"gorm.io/gorm"
+	"time"
+)
+
+type accountingPeriodRepository struct {
+	db *gorm.DB
+}
+
+// NewAccountingPeriodRepository creates a new instance accounting period repository
+func NewAccountingPeriodRepository(db *gorm.DB) ports.IAccountingPeriodRepository {
+	return &accountingPeriodRepository{
+		db: db,
+	}
+}
+
+func (a *accountingPeriodRepository) GetByID(id string) (*domain.AccountingPeriod, error) {
+	var period domain.AccountingPeriod
+	if err := a.db.Where("id = ?", id).
+		Preload("Audit", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at")
+		}).
+		First(&period).Error; err != nil {
+		return nil, err
+	}
+	return &period, nil
+}
+
+func (a *accountingPeriodRepository) GetPeriodByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error) {
+	var periods []domain.AccountingPeriod
+	query := a.db.Where("company = ?", id)
+
+	if err := query.Scopes(utils.Paginate(periods, pagination, query.Session(&gorm.Session{}))).
+		Find(&periods).Error; err != nil {
+		return nil, err
+	}
+
+	pagination.Rows = periods
+	return pagination, nil
+}
+
+func (a *accountingPeriodRepository) GetByMonth(company string, year int, month int) (*domain.AccountingPeriod, error) {
+	var period domain.AccountingPeriod
+	if err := a.db.Where("company = ? AND year = ? AND month = ?", company, year, month).
+		First(&period).Error; err != nil {
+		return nil, err
+	}
+	return &period, nil
+}
+
+// IsClosed reports whether the month containing at is closed for the company
+func (a *accountingPeriodRepository) IsClosed(company string, at time.Time) (bool, error) {
+	var count int64
+	at = at.UTC()
+	if err := a.db.Model(&domain.AccountingPeriod{}).
+		Where("company = ? AND year = ? AND month = ? AND status = ?", company, at.Year(), int(at.Month()), domain.PeriodClosed).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (a *accountingPeriodRepository) Persist(period *domain.AccountingPeriod) error {
+	if err := a.db.Save(period).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *accountingPeriodRepository) PersistAudit(audit *domain.PeriodAudit) error {
+	if err := a.db.Create(audit).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *accountingPeriodRepository) DeleteAll() error {
+	if err := a.db.Exec("DELETE FROM period_audits").Error; err != nil {
+		return err
+	}
+	if err := a.db.Exec("DELETE FROM accounting_periods").Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *accountingPeriodRepository) WithTx(tx *gorm.DB) ports.IAccountingPeriodRepository {
+	return NewAccountingPeriodRepository(tx)
+}
@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"core_business/internals/core/domain"
+	"core_business/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestAccountingPeriodIsClosed(t *testing.T) {
+	periodRepository := NewAccountingPeriodRepository(DBConnection)
+	company := (&utils.Faker{}).RandomUUID()
+
+	period := &domain.AccountingPeriod{Company: company, Year: 2024, Month: 2, Status: domain.PeriodClosed, ClosedBy: "finance"}
+	err := periodRepository.Persist(period)
+	require.NoError(t, err)
+
+	err = periodRepository.PersistAudit(&domain.PeriodAudit{Period: period.ID, Company: company, Action: domain.ClosePeriodAction, Actor: "finance"})
+	require.NoError(t, err)
+
+	closed, err := periodRepository.IsClosed(company.String(), time.Date(2024, 2, 29, 23, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.True(t, closed)
+
+	closed, err = periodRepository.IsClosed(company.String(), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.False(t, closed)
+
+	period.Status = domain.PeriodOpen
+	err = periodRepository.Persist(period)
+	require.NoError(t, err)
+
+	closed, err = periodRepository.IsClosed(company.String(), time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.False(t, closed)
+
+	period, err = periodRepository.GetByID(period.ID.String())
+	require.NoError(t, err)
+	require.Len(t, period.Audit, 1)
+}
@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"errors"
+	"gorm.io/gorm"
+)
+
+type analyticsRepository struct {
+	db *gorm.DB
+}
+
+// NewAnalyticsRepository creates a new instance analytics repository
+func NewAnalyticsRepository(db *gorm.DB) ports.IAnalyticsRepository {
+	return &analyticsRepository{
+		db: db,
+	}
+}
+
+// spend successful card purchases of a company in the period and the previous period, less voids and refunds
+func (a *analyticsRepository) spend(company string, period domain.SpendPeriod) *gorm.DB {
+	return a.db.Model(&domain.Transaction{}).
+		Where("company = ? AND type = ? AND entry = ? AND status = ?", company, domain.WithdrawalType, domain.DebitEntry, domain.SuccessStatus).
+		Where("created_at >= ? AND created_at < ?", period.PreviousFrom, period.To).
+		Scopes(netSpend)
+}
+
+// lines the spend of a company with the group column and the amount less refunds, ready for compare
+func (a *analyticsRepository) lines(company string, period domain.SpendPeriod, group string) *gorm.DB {
+	return a.spend(company, period).
+		Select(group+" AS group_key, debit - "+refundedSQL+" AS amount, created_at", domain.RefundType, domain.SuccessStatus)
+}
+
+// compare sums the lines of both periods in one pass, lines must have the created_at and amount columns
+func (a *analyticsRepository) compare(lines *gorm.DB, period domain.SpendPeriod, columns string) *gorm.DB {
+	return a.db.Table("(?) AS lines", lines).
+		Select(columns+
+			"SUM(CASE WHEN created_at >= ? THEN amount ELSE 0 END) AS amount, "+
+			"SUM(CASE WHEN created_at >= ? THEN 1 ELSE 0 END) AS count, "+
+			"SUM(CASE WHEN created_at < ? THEN amount ELSE 0 END) AS previous_amount, "+
+			"SUM(CASE WHEN created_at < ? THEN 1 ELSE 0 END) AS previous_count",
+			period.From, period.From, period.From, period.From)
+}
+
+func (a *analyticsRepository) GetSpendTotal(company string, period domain.SpendPeriod) (*domain.SpendTotal, error) {
+	var total domain.SpendTotal
+	if err := a.compare(a.lines(company, period, "''"), period, "").Scan(&total).Error; err != nil {
+		return nil, err
+	}
+	return &total, nil
+}
+
+// GetSpendBy sums card spend per group and returns the top groups of the period, months come back in order
+func (a *analyticsRepository) GetSpendBy(company string, dimension domain.SpendDimension, period domain.SpendPeriod, limit int) ([]domain.SpendTotal, error) {
+	var totals []domain.SpendTotal
+	var lines *gorm.DB
+
+	switch dimension {
+	case domain.CategoryDimension:
+		// split transactions count their lines in their own categories, refunds are shared in proportion to the lines
+		splitLines := a.db.Table("transaction_splits").
+			Select("transaction_splits.category AS group_key, "+
+				"transaction_splits.amount * (spend.debit - spend.refunded) / spend.debit AS amount, spend.created_at AS created_at").
+			Joins("JOIN (?) spend ON spend.id = transaction_splits.transaction_id", a.spend(company, period).
+				Select("id, debit, "+refundedSQL+" AS refunded, created_at", domain.RefundType, domain.SuccessStatus))
+
+		unsplit := a.lines(company, period, "expense_category").
+			Where("NOT EXISTS (SELECT 1 FROM transaction_splits WHERE transaction_splits.transaction_id = transactions.id)")
+
+		lines = a.db.Raw("? UNION ALL ?", splitLines, unsplit)
+	case domain.CardDimension:
+		lines = a.lines(company, period, "card")
+	case domain.CardholderDimension:
+		lines = a.lines(company, period, "customer")
+	case domain.MerchantDimension:
+		lines = a.lines(company, period, "merchant_name")
+	case domain.ChannelDimension:
+		lines = a.lines(company, period, "channel")
+	case domain.MonthDimension:
+		month := "strftime('%Y-%m', created_at)"
+		if a.db.Dialector.Name() == "postgres" {
+			month = "to_char(created_at, 'YYYY-MM')"
+		}
+		lines = a.lines(company, period, month)
+	default:
+		return nil, errors.New("invalid spend dimension")
+	}
+
+	order := "amount DESC, group_key"
+	if dimension == domain.MonthDimension {
+		order = "group_key"
+	}
+
+	err := a.compare(lines, period, "group_key, ").
+		Group("group_key").
+		Having("SUM(CASE WHEN created_at >= ? THEN 1 ELSE 0 END) > 0", period.From).
+		Order(order).
+		Limit(limit).
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
+
+func (a *analyticsRepository) WithTx(tx *gorm.DB) ports.IAnalyticsRepository {
+	return NewAnalyticsRepository(tx)
+}
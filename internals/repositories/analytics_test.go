@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"core_business/internals/core/domain"
+	"core_business/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestGetSpendBy(t *testing.T) {
+	transactionRepository := NewTransactionRepository(DBConnection)
+	analyticsRepository := NewAnalyticsRepository(DBConnection)
+	company := (&utils.Faker{}).RandomUUID()
+
+	to := time.Now().Truncate(time.Hour)
+	period := domain.NewSpendPeriod(to.AddDate(0, 0, -30), to)
+
+	transactions := []domain.Transaction{
+		{Debit: 30000, MerchantName: "Transcorp Hilton", Channel: domain.PosChannel, Base: domain.Base{CreatedAt: to.AddDate(0, 0, -2)}},
+		{Debit: 5000, MerchantName: "Chicken Republic", Channel: domain.PosChannel, Base: domain.Base{CreatedAt: to.AddDate(0, 0, -3)}},
+		{Debit: 7000, MerchantName: "Chicken Republic", Channel: domain.WebChannel, Base: domain.Base{CreatedAt: to.AddDate(0, 0, -4)}},
+		{Debit: 4000, MerchantName: "Chicken Republic", Channel: domain.PosChannel, Base: domain.Base{CreatedAt: to.AddDate(0, 0, -40)}},
+		{Debit: 9000, MerchantName: "Bolt", Channel: domain.WebChannel, Base: domain.Base{CreatedAt: to.AddDate(0, 0, -45)}},
+		{Debit: 2000, MerchantName: "Bolt", Channel: domain.WebChannel, Base: domain.Base{CreatedAt: to.AddDate(0, 0, -1)}, Status: domain.FailedStatus},
+	}
+
+	for i := range transactions {
+		transactions[i].Company = company
+		transactions[i].PartnerCardID = (&utils.Faker{}).RandomObjectID()
+		transactions[i].Note = "debited for transaction"
+		transactions[i].Entry = domain.DebitEntry
+		transactions[i].Type = domain.WithdrawalType
+		if transactions[i].Status == "" {
+			transactions[i].Status = domain.SuccessStatus
+		}
+
+		err := transactionRepository.Persist(&transactions[i])
+		require.NoError(t, err)
+	}
+
+	total, err := analyticsRepository.GetSpendTotal(company.String(), period)
+	require.NoError(t, err)
+	require.Equal(t, float64(42000), total.Amount)
+	require.Equal(t, int64(3), total.Count)
+	require.Equal(t, float64(13000), total.PreviousAmount)
+	require.Equal(t, int64(2), total.PreviousCount)
+
+	merchants, err := analyticsRepository.GetSpendBy(company.String(), domain.MerchantDimension, period, 10)
+	require.NoError(t, err)
+	require.Len(t, merchants, 2)
+	require.Equal(t, "Transcorp Hilton", merchants[0].Key)
+	require.Equal(t, "Chicken Republic", merchants[1].Key)
+	require.Equal(t, float64(12000), merchants[1].Amount)
+	require.Equal(t, int64(2), merchants[1].Count)
+	require.Equal(t, float64(4000), merchants[1].PreviousAmount)
+
+	top, err := analyticsRepository.GetSpendBy(company.String(), domain.ChannelDimension, period, 1)
+	require.NoError(t, err)
+	require.Len(t, top, 1)
+	require.Equal(t, string(domain.PosChannel), top[0].Key)
+	require.Equal(t, float64(35000), top[0].Amount)
+
+	months, err := analyticsRepository.GetSpendBy(company.String(), domain.MonthDimension, period, 12)
+	require.NoError(t, err)
+	require.NotEmpty(t, months)
+	var spent float64
+	for _, month := range months {
+		require.Len(t, month.Key, len("2006-01"))
+		spent += month.Amount
+	}
+	require.Equal(t, float64(42000), spent)
+
+	// a voided purchase is left out and a refund comes off the spend of its merchant
+	voidedAt := time.Now()
+	voided := &domain.Transaction{Company: company, Debit: 8000, MerchantName: "Bolt", Channel: domain.WebChannel,
+		VoidedAt: &voidedAt, PartnerCardID: (&utils.Faker{}).RandomObjectID(), Note: "debited for transaction",
+		Entry: domain.DebitEntry, Type: domain.WithdrawalType, Status: domain.SuccessStatus, Base: domain.Base{CreatedAt: to.AddDate(0, 0, -1)}}
+	require.NoError(t, transactionRepository.Persist(voided))
+
+	refund := &domain.Transaction{Company: company, Debit: 2000, ParentID: transactions[1].ID.String(),
+		PartnerCardID: (&utils.Faker{}).RandomObjectID(), Note: "credited for refund", Entry: domain.CreditEntry,
+		Channel: domain.PosChannel, Type: domain.RefundType, Status: domain.SuccessStatus}
+	require.NoError(t, transactionRepository.Persist(refund))
+
+	total, err = analyticsRepository.GetSpendTotal(company.String(), period)
+	require.NoError(t, err)
+	require.Equal(t, float64(40000), total.Amount)
+	require.Equal(t, int64(3), total.Count)
+
+	merchants, err = analyticsRepository.GetSpendBy(company.String(), domain.MerchantDimension, period, 10)
+	require.NoError(t, err)
+	require.Len(t, merchants, 2)
+	require.Equal(t, float64(10000), merchants[1].Amount)
+}
@@ -6,6 +6,7 @@ import (
 	"core_business/pkg/utils"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+	"time"
 )
 
 type cardRepository struct {
@@ -29,6 +30,23 @@ func (c *cardRepository) GetByID(id string) (*domain.Card, error) {
 	return &card, nil
 }
 
+func (c *cardRepository) GetCardByCustomerID(id string, pagination *utils.Pagination) (*utils.Pagination, error) {
+	var cards []domain.Card
+	query := c.db.Where("customer = ?", id)
+
+	if filter := pagination.GetFilter(); filter != "" {
+		query = query.Where("status = ?", filter)
+	}
+
+	if err := query.Scopes(utils.Paginate(cards, pagination, query.Session(&gorm.Session{}))).
+		Find(&cards).Error; err != nil {
+		return nil, err
+	}
+
+	pagination.Rows = cards
+	return pagination, nil
+}
+
 func (c *cardRepository) GetCardByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error) {
 	var cards []domain.Card
 	var filter string
@@ -84,6 +102,129 @@ func (c *cardRepository) Persist(card *domain.Card) error {
 	return nil
 }
 
+// PersistStatusChange saves the card with its new status and the history entry together
+func (c *cardRepository) PersistStatusChange(card *domain.Card, change *domain.CardStatusChange) error {
+	return c.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(card).Error; err != nil {
+			return err
+		}
+
+		change.Card = card.ID
+		return tx.Create(change).Error
+	})
+}
+
+// GetByStatus returns the cards in any of the statuses
+func (c *cardRepository) GetByStatus(statuses []string) ([]domain.Card, error) {
+	var cards []domain.Card
+	if err := c.db.Where("status IN ?", statuses).Order("created_at").Find(&cards).Error; err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
+
+// GetByExpiryYear returns the cards in the statuses expiring in year or before it, years are compared as numbers
+// and the two digit years of older cards are read in this century
+func (c *cardRepository) GetByExpiryYear(statuses []string, year int) ([]domain.Card, error) {
+	var cards []domain.Card
+	if err := c.db.Where("status IN ?", statuses).
+		Where("(CASE WHEN LENGTH(expiry_year) = 2 THEN 2000 + CAST(expiry_year AS INTEGER) "+
+			"WHEN LENGTH(expiry_year) = 4 THEN CAST(expiry_year AS INTEGER) END) <= ?", year).
+		Order("created_at").
+		Find(&cards).Error; err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
+
+// GetByPolicy returns the cards in the statuses issued on the card policy
+func (c *cardRepository) GetByPolicy(id string, statuses []string) ([]domain.Card, error) {
+	var cards []domain.Card
+	if err := c.db.Where("policy = ? AND status IN ?", id, statuses).
+		Order("created_at").
+		Find(&cards).Error; err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
+
+// GetPastValidity returns the cards in the statuses whose policy validity period ended before at
+func (c *cardRepository) GetPastValidity(statuses []string, at time.Time) ([]domain.Card, error) {
+	var cards []domain.Card
+	if err := c.db.Where("status IN ? AND valid_until IS NOT NULL AND valid_until <= ?", statuses, at).
+		Order("created_at").
+		Find(&cards).Error; err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
+
+func (c *cardRepository) PersistRecurringMerchants(merchants []domain.CardRecurringMerchant) error {
+	if len(merchants) == 0 {
+		return nil
+	}
+
+	if err := c.db.Create(&merchants).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *cardRepository) GetRecurringMerchants(id string) ([]domain.CardRecurringMerchant, error) {
+	var merchants []domain.CardRecurringMerchant
+	if err := c.db.Where("card = ?", id).Order("merchant_name").Find(&merchants).Error; err != nil {
+		return nil, err
+	}
+	return merchants, nil
+}
+
+// LockToMerchant locks a merchant locked card to the merchant of its first approval, the card stays locked to the
+// merchant it already has
+func (c *cardRepository) LockToMerchant(id string, merchantID string, merchant string) error {
+	locked := c.db.Model(&domain.Card{}).
+		Where("id = ? AND (locked_merchant_id = '' OR locked_merchant_id IS NULL OR locked_merchant_id = ?)", id, merchantID).
+		Updates(map[string]interface{}{"locked_merchant_id": merchantID, "locked_merchant": merchant})
+	if locked.Error != nil {
+		return locked.Error
+	}
+
+	if locked.RowsAffected == 0 {
+		return domain.ErrMerchantNotAllowed
+	}
+	return nil
+}
+
+// ClaimSingleUse claims a single use card for the authorization of its first approval, a concurrent authorization
+// of the card is declined
+func (c *cardRepository) ClaimSingleUse(id string, authorization string) error {
+	claimed := c.db.Model(&domain.Card{}).
+		Where("id = ? AND (used_by = '' OR used_by IS NULL OR used_by = ?)", id, authorization).
+		Update("used_by", authorization)
+	if claimed.Error != nil {
+		return claimed.Error
+	}
+
+	if claimed.RowsAffected == 0 {
+		return domain.ErrSingleUseCardUsed
+	}
+	return nil
+}
+
+// ReleaseSingleUse frees a single use card claimed by an authorization that failed, the card can be used again
+func (c *cardRepository) ReleaseSingleUse(id string, authorization string) error {
+	return c.db.Model(&domain.Card{}).
+		Where("id = ? AND used_by = ?", id, authorization).
+		Update("used_by", "").Error
+}
+
+func (c *cardRepository) GetStatusHistory(id string) ([]domain.CardStatusChange, error) {
+	var history []domain.CardStatusChange
+	if err := c.db.Where("card = ?", id).Order("created_at").Find(&history).Error; err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
 func (c *cardRepository) Delete(id string) error {
 	if err := c.db.Where("id = ?", id).Delete(&domain.Card{}).Error; err != nil {
 		return err
@@ -0,0 +1,140 @@
+package repositories
+
+import (
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"core_business/pkg/utils"
+	"gorm.io/gorm"
+	"time"
+)
+
+type cardBatchRepository struct {
+	db *gorm.DB
+}
+
+// NewCardBatchRepository creates a new instance card batch repository
+func NewCardBatchRepository(db *gorm.DB) ports.ICardBatchRepository {
+	return &cardBatchRepository{
+		db: db,
+	}
+}
+
+func (c *cardBatchRepository) GetByID(id string) (*domain.CardBatch, error) {
+	var batch domain.CardBatch
+	if err := c.db.Where("id = ?", id).
+		Preload("Rows", func(db *gorm.DB) *gorm.DB {
+			return db.Order("line")
+		}).
+		First(&batch).Error; err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+func (c *cardBatchRepository) GetCardBatchByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error) {
+	var batches []domain.CardBatch
+	query := c.db.Where("company = ?", id)
+
+	if filter := pagination.GetFilter(); filter != "" {
+		query = query.Where("status = ?", filter)
+	}
+
+	if err := query.Scopes(utils.Paginate(batches, pagination, query.Session(&gorm.Session{}))).
+		Find(&batches).Error; err != nil {
+		return nil, err
+	}
+
+	pagination.Rows = batches
+	return pagination, nil
+}
+
+// GetByStatus returns the batches in the status with their rows, oldest first
+func (c *cardBatchRepository) GetByStatus(status domain.CardBatchStatus) ([]domain.CardBatch, error) {
+	var batches []domain.CardBatch
+	if err := c.db.Where("status = ?", status).
+		Preload("Rows", func(db *gorm.DB) *gorm.DB {
+			return db.Order("line")
+		}).
+		Order("created_at").
+		Find(&batches).Error; err != nil {
+		return nil, err
+	}
+	return batches, nil
+}
+
+func (c *cardBatchRepository) Persist(batch *domain.CardBatch) error {
+	if batch.ID.String() != "" {
+		if err := c.db.Save(batch).Error; err != nil {
+			return err
+		}
+		return nil
+	}
+	if err := c.db.Create(&batch).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *cardBatchRepository) PersistRow(row *domain.CardBatchRow) error {
+	if err := c.db.Save(row).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// ClaimRow moves a pending row to issuing, only one worker gets the row when several process the same batch.
+// A row claimed before staleBefore is claimed again, the worker holding it is taken to have died
+func (c *cardBatchRepository) ClaimRow(id string, staleBefore time.Time) error {
+	claimed := c.db.Model(&domain.CardBatchRow{}).
+		Where("id = ? AND (status = ? OR (status = ? AND updated_at < ?))", id, domain.RowPending, domain.RowIssuing, staleBefore).
+		Update("status", domain.RowIssuing)
+	if claimed.Error != nil {
+		return claimed.Error
+	}
+
+	if claimed.RowsAffected == 0 {
+		return domain.ErrCardBatchRowClaimed
+	}
+	return nil
+}
+
+// UpdateProgress saves the status of the batch with its counters counted from the outcome of its rows, the batch
+// is completed once every row is issued or failed whichever worker tried it
+func (c *cardBatchRepository) UpdateProgress(batch *domain.CardBatch) error {
+	var issued, failed int64
+	if err := c.db.Model(&domain.CardBatchRow{}).
+		Where("batch = ? AND status = ?", batch.ID, domain.RowIssued).
+		Count(&issued).Error; err != nil {
+		return err
+	}
+
+	if err := c.db.Model(&domain.CardBatchRow{}).
+		Where("batch = ? AND status = ?", batch.ID, domain.RowFailed).
+		Count(&failed).Error; err != nil {
+		return err
+	}
+
+	if int(issued+failed) >= batch.Total {
+		batch.Status = domain.BatchCompleted
+	}
+
+	if err := c.db.Model(&domain.CardBatch{}).Where("id = ?", batch.ID).
+		Updates(map[string]interface{}{"status": batch.Status, "issued": issued, "failed": failed}).Error; err != nil {
+		return err
+	}
+
+	batch.Issued = int(issued)
+	batch.Failed = int(failed)
+	return nil
+}
+
+func (c *cardBatchRepository) DeleteAll() error {
+	if err := c.db.Exec("DELETE FROM card_batches").Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *cardBatchRepository) WithTx(tx *gorm.DB) ports.ICardBatchRepository {
+	return NewCardBatchRepository(tx)
+}
@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"core_business/internals/core/domain"
+	"core_business/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func createRandomCardBatch(t *testing.T) *domain.CardBatch {
+	cardBatchRepository := NewCardBatchRepository(DBConnection)
+
+	args := &domain.CardBatch{
+		Company:  (&utils.Faker{}).RandomUUID(),
+		Wallet:   (&utils.Faker{}).RandomUUID(),
+		Status:   domain.BatchPending,
+		Total:    2,
+		TotalFee: 200000,
+		Rows: []domain.CardBatchRow{
+			{Line: 3, FirstName: "Ada", LastName: "Obi", Email: "ada@company.com", Phone: "08030000001",
+				Type: "virtual", Brand: "verve", Template: domain.DefaultSpendingTemplate, Status: domain.RowPending},
+			{Line: 2, FirstName: "Tunde", LastName: "Bello", Email: "tunde@company.com", Phone: "08030000002",
+				Type: "physical", Brand: "visa", Template: "travel", Status: domain.RowPending},
+		},
+	}
+
+	err := cardBatchRepository.Persist(args)
+	require.NoError(t, err)
+
+	batch, err := cardBatchRepository.GetByID(args.ID.String())
+	require.NoError(t, err)
+	require.NotEmpty(t, batch)
+
+	require.Equal(t, args.Company, batch.Company)
+	require.Equal(t, args.TotalFee, batch.TotalFee)
+	require.Len(t, batch.Rows, 2)
+	require.Equal(t, 2, batch.Rows[0].Line)
+	require.Equal(t, 3, batch.Rows[1].Line)
+
+	return batch
+}
+
+func TestCardBatchRowOutcome(t *testing.T) {
+	cardBatchRepository := NewCardBatchRepository(DBConnection)
+	batch := createRandomCardBatch(t)
+
+	card := (&utils.Faker{}).RandomUUID()
+	batch.Rows[0].Status = domain.RowIssued
+	batch.Rows[0].Card = &card
+	batch.Rows[1].Status = domain.RowFailed
+	batch.Rows[1].Error = "issuer unavailable"
+
+	for i := range batch.Rows {
+		err := cardBatchRepository.PersistRow(&batch.Rows[i])
+		require.NoError(t, err)
+	}
+
+	batch, err := cardBatchRepository.GetByID(batch.ID.String())
+	require.NoError(t, err)
+	require.Equal(t, domain.RowIssued, batch.Rows[0].Status)
+	require.Equal(t, card, *batch.Rows[0].Card)
+	require.Equal(t, domain.RowFailed, batch.Rows[1].Status)
+	require.Nil(t, batch.Rows[1].Card)
+	require.Equal(t, "issuer unavailable", batch.Rows[1].Error)
+}
+
+func TestCardBatchGetByStatus(t *testing.T) {
+	cardBatchRepository := NewCardBatchRepository(DBConnection)
+	batch := createRandomCardBatch(t)
+
+	batch.Status = domain.BatchProcessing
+	batch.Issued = 1
+	batch.Rows = nil
+	err := cardBatchRepository.Persist(batch)
+	require.NoError(t, err)
+
+	batches, err := cardBatchRepository.GetByStatus(domain.BatchProcessing)
+	require.NoError(t, err)
+
+	var found *domain.CardBatch
+	for i := range batches {
+		if batches[i].ID == batch.ID {
+			found = &batches[i]
+		}
+	}
+	require.NotNil(t, found)
+	require.Equal(t, 1, found.Issued)
+	require.Len(t, found.Rows, 2)
+
+	batches, err = cardBatchRepository.GetByStatus(domain.BatchCompleted)
+	require.NoError(t, err)
+	for _, b := range batches {
+		require.NotEqual(t, batch.ID, b.ID)
+	}
+}
+
+func TestCardBatchClaimRow(t *testing.T) {
+	cardBatchRepository := NewCardBatchRepository(DBConnection)
+	batch := createRandomCardBatch(t)
+
+	staleBefore := time.Now().Add(-domain.CardBatchClaimTTL)
+
+	err := cardBatchRepository.ClaimRow(batch.Rows[0].ID.String(), staleBefore)
+	require.NoError(t, err)
+
+	err = cardBatchRepository.ClaimRow(batch.Rows[0].ID.String(), staleBefore)
+	require.ErrorIs(t, err, domain.ErrCardBatchRowClaimed)
+
+	batch.Rows[1].Status = domain.RowFailed
+	err = cardBatchRepository.PersistRow(&batch.Rows[1])
+	require.NoError(t, err)
+
+	// a row still claimed keeps the batch processing
+	batch.Status = domain.BatchProcessing
+	err = cardBatchRepository.UpdateProgress(batch)
+	require.NoError(t, err)
+	require.Equal(t, 0, batch.Issued)
+	require.Equal(t, 1, batch.Failed)
+
+	batch, err = cardBatchRepository.GetByID(batch.ID.String())
+	require.NoError(t, err)
+	require.Equal(t, domain.BatchProcessing, batch.Status)
+	require.Equal(t, 1, batch.Failed)
+	require.Equal(t, domain.RowIssuing, batch.Rows[0].Status)
+
+	// a stale claim is taken over and the batch completes with its last row
+	err = cardBatchRepository.ClaimRow(batch.Rows[0].ID.String(), time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	batch.Rows[0].Status = domain.RowIssued
+	err = cardBatchRepository.PersistRow(&batch.Rows[0])
+	require.NoError(t, err)
+
+	err = cardBatchRepository.UpdateProgress(batch)
+	require.NoError(t, err)
+	require.Equal(t, domain.BatchCompleted, batch.Status)
+	require.Equal(t, 1, batch.Issued)
+}
@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"core_business/pkg/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type cardFulfillmentRepository struct {
+	db *gorm.DB
+}
+
+// NewCardFulfillmentRepository creates a new instance card fulfillment repository
+func NewCardFulfillmentRepository(db *gorm.DB) ports.ICardFulfillmentRepository {
+	return &cardFulfillmentRepository{
+		db: db,
+	}
+}
+
+func (c *cardFulfillmentRepository) GetByID(id string) (*domain.CardFulfillment, error) {
+	var fulfillment domain.CardFulfillment
+	if err := c.db.Where("id = ?", id).
+		Preload("Events", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at")
+		}).
+		First(&fulfillment).Error; err != nil {
+		return nil, err
+	}
+	return &fulfillment, nil
+}
+
+func (c *cardFulfillmentRepository) GetByCardID(id string) (*domain.CardFulfillment, error) {
+	var fulfillment domain.CardFulfillment
+	if err := c.db.Where("card = ?", id).
+		Preload("Events", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at")
+		}).
+		First(&fulfillment).Error; err != nil {
+		return nil, err
+	}
+	return &fulfillment, nil
+}
+
+func (c *cardFulfillmentRepository) GetFulfillmentByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error) {
+	var fulfillments []domain.CardFulfillment
+	query := c.db.Where("company = ?", id)
+
+	if filter := pagination.GetFilter(); filter != "" {
+		query = query.Where("status = ?", filter)
+	}
+
+	if err := query.Scopes(utils.Paginate(fulfillments, pagination, query.Session(&gorm.Session{}))).
+		Find(&fulfillments).Error; err != nil {
+		return nil, err
+	}
+
+	pagination.Rows = fulfillments
+	return pagination, nil
+}
+
+func (c *cardFulfillmentRepository) Persist(fulfillment *domain.CardFulfillment) error {
+	if err := c.db.Omit(clause.Associations).Save(fulfillment).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// PersistStatusChange saves the fulfillment with its new status and the event together
+func (c *cardFulfillmentRepository) PersistStatusChange(fulfillment *domain.CardFulfillment, event *domain.CardFulfillmentEvent) error {
+	return c.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(fulfillment).Error; err != nil {
+			return err
+		}
+
+		event.Fulfillment = fulfillment.ID
+		return tx.Create(event).Error
+	})
+}
+
+func (c *cardFulfillmentRepository) DeleteAll() error {
+	if err := c.db.Exec("DELETE FROM card_fulfillments").Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *cardFulfillmentRepository) WithTx(tx *gorm.DB) ports.ICardFulfillmentRepository {
+	return NewCardFulfillmentRepository(tx)
+}
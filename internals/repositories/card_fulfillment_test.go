@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"core_business/internals/core/domain"
+	"core_business/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestFulfillmentPersistStatusChange(t *testing.T) {
+	fulfillmentRepository := NewCardFulfillmentRepository(DBConnection)
+
+	fulfillment := &domain.CardFulfillment{
+		Card:    (&utils.Faker{}).RandomUUID(),
+		Company: (&utils.Faker{}).RandomUUID(),
+		Status:  domain.FulfillmentOrdered,
+		ShippingAddress: domain.ShippingAddress{
+			Recipient: "Ada Obi",
+			Line1:     "12 Admiralty Way",
+			City:      "Lekki",
+			State:     "Lagos",
+			Country:   "NG",
+		},
+	}
+	err := fulfillmentRepository.PersistStatusChange(fulfillment, &domain.CardFulfillmentEvent{
+		To:    domain.FulfillmentOrdered,
+		Actor: "system",
+	})
+	require.NoError(t, err)
+
+	fulfillment.Status = domain.FulfillmentPrinted
+	err = fulfillmentRepository.PersistStatusChange(fulfillment, &domain.CardFulfillmentEvent{
+		From:  domain.FulfillmentOrdered,
+		To:    domain.FulfillmentPrinted,
+		Actor: "ops@company.com",
+	})
+	require.NoError(t, err)
+
+	found, err := fulfillmentRepository.GetByCardID(fulfillment.Card.String())
+	require.NoError(t, err)
+	require.Equal(t, fulfillment.ID, found.ID)
+	require.Equal(t, domain.FulfillmentPrinted, found.Status)
+	require.Equal(t, "Lekki", found.ShippingAddress.City)
+	require.Len(t, found.Events, 2)
+	require.Equal(t, domain.FulfillmentPrinted, found.Events[1].To)
+}
+
+func TestGetFulfillmentByCompanyID(t *testing.T) {
+	fulfillmentRepository := NewCardFulfillmentRepository(DBConnection)
+	company := (&utils.Faker{}).RandomUUID()
+
+	for _, status := range []domain.FulfillmentStatus{domain.FulfillmentOrdered, domain.FulfillmentDispatched} {
+		err := fulfillmentRepository.Persist(&domain.CardFulfillment{
+			Card:    (&utils.Faker{}).RandomUUID(),
+			Company: company,
+			Status:  status,
+		})
+		require.NoError(t, err)
+	}
+
+	pagination := &utils.Pagination{Limit: 10, Page: 1, Filter: string(domain.FulfillmentDispatched)}
+	fulfillments, err := fulfillmentRepository.GetFulfillmentByCompanyID(company.String(), pagination)
+	require.NoError(t, err)
+	require.Len(t, fulfillments.Rows, 1)
+}
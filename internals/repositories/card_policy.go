@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"core_business/pkg/utils"
+	"gorm.io/gorm"
+)
+
+type cardPolicyRepository struct {
+	db *gorm.DB
+}
+
+// NewCardPolicyRepository creates a new instance card policy repository
+func NewCardPolicyRepository(db *gorm.DB) ports.ICardPolicyRepository {
+	return &cardPolicyRepository{
+		db: db,
+	}
+}
+
+func (c *cardPolicyRepository) GetByID(id string) (*domain.CardPolicy, error) {
+	var policy domain.CardPolicy
+	if err := c.db.Where("id = ?", id).First(&policy).Error; err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// GetByName returns the card policy of the company with the name, names are compared case insensitively
+func (c *cardPolicyRepository) GetByName(company string, name string) (*domain.CardPolicy, error) {
+	var policy domain.CardPolicy
+	if err := c.db.Where("company = ? AND LOWER(name) = LOWER(?)", company, name).First(&policy).Error; err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (c *cardPolicyRepository) GetCardPolicyByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error) {
+	var policies []domain.CardPolicy
+	query := c.db.Where("company = ?", id)
+
+	if err := query.Scopes(utils.Paginate(policies, pagination, query.Session(&gorm.Session{}))).
+		Find(&policies).Error; err != nil {
+		return nil, err
+	}
+
+	pagination.Rows = policies
+	return pagination, nil
+}
+
+func (c *cardPolicyRepository) Persist(policy *domain.CardPolicy) error {
+	if err := c.db.Save(policy).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *cardPolicyRepository) Delete(id string) error {
+	if err := c.db.Where("id = ?", id).Delete(&domain.CardPolicy{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *cardPolicyRepository) DeleteAll() error {
+	if err := c.db.Exec("DELETE FROM card_policies").Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *cardPolicyRepository) WithTx(tx *gorm.DB) ports.ICardPolicyRepository {
+	return NewCardPolicyRepository(tx)
+}
@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"core_business/internals/core/domain"
+	"core_business/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestCardPolicyGetByName(t *testing.T) {
+	cardPolicyRepository := NewCardPolicyRepository(DBConnection)
+
+	policy := domain.CardPolicyPresets["sales-travel"]
+	policy.Company = (&utils.Faker{}).RandomUUID()
+	err := cardPolicyRepository.Persist(&policy)
+	require.NoError(t, err)
+	require.NotEmpty(t, policy.ID)
+
+	found, err := cardPolicyRepository.GetByName(policy.Company.String(), "sales TRAVEL card")
+	require.NoError(t, err)
+	require.Equal(t, policy.ID, found.ID)
+	require.Equal(t, 90, found.ValidityDays)
+	require.Equal(t, policy.SpendingControls.AllowedCategories, found.SpendingControls.AllowedCategories)
+	require.Equal(t, 500000, found.SpendingControls.SpendingLimits.Amount)
+
+	_, err = cardPolicyRepository.GetByName((&utils.Faker{}).RandomUUID().String(), "Sales travel card")
+	require.Error(t, err)
+}
+
+func TestCardGetByPolicy(t *testing.T) {
+	cardRepository := NewCardRepository(DBConnection)
+	policy := (&utils.Faker{}).RandomUUID()
+	ended := time.Now().Add(-time.Hour)
+
+	ids := map[domain.CardStatus]string{}
+	for _, status := range []domain.CardStatus{domain.CardActive, domain.CardCanceled} {
+		card := &domain.Card{
+			Company:       Company.ID,
+			Name:          (&utils.Faker{}).RandomName(),
+			Status:        string(status),
+			PartnerCardID: (&utils.Faker{}).RandomObjectID(),
+			MaskedPan:     "506321*******1234",
+			ExpiryMonth:   "12",
+			ExpiryYear:    "2030",
+			Policy:        &policy,
+			ValidUntil:    &ended,
+		}
+		require.NoError(t, cardRepository.Persist(card))
+		ids[status] = card.ID.String()
+	}
+
+	statuses := []string{string(domain.CardActive), string(domain.CardInactive), string(domain.CardLocked)}
+	cards, err := cardRepository.GetByPolicy(policy.String(), statuses)
+	require.NoError(t, err)
+	require.Len(t, cards, 1)
+	require.Equal(t, ids[domain.CardActive], cards[0].ID.String())
+	require.False(t, cards[0].Usable())
+
+	cards, err = cardRepository.GetPastValidity(statuses, time.Now())
+	require.NoError(t, err)
+
+	found := map[string]bool{}
+	for _, card := range cards {
+		found[card.ID.String()] = true
+	}
+	require.True(t, found[ids[domain.CardActive]])
+	require.False(t, found[ids[domain.CardCanceled]])
+}
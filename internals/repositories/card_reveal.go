@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"gorm.io/gorm"
+	"time"
+)
+
+type cardRevealRepository struct {
+	db *gorm.DB
+}
+
+// NewCardRevealRepository creates a new instance card reveal repository
+func NewCardRevealRepository(db *gorm.DB) ports.ICardRevealRepository {
+	return &cardRevealRepository{
+		db: db,
+	}
+}
+
+func (c *cardRevealRepository) PersistToken(token *domain.CardRevealToken) error {
+	if err := c.db.Create(token).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// UseToken marks the token with the hash used, a token can only be used once and before it expires
+func (c *cardRevealRepository) UseToken(hash string, at time.Time) (*domain.CardRevealToken, error) {
+	used := c.db.Model(&domain.CardRevealToken{}).
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", hash, at).
+		Update("used_at", at)
+	if used.Error != nil {
+		return nil, used.Error
+	}
+
+	if used.RowsAffected == 0 {
+		return nil, domain.ErrInvalidRevealToken
+	}
+
+	var token domain.CardRevealToken
+	if err := c.db.Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (c *cardRevealRepository) PersistReveal(reveal *domain.CardReveal) error {
+	if err := c.db.Create(reveal).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetRevealsByCardID returns the reveals of the card, latest first
+func (c *cardRevealRepository) GetRevealsByCardID(id string) ([]domain.CardReveal, error) {
+	var reveals []domain.CardReveal
+	if err := c.db.Where("card = ?", id).
+		Order("created_at desc").
+		Find(&reveals).Error; err != nil {
+		return nil, err
+	}
+	return reveals, nil
+}
+
+func (c *cardRevealRepository) DeleteAll() error {
+	if err := c.db.Exec("DELETE FROM card_reveal_tokens").Error; err != nil {
+		return err
+	}
+	if err := c.db.Exec("DELETE FROM card_reveals").Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *cardRevealRepository) WithTx(tx *gorm.DB) ports.ICardRevealRepository {
+	return NewCardRevealRepository(tx)
+}
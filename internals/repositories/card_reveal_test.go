@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"core_business/internals/core/domain"
+	"core_business/pkg/utils"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestUseRevealToken(t *testing.T) {
+	cardRevealRepository := NewCardRevealRepository(DBConnection)
+	now := time.Now()
+
+	token := &domain.CardRevealToken{
+		Card:      (&utils.Faker{}).RandomUUID(),
+		TokenHash: (&utils.Faker{}).RandomUUID().String(),
+		Actor:     "ada@company.com",
+		IP:        "10.0.0.1",
+		ExpiresAt: now.Add(domain.CardRevealTokenTTL),
+	}
+	err := cardRevealRepository.PersistToken(token)
+	require.NoError(t, err)
+
+	used, err := cardRevealRepository.UseToken(token.TokenHash, now)
+	require.NoError(t, err)
+	require.Equal(t, token.ID, used.ID)
+	require.Equal(t, token.Card, used.Card)
+	require.NotNil(t, used.UsedAt)
+
+	_, err = cardRevealRepository.UseToken(token.TokenHash, now)
+	require.True(t, errors.Is(err, domain.ErrInvalidRevealToken))
+
+	_, err = cardRevealRepository.UseToken("unknown", now)
+	require.True(t, errors.Is(err, domain.ErrInvalidRevealToken))
+
+	expired := &domain.CardRevealToken{
+		Card:      token.Card,
+		TokenHash: (&utils.Faker{}).RandomUUID().String(),
+		Actor:     "ada@company.com",
+		ExpiresAt: now.Add(-time.Second),
+	}
+	err = cardRevealRepository.PersistToken(expired)
+	require.NoError(t, err)
+
+	_, err = cardRevealRepository.UseToken(expired.TokenHash, now)
+	require.True(t, errors.Is(err, domain.ErrInvalidRevealToken))
+}
+
+func TestGetRevealsByCardID(t *testing.T) {
+	cardRevealRepository := NewCardRevealRepository(DBConnection)
+	card := (&utils.Faker{}).RandomUUID()
+
+	for _, actor := range []string{"ada@company.com", "tunde@company.com"} {
+		err := cardRevealRepository.PersistReveal(&domain.CardReveal{
+			Card:    card,
+			Company: (&utils.Faker{}).RandomUUID(),
+			Token:   (&utils.Faker{}).RandomUUID(),
+			Actor:   actor,
+			IP:      "10.0.0.1",
+		})
+		require.NoError(t, err)
+		time.Sleep(time.Millisecond)
+	}
+
+	reveals, err := cardRevealRepository.GetRevealsByCardID(card.String())
+	require.NoError(t, err)
+	require.Len(t, reveals, 2)
+	require.Equal(t, "tunde@company.com", reveals[0].Actor)
+	require.Equal(t, "10.0.0.1", reveals[0].IP)
+}
@@ -0,0 +1,242 @@
+package repositories
+
+import (
+	"core_business/internals/core/domain"
+	"core_business/pkg/utils"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestCardStatusHistory(t *testing.T) {
+	cardRepository := NewCardRepository(DBConnection)
+
+	card := &domain.Card{
+		Company:       Company.ID,
+		Name:          (&utils.Faker{}).RandomName(),
+		Status:        string(domain.CardActive),
+		PartnerCardID: (&utils.Faker{}).RandomObjectID(),
+		MaskedPan:     "506321*******1234",
+		ExpiryMonth:   "12",
+		ExpiryYear:    "2030",
+	}
+
+	err := cardRepository.PersistStatusChange(card, &domain.CardStatusChange{To: domain.CardActive, Actor: "system", Reason: "card issued"})
+	require.NoError(t, err)
+	require.NotEmpty(t, card.ID)
+
+	card.Status = string(domain.CardLocked)
+	card.Lock = true
+	err = cardRepository.PersistStatusChange(card, &domain.CardStatusChange{From: domain.CardActive, To: domain.CardLocked, Actor: "system:fraud", Reason: "fraud rule matched"})
+	require.NoError(t, err)
+
+	saved, err := cardRepository.GetByID(card.ID.String())
+	require.NoError(t, err)
+	require.Equal(t, string(domain.CardLocked), saved.Status)
+	require.True(t, saved.Lock)
+	require.False(t, saved.Usable())
+
+	history, err := cardRepository.GetStatusHistory(card.ID.String())
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, card.ID, history[0].Card)
+	require.Equal(t, domain.CardActive, history[0].To)
+	require.Equal(t, domain.CardLocked, history[1].To)
+	require.Equal(t, "system:fraud", history[1].Actor)
+}
+
+func TestCardGetByStatus(t *testing.T) {
+	cardRepository := NewCardRepository(DBConnection)
+
+	statuses := []domain.CardStatus{domain.CardLocked, domain.CardCanceled}
+	ids := map[domain.CardStatus]string{}
+	for _, status := range statuses {
+		card := &domain.Card{
+			Company:       Company.ID,
+			Name:          (&utils.Faker{}).RandomName(),
+			Status:        string(status),
+			PartnerCardID: (&utils.Faker{}).RandomObjectID(),
+			MaskedPan:     "506321*******1234",
+			ExpiryMonth:   "12",
+			ExpiryYear:    "2030",
+		}
+		require.NoError(t, cardRepository.Persist(card))
+		ids[status] = card.ID.String()
+	}
+
+	cards, err := cardRepository.GetByStatus([]string{string(domain.CardLocked), string(domain.CardInactive)})
+	require.NoError(t, err)
+
+	found := map[string]bool{}
+	for _, card := range cards {
+		require.Contains(t, []string{string(domain.CardLocked), string(domain.CardInactive)}, card.Status)
+		found[card.ID.String()] = true
+	}
+	require.True(t, found[ids[domain.CardLocked]])
+	require.False(t, found[ids[domain.CardCanceled]])
+}
+
+func TestCardGetByExpiryYear(t *testing.T) {
+	cardRepository := NewCardRepository(DBConnection)
+
+	ids := map[string]string{}
+	for _, year := range []string{"2019", "19", "2099", "99", "999"} {
+		card := &domain.Card{
+			Company:       Company.ID,
+			Name:          (&utils.Faker{}).RandomName(),
+			Status:        string(domain.CardActive),
+			PartnerCardID: (&utils.Faker{}).RandomObjectID(),
+			MaskedPan:     "506321*******1234",
+			ExpiryMonth:   "06",
+			ExpiryYear:    year,
+		}
+		require.NoError(t, cardRepository.Persist(card))
+		ids[year] = card.ID.String()
+	}
+
+	cards, err := cardRepository.GetByExpiryYear([]string{string(domain.CardActive)}, 2020)
+	require.NoError(t, err)
+
+	found := map[string]bool{}
+	for _, card := range cards {
+		found[card.ID.String()] = true
+	}
+	require.True(t, found[ids["2019"]])
+	require.True(t, found[ids["19"]])
+	require.False(t, found[ids["2099"]])
+	require.False(t, found[ids["99"]])
+	require.False(t, found[ids["999"]])
+
+	renewal := (&utils.Faker{}).RandomUUID()
+	err = cardRepository.PersistRecurringMerchants([]domain.CardRecurringMerchant{
+		{Card: renewal, PreviousCard: cards[0].ID, MerchantName: "Netflix", Months: 3, LastAmount: 4400},
+		{Card: renewal, PreviousCard: cards[0].ID, MerchantName: "Figma", Months: 2, LastAmount: 12000},
+	})
+	require.NoError(t, err)
+
+	merchants, err := cardRepository.GetRecurringMerchants(renewal.String())
+	require.NoError(t, err)
+	require.Len(t, merchants, 2)
+	require.Equal(t, "Figma", merchants[0].MerchantName)
+	require.Equal(t, 3, merchants[1].Months)
+}
+
+func TestCardLockToMerchant(t *testing.T) {
+	cardRepository := NewCardRepository(DBConnection)
+
+	card := &domain.Card{
+		Company:       Company.ID,
+		Name:          (&utils.Faker{}).RandomName(),
+		Status:        string(domain.CardActive),
+		PartnerCardID: (&utils.Faker{}).RandomObjectID(),
+		MaskedPan:     "506321*******1234",
+		ExpiryMonth:   "12",
+		ExpiryYear:    "2030",
+		Mode:          domain.MerchantLockedCard,
+	}
+	require.NoError(t, cardRepository.Persist(card))
+
+	err := cardRepository.LockToMerchant(card.ID.String(), "netflix-001", "Netflix")
+	require.NoError(t, err)
+
+	// the same merchant keeps being approved
+	err = cardRepository.LockToMerchant(card.ID.String(), "netflix-001", "Netflix")
+	require.NoError(t, err)
+
+	err = cardRepository.LockToMerchant(card.ID.String(), "spotify-001", "Spotify")
+	require.True(t, errors.Is(err, domain.ErrMerchantNotAllowed))
+
+	saved, err := cardRepository.GetByID(card.ID.String())
+	require.NoError(t, err)
+	require.Equal(t, "netflix-001", saved.LockedMerchantID)
+	require.Equal(t, "Netflix", saved.LockedMerchant)
+}
+
+func TestCardClaimSingleUse(t *testing.T) {
+	cardRepository := NewCardRepository(DBConnection)
+
+	card := &domain.Card{
+		Company:       Company.ID,
+		Name:          (&utils.Faker{}).RandomName(),
+		Status:        string(domain.CardActive),
+		PartnerCardID: (&utils.Faker{}).RandomObjectID(),
+		MaskedPan:     "506321*******1234",
+		ExpiryMonth:   "12",
+		ExpiryYear:    "2030",
+		Mode:          domain.SingleUseCard,
+	}
+	require.NoError(t, cardRepository.Persist(card))
+
+	err := cardRepository.ClaimSingleUse(card.ID.String(), "auth-001")
+	require.NoError(t, err)
+
+	// an incremental authorization is the same use of the card
+	err = cardRepository.ClaimSingleUse(card.ID.String(), "auth-001")
+	require.NoError(t, err)
+
+	err = cardRepository.ClaimSingleUse(card.ID.String(), "auth-002")
+	require.True(t, errors.Is(err, domain.ErrSingleUseCardUsed))
+
+	// releasing with another authorization leaves the claim
+	require.NoError(t, cardRepository.ReleaseSingleUse(card.ID.String(), "auth-002"))
+	saved, err := cardRepository.GetByID(card.ID.String())
+	require.NoError(t, err)
+	require.Equal(t, "auth-001", saved.UsedBy)
+
+	require.NoError(t, cardRepository.ReleaseSingleUse(card.ID.String(), "auth-001"))
+	err = cardRepository.ClaimSingleUse(card.ID.String(), "auth-002")
+	require.NoError(t, err)
+}
+
+func TestCardSchedule(t *testing.T) {
+	cardRepository := NewCardRepository(DBConnection)
+
+	card := &domain.Card{
+		Company:       Company.ID,
+		Name:          (&utils.Faker{}).RandomName(),
+		Status:        string(domain.CardActive),
+		PartnerCardID: (&utils.Faker{}).RandomObjectID(),
+		MaskedPan:     "506321*******1234",
+		ExpiryMonth:   "12",
+		ExpiryYear:    "2030",
+		Schedule: &domain.CardSchedule{
+			TimeZone: "Africa/Lagos",
+			Windows: []domain.ScheduleWindow{
+				{Days: []string{"mon", "tue", "wed", "thu", "fri"}, Start: "08:00", End: "20:00"},
+				{Days: []string{"saturday"}, Start: "22:00", End: "02:00"},
+			},
+		},
+	}
+	require.NoError(t, card.Schedule.Validate())
+	require.NoError(t, cardRepository.Persist(card))
+
+	saved, err := cardRepository.GetByID(card.ID.String())
+	require.NoError(t, err)
+	require.NotNil(t, saved.Schedule)
+	require.Len(t, saved.Schedule.Windows, 2)
+
+	lagos, err := time.LoadLocation("Africa/Lagos")
+	require.NoError(t, err)
+
+	cases := map[time.Time]bool{
+		time.Date(2026, 10, 19, 10, 0, 0, 0, lagos):    true,  // monday morning
+		time.Date(2026, 10, 19, 20, 0, 0, 0, lagos):    false, // monday, the window has ended
+		time.Date(2026, 10, 24, 10, 0, 0, 0, lagos):    false, // saturday morning
+		time.Date(2026, 10, 24, 23, 30, 0, 0, lagos):   true,  // saturday night
+		time.Date(2026, 10, 25, 1, 0, 0, 0, lagos):     true,  // past midnight into sunday
+		time.Date(2026, 10, 25, 3, 0, 0, 0, lagos):     false,
+		time.Date(2026, 10, 19, 8, 30, 0, 0, time.UTC): true, // 09:30 in Lagos
+	}
+	for at, expected := range cases {
+		allowed, err := saved.Schedule.Allows(at)
+		require.NoError(t, err)
+		require.Equal(t, expected, allowed, at.String())
+	}
+
+	invalid := &domain.CardSchedule{TimeZone: "Mars/Olympus", Windows: card.Schedule.Windows}
+	require.True(t, errors.Is(invalid.Validate(), domain.ErrInvalidSchedule))
+
+	invalid = &domain.CardSchedule{Windows: []domain.ScheduleWindow{{Days: []string{"someday"}, Start: "08:00", End: "20:00"}}}
+	require.True(t, errors.Is(invalid.Validate(), domain.ErrInvalidSchedule))
+}
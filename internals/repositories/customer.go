@@ -34,6 +34,28 @@ func (c *customerRepository) GetBy(filter interface{}) (*domain.Customer, error)
 	return customer, nil
 }
 
+// GetByEmail returns the cardholder of the company with the email
+func (c *customerRepository) GetByEmail(company string, email string) (*domain.Customer, error) {
+	var customer domain.Customer
+	if err := c.db.Where("company = ? AND email = ?", company, email).First(&customer).Error; err != nil {
+		return nil, err
+	}
+	return &customer, nil
+}
+
+func (c *customerRepository) GetCustomerByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error) {
+	var customers []domain.Customer
+	query := c.db.Where("company = ?", id)
+
+	if err := query.Scopes(utils.Paginate(customers, pagination, query.Session(&gorm.Session{}))).
+		Find(&customers).Error; err != nil {
+		return nil, err
+	}
+
+	pagination.Rows = customers
+	return pagination, nil
+}
+
 func (c *customerRepository) Get(pagination *utils.Pagination) (*utils.Pagination, error) {
 	var customers []domain.Customer
 	if err := c.db.Scopes(utils.Paginate(customers, pagination, c.db)).Find(&customers).Error; err != nil {
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"core_business/internals/core/domain"
+	"core_business/pkg/utils"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func TestCustomerCards(t *testing.T) {
+	customerRepository := NewCustomerRepository(DBConnection)
+	cardRepository := NewCardRepository(DBConnection)
+	company := (&utils.Faker{}).RandomUUID()
+
+	customer := &domain.Customer{
+		Company:   company,
+		FirstName: "Ada",
+		LastName:  "Obi",
+		Email:     "ada@company.com",
+		Phone:     "08030000001",
+	}
+	err := customerRepository.Persist(customer)
+	require.NoError(t, err)
+
+	found, err := customerRepository.GetByEmail(company.String(), "ada@company.com")
+	require.NoError(t, err)
+	require.Equal(t, customer.ID, found.ID)
+
+	_, err = customerRepository.GetByEmail((&utils.Faker{}).RandomUUID().String(), "ada@company.com")
+	require.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+	customers, err := customerRepository.GetCustomerByCompanyID(company.String(), &utils.Pagination{})
+	require.NoError(t, err)
+	require.Len(t, customers.Rows, 1)
+
+	for _, status := range []domain.CardStatus{domain.CardActive, domain.CardCanceled} {
+		err = cardRepository.Persist(&domain.Card{
+			Company:       company,
+			Customer:      customer.ID,
+			Name:          "Ada Obi",
+			Status:        string(status),
+			PartnerCardID: (&utils.Faker{}).RandomObjectID(),
+			MaskedPan:     "506321*******1234",
+			ExpiryMonth:   "12",
+			ExpiryYear:    "2030",
+		})
+		require.NoError(t, err)
+	}
+
+	cards, err := cardRepository.GetCardByCustomerID(customer.ID.String(), &utils.Pagination{})
+	require.NoError(t, err)
+	require.Len(t, cards.Rows, 2)
+
+	cards, err = cardRepository.GetCardByCustomerID(customer.ID.String(), &utils.Pagination{Filter: string(domain.CardActive)})
+	require.NoError(t, err)
+	require.Len(t, cards.Rows, 1)
+}
@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"core_business/pkg/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type disputeRepository struct {
+	db *gorm.DB
+}
+
+// NewDisputeRepository creates a new instance dispute repository
+func NewDisputeRepository(db *gorm.DB) ports.IDisputeRepository {
+	return &disputeRepository{
+		db: db,
+	}
+}
+
+func (d *disputeRepository) GetByID(id string) (*domain.Dispute, error) {
+	var dispute domain.Dispute
+	if err := d.db.Where("id = ?", id).
+		Preload(clause.Associations).
+		First(&dispute).Error; err != nil {
+		return nil, err
+	}
+	return &dispute, nil
+}
+
+func (d *disputeRepository) GetDisputeByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error) {
+	var disputes []domain.Dispute
+	query := d.db.Where("company = ?", id)
+
+	if filter := pagination.GetFilter(); filter != "" {
+		query = query.Where("status = ?", filter)
+	}
+
+	if err := query.Scopes(utils.Paginate(disputes, pagination, query.Session(&gorm.Session{}))).
+		Preload(clause.Associations).
+		Find(&disputes).Error; err != nil {
+		return nil, err
+	}
+
+	pagination.Rows = disputes
+	return pagination, nil
+}
+
+func (d *disputeRepository) GetActiveByTransaction(id string) ([]domain.Dispute, error) {
+	var disputes []domain.Dispute
+	if err := d.db.Where("transaction_id = ? AND status IN ?", id,
+		[]domain.DisputeStatus{domain.DisputeOpened, domain.DisputeSubmitted}).
+		Find(&disputes).Error; err != nil {
+		return nil, err
+	}
+	return disputes, nil
+}
+
+func (d *disputeRepository) GetByTransaction(id string) ([]domain.Dispute, error) {
+	var disputes []domain.Dispute
+	if err := d.db.Where("transaction_id = ?", id).Find(&disputes).Error; err != nil {
+		return nil, err
+	}
+	return disputes, nil
+}
+
+func (d *disputeRepository) Persist(dispute *domain.Dispute) error {
+	if dispute.ID.String() != "" {
+		if err := d.db.Save(dispute).Error; err != nil {
+			return err
+		}
+		return nil
+	}
+	if err := d.db.Create(&dispute).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (d *disputeRepository) PersistEvidence(evidence *domain.DisputeEvidence) error {
+	if err := d.db.Create(evidence).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (d *disputeRepository) Delete(id string) error {
+	if err := d.db.Where("id = ?", id).Delete(&domain.Dispute{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (d *disputeRepository) DeleteAll() error {
+	if err := d.db.Exec("DELETE FROM disputes").Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (d *disputeRepository) WithTx(tx *gorm.DB) ports.IDisputeRepository {
+	return NewDisputeRepository(tx)
+}
@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"core_business/internals/core/domain"
+	"core_business/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func createRandomDispute(t *testing.T) *domain.Dispute {
+	disputeRepository := NewDisputeRepository(DBConnection)
+
+	args := &domain.Dispute{
+		Company:     (&utils.Faker{}).RandomUUID(),
+		Wallet:      (&utils.Faker{}).RandomUUID(),
+		Card:        (&utils.Faker{}).RandomUUID(),
+		Transaction: (&utils.Faker{}).RandomUUID(),
+		Amount:      2500,
+		Reason:      domain.DuplicateReason,
+		Status:      domain.DisputeOpened,
+		Evidence: []domain.DisputeEvidence{
+			{URL: "https://evidence/1.png", Description: "statement"},
+		},
+	}
+
+	err := disputeRepository.Persist(args)
+	require.NoError(t, err)
+
+	dispute, err := disputeRepository.GetByID(args.ID.String())
+	require.NoError(t, err)
+	require.NotEmpty(t, dispute)
+
+	require.Equal(t, args.Transaction, dispute.Transaction)
+	require.Equal(t, args.Amount, dispute.Amount)
+	require.Equal(t, args.Reason, dispute.Reason)
+	require.Len(t, dispute.Evidence, 1)
+
+	return dispute
+}
+
+func TestGetActiveDisputeByTransaction(t *testing.T) {
+	disputeRepository := NewDisputeRepository(DBConnection)
+	dispute := createRandomDispute(t)
+
+	disputes, err := disputeRepository.GetActiveByTransaction(dispute.Transaction.String())
+	require.NoError(t, err)
+	require.Len(t, disputes, 1)
+
+	dispute.Status = domain.DisputeLost
+	err = disputeRepository.Persist(dispute)
+	require.NoError(t, err)
+
+	disputes, err = disputeRepository.GetActiveByTransaction(dispute.Transaction.String())
+	require.NoError(t, err)
+	require.Empty(t, disputes)
+
+	disputes, err = disputeRepository.GetByTransaction(dispute.Transaction.String())
+	require.NoError(t, err)
+	require.Len(t, disputes, 1)
+
+	err = disputeRepository.PersistEvidence(&domain.DisputeEvidence{Dispute: dispute.ID, URL: "https://evidence/2.png"})
+	require.NoError(t, err)
+
+	dispute, err = disputeRepository.GetByID(dispute.ID.String())
+	require.NoError(t, err)
+	require.Len(t, dispute.Evidence, 2)
+}
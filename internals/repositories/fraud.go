@@ -0,0 +1,141 @@
+package repositories
+
+import (
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"core_business/pkg/utils"
+	"gorm.io/gorm"
+	"time"
+)
+
+type fraudRepository struct {
+	db *gorm.DB
+}
+
+// NewFraudRepository creates a new instance fraud repository
+func NewFraudRepository(db *gorm.DB) ports.IFraudRepository {
+	return &fraudRepository{
+		db: db,
+	}
+}
+
+func (f *fraudRepository) GetRuleByID(id string) (*domain.FraudRule, error) {
+	var rule domain.FraudRule
+	if err := f.db.Where("id = ?", id).First(&rule).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (f *fraudRepository) GetRulesByCompanyID(id string) ([]domain.FraudRule, error) {
+	var rules []domain.FraudRule
+	if err := f.db.Where("company = ?", id).Order("created_at").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (f *fraudRepository) PersistRule(rule *domain.FraudRule) error {
+	if err := f.db.Save(rule).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (f *fraudRepository) DeleteRule(id string) error {
+	if err := f.db.Where("id = ?", id).Delete(&domain.FraudRule{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (f *fraudRepository) GetAlertByID(id string) (*domain.FraudAlert, error) {
+	var alert domain.FraudAlert
+	if err := f.db.Where("id = ?", id).First(&alert).Error; err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+func (f *fraudRepository) GetAlertsByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error) {
+	var alerts []domain.FraudAlert
+	query := f.db.Where("company = ?", id)
+
+	if filter := pagination.GetFilter(); filter != "" {
+		query = query.Where("status = ?", filter)
+	}
+
+	if err := query.Scopes(utils.Paginate(alerts, pagination, query.Session(&gorm.Session{}))).
+		Find(&alerts).Error; err != nil {
+		return nil, err
+	}
+
+	pagination.Rows = alerts
+	return pagination, nil
+}
+
+func (f *fraudRepository) PersistAlert(alert *domain.FraudAlert) error {
+	if err := f.db.Save(alert).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// cardSpend card purchases that were not declined or failed
+func (f *fraudRepository) cardSpend(card string) *gorm.DB {
+	return f.db.Model(&domain.Transaction{}).
+		Where("card = ? AND type = ? AND status IN ?", card, domain.WithdrawalType,
+			[]domain.TransactionStatus{domain.PendingStatus, domain.SuccessStatus})
+}
+
+// CountCardTransactionsSince counts card purchases since a time, an empty channel counts all channels
+func (f *fraudRepository) CountCardTransactionsSince(card string, channel domain.TransactionChannel, since time.Time) (int64, error) {
+	var count int64
+	query := f.cardSpend(card).Where("created_at >= ?", since)
+
+	if channel != "" {
+		query = query.Where("UPPER(channel) = ?", channel)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetCardAverageAmount returns the average purchase amount of a card and the number of purchases it is based on
+func (f *fraudRepository) GetCardAverageAmount(card string) (float64, int64, error) {
+	var stats struct {
+		Average float64
+		Count   int64
+	}
+
+	if err := f.cardSpend(card).
+		Select("COALESCE(AVG(debit), 0) AS average, COUNT(*) AS count").
+		Scan(&stats).Error; err != nil {
+		return 0, 0, err
+	}
+	return stats.Average, stats.Count, nil
+}
+
+func (f *fraudRepository) CountCardMerchantCategory(card string, category string) (int64, error) {
+	var count int64
+	if err := f.cardSpend(card).Where("merchant_category = ?", category).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (f *fraudRepository) DeleteAll() error {
+	if err := f.db.Exec("DELETE FROM fraud_alerts").Error; err != nil {
+		return err
+	}
+	if err := f.db.Exec("DELETE FROM fraud_rules").Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (f *fraudRepository) WithTx(tx *gorm.DB) ports.IFraudRepository {
+	return NewFraudRepository(tx)
+}
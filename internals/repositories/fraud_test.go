@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"core_business/internals/core/domain"
+	"core_business/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestFraudCardHistory(t *testing.T) {
+	transactionRepository := NewTransactionRepository(DBConnection)
+	fraudRepository := NewFraudRepository(DBConnection)
+	card := (&utils.Faker{}).RandomUUID()
+	now := time.Now()
+
+	transactions := []domain.Transaction{
+		{Debit: 2000, Channel: "atm", MerchantCategory: "6011", Base: domain.Base{CreatedAt: now.Add(-5 * time.Minute)}},
+		{Debit: 3000, Channel: domain.AtmChannel, MerchantCategory: "6011", Base: domain.Base{CreatedAt: now.Add(-10 * time.Minute)}},
+		{Debit: 7000, Channel: domain.PosChannel, MerchantCategory: "5812", Base: domain.Base{CreatedAt: now.Add(-2 * time.Hour)}},
+		{Debit: 9000, Channel: domain.PosChannel, MerchantCategory: "5812", Status: domain.FailedStatus, Base: domain.Base{CreatedAt: now.Add(-time.Minute)}},
+	}
+
+	for i := range transactions {
+		transactions[i].Card = card
+		transactions[i].PartnerCardID = (&utils.Faker{}).RandomObjectID()
+		transactions[i].Note = "debited for transaction"
+		transactions[i].Entry = domain.DebitEntry
+		transactions[i].Type = domain.WithdrawalType
+		if transactions[i].Status == "" {
+			transactions[i].Status = domain.SuccessStatus
+		}
+
+		err := transactionRepository.Persist(&transactions[i])
+		require.NoError(t, err)
+	}
+
+	count, err := fraudRepository.CountCardTransactionsSince(card.String(), "", now.Add(-30*time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	count, err = fraudRepository.CountCardTransactionsSince(card.String(), domain.AtmChannel, now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	average, count, err := fraudRepository.GetCardAverageAmount(card.String())
+	require.NoError(t, err)
+	require.Equal(t, int64(3), count)
+	require.Equal(t, float64(4000), average)
+
+	count, err = fraudRepository.CountCardMerchantCategory(card.String(), "5812")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	count, err = fraudRepository.CountCardMerchantCategory(card.String(), "4111")
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
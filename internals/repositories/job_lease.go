@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type jobLeaseRepository struct {
+	db *gorm.DB
+}
+
+// NewJobLeaseRepository creates a new instance job lease repository
+func NewJobLeaseRepository(db *gorm.DB) ports.IJobLeaseRepository {
+	return &jobLeaseRepository{
+		db: db,
+	}
+}
+
+// Acquire takes the lease of the job until the given time once the previous lease expired, the first instance to
+// run a job creates its lease. Only one instance gets the lease when several acquire it at once
+func (j *jobLeaseRepository) Acquire(name string, holder string, at time.Time, until time.Time) error {
+	acquired := j.db.Model(&domain.JobLease{}).
+		Where("name = ? AND expires_at <= ?", name, at).
+		Updates(map[string]interface{}{"holder": holder, "expires_at": until})
+	if acquired.Error != nil {
+		return acquired.Error
+	}
+
+	if acquired.RowsAffected > 0 {
+		return nil
+	}
+
+	created := j.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&domain.JobLease{Name: name, Holder: holder, ExpiresAt: until})
+	if created.Error != nil {
+		return created.Error
+	}
+
+	if created.RowsAffected == 0 {
+		return domain.ErrJobLeaseHeld
+	}
+	return nil
+}
+
+func (j *jobLeaseRepository) DeleteAll() error {
+	if err := j.db.Exec("DELETE FROM job_leases").Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (j *jobLeaseRepository) WithTx(tx *gorm.DB) ports.IJobLeaseRepository {
+	return NewJobLeaseRepository(tx)
+}
@@ -0,0 +1,32 @@
+package repositories
+
+import (
+	"core_business/internals/core/domain"
+	"core_business/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestAcquireJobLease(t *testing.T) {
+	jobLeaseRepository := NewJobLeaseRepository(DBConnection)
+	name := "renewal-" + (&utils.Faker{}).RandomObjectID()
+	now := time.Now()
+
+	err := jobLeaseRepository.Acquire(name, "first", now, now.Add(time.Hour))
+	require.NoError(t, err)
+
+	// the lease is not handed to another instance, nor to the holder, before it expires
+	err = jobLeaseRepository.Acquire(name, "second", now.Add(time.Minute), now.Add(time.Hour))
+	require.ErrorIs(t, err, domain.ErrJobLeaseHeld)
+
+	err = jobLeaseRepository.Acquire(name, "first", now.Add(time.Minute), now.Add(time.Hour))
+	require.ErrorIs(t, err, domain.ErrJobLeaseHeld)
+
+	err = jobLeaseRepository.Acquire(name, "second", now.Add(time.Hour), now.Add(2*time.Hour))
+	require.NoError(t, err)
+
+	var lease domain.JobLease
+	require.NoError(t, DBConnection.Where("name = ?", name).First(&lease).Error)
+	require.Equal(t, "second", lease.Holder)
+}
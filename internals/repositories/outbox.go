@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"gorm.io/gorm"
+)
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepository creates a new instance outbox repository
+func NewOutboxRepository(db *gorm.DB) ports.IOutboxRepository {
+	return &outboxRepository{
+		db: db,
+	}
+}
+
+func (o *outboxRepository) GetUnpublished(limit int) ([]domain.OutboxEvent, error) {
+	var events []domain.OutboxEvent
+	if err := o.db.Where("published_at IS NULL").
+		Order("created_at").
+		Limit(limit).
+		Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (o *outboxRepository) Persist(event *domain.OutboxEvent) error {
+	if err := o.db.Save(event).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (o *outboxRepository) DeleteAll() error {
+	if err := o.db.Exec("DELETE FROM outbox_events").Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (o *outboxRepository) WithTx(tx *gorm.DB) ports.IOutboxRepository {
+	return NewOutboxRepository(tx)
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"gorm.io/gorm"
+)
+
+type receiptPolicyRepository struct {
+	db *gorm.DB
+}
+
+// NewReceiptPolicyRepository creates a new instance receipt policy repository
+func NewReceiptPolicyRepository(db *gorm.DB) ports.IReceiptPolicyRepository {
+	return &receiptPolicyRepository{
+		db: db,
+	}
+}
+
+func (r *receiptPolicyRepository) GetByID(id string) (*domain.ReceiptPolicy, error) {
+	var receiptPolicy domain.ReceiptPolicy
+	if err := r.db.Where("id = ?", id).First(&receiptPolicy).Error; err != nil {
+		return nil, err
+	}
+	return &receiptPolicy, nil
+}
+
+func (r *receiptPolicyRepository) GetByCompany(id string) (*domain.ReceiptPolicy, error) {
+	var receiptPolicy domain.ReceiptPolicy
+	if err := r.db.Where("company = ?", id).First(&receiptPolicy).Error; err != nil {
+		return nil, err
+	}
+	return &receiptPolicy, nil
+}
+
+func (r *receiptPolicyRepository) Persist(receiptPolicy *domain.ReceiptPolicy) error {
+	if receiptPolicy.ID.String() != "" {
+		if err := r.db.Save(receiptPolicy).Error; err != nil {
+			return err
+		}
+		return nil
+	}
+	if err := r.db.Create(&receiptPolicy).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *receiptPolicyRepository) Delete(id string) error {
+	if err := r.db.Where("id = ?", id).Delete(&domain.ReceiptPolicy{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *receiptPolicyRepository) DeleteAll() error {
+	if err := r.db.Exec("DELETE FROM receipt_policies").Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *receiptPolicyRepository) WithTx(tx *gorm.DB) ports.IReceiptPolicyRepository {
+	return NewReceiptPolicyRepository(tx)
+}
@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"core_business/internals/core/domain"
+	"core_business/pkg/utils"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func createRandomReceiptPolicy(t *testing.T) *domain.ReceiptPolicy {
+	receiptPolicyRepository := NewReceiptPolicyRepository(DBConnection)
+
+	args := &domain.ReceiptPolicy{
+		Company:            (&utils.Faker{}).RandomUUID(),
+		AmountThreshold:    5000,
+		ExemptCategories:   []string{"Fuel"},
+		GraceDays:          3,
+		AutoLockCard:       true,
+		MaxOverdueReceipts: 2,
+	}
+
+	err := receiptPolicyRepository.Persist(args)
+	require.NoError(t, err)
+
+	receiptPolicy, err := receiptPolicyRepository.GetByCompany(args.Company.String())
+	require.NoError(t, err)
+	require.NotEmpty(t, receiptPolicy)
+
+	require.Equal(t, args.ID, receiptPolicy.ID)
+	require.Equal(t, args.AmountThreshold, receiptPolicy.AmountThreshold)
+	require.Equal(t, args.ExemptCategories, receiptPolicy.ExemptCategories)
+	require.Equal(t, args.GraceDays, receiptPolicy.GraceDays)
+
+	return args
+}
+
+func TestGetReceiptPolicyByBadCompany(t *testing.T) {
+	receiptPolicyRepository := NewReceiptPolicyRepository(DBConnection)
+
+	receiptPolicy, err := receiptPolicyRepository.GetByCompany(uuid.NewV4().String())
+	require.Error(t, err)
+	require.Empty(t, receiptPolicy)
+}
+
+func TestCountOverdueReceipts(t *testing.T) {
+	policy := createRandomReceiptPolicy(t)
+	transactionRepository := NewTransactionRepository(DBConnection)
+	card := (&utils.Faker{}).RandomUUID()
+	customer := (&utils.Faker{}).RandomUUID()
+	voidedAt := time.Now()
+
+	transactions := []domain.Transaction{
+		{Debit: 10000, Type: domain.WithdrawalType},
+		{Debit: 10000, Type: domain.WithdrawalType, Receipt: "https://receipts/1.png"},
+		{Debit: 10000, Type: domain.WithdrawalType, ExpenseCategory: "Fuel"},
+		{Debit: 100, Type: domain.WithdrawalType},
+		{Debit: 10000, Type: domain.FeeType},
+		{Debit: 20000, Type: domain.WithdrawalType, ExpenseCategory: "Lodging"},
+		{Debit: 10000, Type: domain.WithdrawalType, VoidedAt: &voidedAt},
+	}
+
+	for i := range transactions {
+		transactions[i].Company = policy.Company
+		transactions[i].Card = card
+		transactions[i].Customer = customer
+		transactions[i].PartnerCardID = (&utils.Faker{}).RandomObjectID()
+		transactions[i].Note = "debited for transaction"
+		transactions[i].Status = domain.SuccessStatus
+		transactions[i].Entry = domain.DebitEntry
+		transactions[i].Channel = domain.WebChannel
+
+		err := transactionRepository.Persist(&transactions[i])
+		require.NoError(t, err)
+	}
+
+	count, err := transactionRepository.CountOverdueReceiptsByCardID(card.String(), policy, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	count, err = transactionRepository.CountOverdueReceiptsByCardID(card.String(), policy, time.Now().AddDate(0, 0, -policy.GraceDays))
+	require.NoError(t, err)
+	require.Equal(t, int64(0), count)
+
+	pagination, err := transactionRepository.GetMissingReceiptsByCustomerID(customer.String(), policy, &utils.Pagination{Limit: 5, Page: 1})
+	require.NoError(t, err)
+	require.Len(t, pagination.Rows, 2)
+	require.Equal(t, int64(2), pagination.TotalRows)
+}
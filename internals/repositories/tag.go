@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"core_business/pkg/utils"
+	"gorm.io/gorm"
+)
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRepository creates a new instance tag repository
+func NewTagRepository(db *gorm.DB) ports.ITagRepository {
+	return &tagRepository{
+		db: db,
+	}
+}
+
+func (t *tagRepository) GetByID(id string) (*domain.Tag, error) {
+	var tag domain.Tag
+	if err := t.db.Where("id = ?", id).First(&tag).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (t *tagRepository) GetByIDs(ids []string) ([]domain.Tag, error) {
+	var tags []domain.Tag
+	if err := t.db.Where("id IN ?", ids).Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (t *tagRepository) GetTagByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error) {
+	var tags []domain.Tag
+	query := t.db.Where("company = ?", id)
+	if err := query.Scopes(utils.Paginate(tags, pagination, query.Session(&gorm.Session{}))).
+		Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	pagination.Rows = tags
+	return pagination, nil
+}
+
+func (t *tagRepository) GetByName(company string, name string) (*domain.Tag, error) {
+	var tag domain.Tag
+	if err := t.db.Where("company = ? AND name = ?", company, name).First(&tag).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (t *tagRepository) Persist(tag *domain.Tag) error {
+	if tag.ID.String() != "" {
+		if err := t.db.Save(tag).Error; err != nil {
+			return err
+		}
+		return nil
+	}
+	if err := t.db.Create(&tag).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *tagRepository) Delete(id string) error {
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM transaction_tags WHERE tag_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&domain.Tag{}).Error
+	})
+}
+
+func (t *tagRepository) DeleteAll() error {
+	if err := t.db.Exec("DELETE FROM tags").Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *tagRepository) WithTx(tx *gorm.DB) ports.ITagRepository {
+	return NewTagRepository(tx)
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"core_business/internals/core/domain"
+	"core_business/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestFilterTransactionByTag(t *testing.T) {
+	tagRepository := NewTagRepository(DBConnection)
+	transactionRepository := NewTransactionRepository(DBConnection)
+	company := (&utils.Faker{}).RandomUUID()
+
+	tag := &domain.Tag{Company: company, Name: "Lagos offsite"}
+	err := tagRepository.Persist(tag)
+	require.NoError(t, err)
+
+	var transactions []domain.Transaction
+	for i := 0; i < 3; i++ {
+		transaction := domain.Transaction{
+			Company:       company,
+			PartnerCardID: (&utils.Faker{}).RandomObjectID(),
+			Debit:         1000,
+			Note:          "debited for transaction",
+			Status:        domain.SuccessStatus,
+			Entry:         domain.DebitEntry,
+			Channel:       domain.WebChannel,
+			Type:          domain.WithdrawalType,
+		}
+		err = transactionRepository.Persist(&transaction)
+		require.NoError(t, err)
+		transactions = append(transactions, transaction)
+	}
+
+	err = transactionRepository.ReplaceTags(&transactions[0], []domain.Tag{*tag})
+	require.NoError(t, err)
+
+	pagination, err := transactionRepository.GetTransactionByCompanyID(company.String(), &utils.Pagination{Limit: 5, Page: 1, Filter: tag.ID.String()})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), pagination.TotalRows)
+	rows := pagination.Rows.([]domain.Transaction)
+	require.Len(t, rows, 1)
+	require.Equal(t, transactions[0].ID, rows[0].ID)
+	require.Len(t, rows[0].Tags, 1)
+
+	err = tagRepository.Delete(tag.ID.String())
+	require.NoError(t, err)
+
+	pagination, err = transactionRepository.GetTransactionByCompanyID(company.String(), &utils.Pagination{Limit: 5, Page: 1, Filter: tag.ID.String()})
+	require.NoError(t, err)
+	require.Empty(t, pagination.Rows)
+}
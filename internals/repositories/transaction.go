@@ -6,6 +6,7 @@ import (
 	"core_business/pkg/utils"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+	"time"
 )
 
 type transactionRepository struct {
@@ -29,10 +30,35 @@ func (t *transactionRepository) GetByID(id string) (*domain.Transaction, error)
 	return &transaction, nil
 }
 
+// GetByIDForUpdate reads the transaction and locks its row until the caller transaction ends
+func (t *transactionRepository) GetByIDForUpdate(id string) (*domain.Transaction, error) {
+	var transaction domain.Transaction
+	if err := t.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&transaction).Error; err != nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
+
 func (t *transactionRepository) GetTransactionByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error) {
 	var transactions []domain.Transaction
-	if err := t.db.Scopes(utils.Paginate(transactions, pagination, t.db)).
-		Where("Company = ?", id).
+	query := t.db.Scopes(tagged(pagination.GetFilter())).Where("Company = ?", id)
+	if err := query.Scopes(utils.Paginate(transactions, pagination, query.Session(&gorm.Session{}))).
+		Preload("Tags").
+		Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+
+	pagination.Rows = transactions
+	return pagination, nil
+}
+
+func (t *transactionRepository) GetTransactionByCustomerID(id string, pagination *utils.Pagination) (*utils.Pagination, error) {
+	var transactions []domain.Transaction
+	query := t.db.Scopes(tagged(pagination.GetFilter())).Where("customer = ?", id)
+	if err := query.Scopes(utils.Paginate(transactions, pagination, query.Session(&gorm.Session{}))).
+		Preload("Tags").
 		Find(&transactions).Error; err != nil {
 		return nil, err
 	}
@@ -43,8 +69,22 @@ func (t *transactionRepository) GetTransactionByCompanyID(id string, pagination
 
 func (t *transactionRepository) GetTransactionByCardID(id string, pagination *utils.Pagination) (*utils.Pagination, error) {
 	var transactions []domain.Transaction
-	if err := t.db.Scopes(utils.Paginate(transactions, pagination, t.db)).
-		Where("Card = ?", id).
+	query := t.db.Scopes(tagged(pagination.GetFilter())).Where("Card = ?", id)
+	if err := query.Scopes(utils.Paginate(transactions, pagination, query.Session(&gorm.Session{}))).
+		Preload("Tags").
+		Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+
+	pagination.Rows = transactions
+	return pagination, nil
+}
+
+func (t *transactionRepository) GetMissingReceiptsByCustomerID(id string, policy *domain.ReceiptPolicy, pagination *utils.Pagination) (*utils.Pagination, error) {
+	var transactions []domain.Transaction
+	query := t.db.Scopes(missingReceipt(policy)).Where("customer = ?", id)
+
+	if err := query.Scopes(utils.Paginate(transactions, pagination, query.Session(&gorm.Session{}))).
 		Find(&transactions).Error; err != nil {
 		return nil, err
 	}
@@ -53,6 +93,44 @@ func (t *transactionRepository) GetTransactionByCardID(id string, pagination *ut
 	return pagination, nil
 }
 
+// GetMerchantChargesByCardID returns the successful purchases of the card at named merchants since the time, oldest first
+func (t *transactionRepository) GetMerchantChargesByCardID(id string, since time.Time) ([]domain.Transaction, error) {
+	var transactions []domain.Transaction
+	if err := t.db.Where("card = ? AND type = ? AND entry = ? AND status = ? AND merchant_name <> '' AND created_at >= ?",
+		id, domain.WithdrawalType, domain.DebitEntry, domain.SuccessStatus, since).
+		Order("created_at").
+		Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// GetForExport the successful transactions of a company created in the period, with the lines of split transactions
+func (t *transactionRepository) GetForExport(id string, from, to time.Time) ([]domain.Transaction, error) {
+	var transactions []domain.Transaction
+	if err := t.db.Where("company = ? AND status = ? AND created_at >= ? AND created_at < ?",
+		id, domain.SuccessStatus, from, to).
+		Preload("Splits").
+		Preload("Tags").
+		Preload("FeeDetails").
+		Order("created_at").
+		Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+func (t *transactionRepository) CountOverdueReceiptsByCardID(id string, policy *domain.ReceiptPolicy, before time.Time) (int64, error) {
+	var count int64
+	if err := t.db.Model(&domain.Transaction{}).
+		Scopes(missingReceipt(policy)).
+		Where("card = ? AND created_at < ?", id, before).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
 func (t *transactionRepository) GetBy(filter interface{}) ([]domain.Transaction, error) {
 	var transactions []domain.Transaction
 	if err := t.db.Model(&domain.Transaction{}).Find(&transactions, filter).Error; err != nil {
@@ -61,9 +139,117 @@ func (t *transactionRepository) GetBy(filter interface{}) ([]domain.Transaction,
 	return transactions, nil
 }
 
+func (t *transactionRepository) GetRefundsByParentID(ids []string) ([]domain.Transaction, error) {
+	var transactions []domain.Transaction
+	if err := t.db.Where("parent_id IN ? AND type = ? AND status = ?", ids, domain.RefundType, domain.SuccessStatus).
+		Order("created_at").
+		Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+func (t *transactionRepository) GetAmountChangesByReference(reference string) ([]domain.AmountChange, error) {
+	var changes []domain.AmountChange
+	if err := t.db.Where("reference_id = ?", reference).Find(&changes).Error; err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+func (t *transactionRepository) PersistAmountChange(change *domain.AmountChange) error {
+	if err := t.db.Create(change).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *transactionRepository) ReplaceSplits(id string, splits []domain.TransactionSplit) error {
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("transaction_id = ?", id).Delete(&domain.TransactionSplit{}).Error; err != nil {
+			return err
+		}
+
+		if len(splits) == 0 {
+			return nil
+		}
+		return tx.Create(&splits).Error
+	})
+}
+
+// ReplaceFeeDetails swaps the partner fee lines of a transaction, the settled webhook carries the final fees
+func (t *transactionRepository) ReplaceFeeDetails(id string, details []domain.PartnerFeeDetail) error {
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("transaction_id = ?", id).Delete(&domain.PartnerFeeDetail{}).Error; err != nil {
+			return err
+		}
+
+		if len(details) == 0 {
+			return nil
+		}
+		return tx.Create(&details).Error
+	})
+}
+
+// GetCategoryTotalsByCompanyID sums successful card spend per expense category, a split transaction counts its lines instead of itself
+func (t *transactionRepository) GetCategoryTotalsByCompanyID(id string) ([]domain.CategoryTotal, error) {
+	var totals []domain.CategoryTotal
+	spend := t.db.Model(&domain.Transaction{}).
+		Where("company = ? AND type = ? AND entry = ? AND status = ?", id, domain.WithdrawalType, domain.DebitEntry, domain.SuccessStatus).
+		Scopes(netSpend)
+
+	// a refund of a split transaction is shared between its lines in proportion to their amounts
+	splitLines := t.db.Table("transaction_splits").
+		Select("transaction_splits.category AS category, "+
+			"transaction_splits.amount * (spend.debit - spend.refunded) / spend.debit AS amount").
+		Joins("JOIN (?) spend ON spend.id = transaction_splits.transaction_id",
+			spend.Session(&gorm.Session{}).Select("id, debit, "+refundedSQL+" AS refunded", domain.RefundType, domain.SuccessStatus))
+
+	unsplit := spend.Session(&gorm.Session{}).
+		Select("expense_category AS category, debit - "+refundedSQL+" AS amount", domain.RefundType, domain.SuccessStatus).
+		Where("NOT EXISTS (SELECT 1 FROM transaction_splits WHERE transaction_splits.transaction_id = transactions.id)")
+
+	if err := t.db.Table("(? UNION ALL ?) lines", splitLines, unsplit).
+		Select("category, SUM(amount) AS amount, COUNT(*) AS count").
+		Group("category").
+		Order("amount DESC").
+		Scan(&totals).Error; err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
+
+func (t *transactionRepository) ReplaceTags(transaction *domain.Transaction, tags []domain.Tag) error {
+	if len(tags) == 0 {
+		return t.db.Model(transaction).Association("Tags").Clear()
+	}
+	return t.db.Model(transaction).Association("Tags").Replace(tags)
+}
+
+func (t *transactionRepository) GetCommentsByTransactionID(id string, pagination *utils.Pagination) (*utils.Pagination, error) {
+	var comments []domain.TransactionComment
+	query := t.db.Where("transaction_id = ?", id)
+	if err := query.Scopes(utils.Paginate(comments, pagination, query.Session(&gorm.Session{}))).
+		Find(&comments).Error; err != nil {
+		return nil, err
+	}
+	pagination.Rows = comments
+	return pagination, nil
+}
+
+func (t *transactionRepository) PersistComment(comment *domain.TransactionComment) error {
+	if err := t.db.Create(comment).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
 func (t *transactionRepository) Get(pagination *utils.Pagination) (*utils.Pagination, error) {
 	var transactions []domain.Transaction
-	if err := t.db.Scopes(utils.Paginate(transactions, pagination, t.db)).Find(&transactions).Error; err != nil {
+	query := t.db.Scopes(tagged(pagination.GetFilter()))
+	if err := query.Scopes(utils.Paginate(transactions, pagination, query.Session(&gorm.Session{}))).
+		Preload("Tags").
+		Find(&transactions).Error; err != nil {
 		return nil, err
 	}
 	pagination.Rows = transactions
@@ -97,6 +283,42 @@ func (t *transactionRepository) DeleteAll() error {
 	return nil
 }
 
+// missingReceipt scopes card purchases the receipt policy applies to that have no receipt attached
+func missingReceipt(policy *domain.ReceiptPolicy) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("type = ? AND entry = ? AND status IN ?", domain.WithdrawalType, domain.DebitEntry,
+			[]domain.TransactionStatus{domain.PendingStatus, domain.SuccessStatus}).
+			Where("(receipt IS NULL OR receipt = '') AND voided_at IS NULL").
+			Where("debit >= ?", policy.AmountThreshold)
+
+		if len(policy.ExemptCategories) > 0 {
+			db = db.Where("(expense_category IS NULL OR expense_category NOT IN ?)", policy.ExemptCategories)
+		}
+		return db
+	}
+}
+
+// refundedSQL sums the successful refunds of the purchase in the enclosing query, it takes the refund type and
+// success status as arguments
+const refundedSQL = "(SELECT COALESCE(SUM(refunds.debit), 0) FROM transactions refunds " +
+	"WHERE refunds.parent_id = CAST(transactions.id AS TEXT) AND refunds.type = ? AND refunds.status = ?)"
+
+// netSpend leaves out purchases that were voided or refunded in full
+func netSpend(db *gorm.DB) *gorm.DB {
+	return db.Where("voided_at IS NULL").
+		Where("debit > "+refundedSQL, domain.RefundType, domain.SuccessStatus)
+}
+
+// tagged limits transactions to those carrying the tag, an empty tag leaves the query untouched
+func tagged(tag string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if tag == "" {
+			return db
+		}
+		return db.Where("id IN (SELECT transaction_id FROM transaction_tags WHERE tag_id = ?)", tag)
+	}
+}
+
 func (t *transactionRepository) WithTx(tx *gorm.DB) ports.ITransactionRepository {
 	return NewTransactionRepository(tx)
 }
@@ -0,0 +1,243 @@
+package repositories
+
+import (
+	"core_business/internals/core/domain"
+	"core_business/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestGetRefundsByParentID(t *testing.T) {
+	transactionRepository := NewTransactionRepository(DBConnection)
+
+	purchase := &domain.Transaction{
+		Company:         (&utils.Faker{}).RandomUUID(),
+		PartnerCardID:   (&utils.Faker{}).RandomObjectID(),
+		Debit:           10000,
+		Note:            "debited for transaction",
+		Status:          domain.SuccessStatus,
+		Entry:           domain.DebitEntry,
+		Channel:         domain.WebChannel,
+		Type:            domain.WithdrawalType,
+		AuthorizationID: (&utils.Faker{}).RandomObjectID(),
+	}
+	err := transactionRepository.Persist(purchase)
+	require.NoError(t, err)
+
+	for _, status := range []domain.TransactionStatus{domain.SuccessStatus, domain.SuccessStatus, domain.FailedStatus} {
+		err = transactionRepository.Persist(&domain.Transaction{
+			Company:       purchase.Company,
+			PartnerCardID: purchase.PartnerCardID,
+			Debit:         2500,
+			Note:          "refunded by merchant",
+			Status:        status,
+			Entry:         domain.CreditEntry,
+			Channel:       domain.WebChannel,
+			Type:          domain.RefundType,
+			ParentID:      purchase.ID.String(),
+		})
+		require.NoError(t, err)
+	}
+
+	refunds, err := transactionRepository.GetRefundsByParentID([]string{purchase.ID.String()})
+	require.NoError(t, err)
+	require.Len(t, refunds, 2)
+	require.Equal(t, purchase.ID.String(), refunds[0].ParentID)
+}
+
+func TestAmountHistory(t *testing.T) {
+	transactionRepository := NewTransactionRepository(DBConnection)
+
+	transaction := &domain.Transaction{
+		Company:          (&utils.Faker{}).RandomUUID(),
+		PartnerCardID:    (&utils.Faker{}).RandomObjectID(),
+		Debit:            5000,
+		AuthorizedAmount: 5000,
+		Note:             "debited for transaction",
+		Status:           domain.PendingStatus,
+		Entry:            domain.DebitEntry,
+		Channel:          domain.PosChannel,
+		Type:             domain.WithdrawalType,
+	}
+	err := transactionRepository.Persist(transaction)
+	require.NoError(t, err)
+
+	reference := (&utils.Faker{}).RandomObjectID()
+	err = transactionRepository.PersistAmountChange(&domain.AmountChange{
+		Transaction:    transaction.ID,
+		Type:           domain.CaptureChange,
+		PreviousAmount: 5000,
+		Amount:         3200,
+		Difference:     -1800,
+		ReferenceID:    reference,
+	})
+	require.NoError(t, err)
+
+	changes, err := transactionRepository.GetAmountChangesByReference(reference)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+
+	transaction, err = transactionRepository.GetByID(transaction.ID.String())
+	require.NoError(t, err)
+	require.Len(t, transaction.AmountHistory, 1)
+	require.Equal(t, float64(-1800), transaction.AmountHistory[0].Difference)
+}
+
+func TestMerchantAndFeeDetails(t *testing.T) {
+	transactionRepository := NewTransactionRepository(DBConnection)
+
+	transaction := &domain.Transaction{
+		Company:          (&utils.Faker{}).RandomUUID(),
+		PartnerCardID:    (&utils.Faker{}).RandomObjectID(),
+		Debit:            46500,
+		Currency:         "NGN",
+		MerchantAmount:   30,
+		MerchantCurrency: "USD",
+		Vat:              75,
+		PartnerFee:       1000,
+		FeeDetails: []domain.PartnerFeeDetail{
+			{Contract: "cross-border", Currency: "NGN", Amount: 1000, Description: "Cross border fee"},
+		},
+		Note:    "debited for transaction",
+		Status:  domain.PendingStatus,
+		Entry:   domain.DebitEntry,
+		Channel: domain.WebChannel,
+		Type:    domain.WithdrawalType,
+	}
+	err := transactionRepository.Persist(transaction)
+	require.NoError(t, err)
+
+	transaction, err = transactionRepository.GetByID(transaction.ID.String())
+	require.NoError(t, err)
+	require.Equal(t, "USD", transaction.MerchantCurrency)
+	require.Equal(t, float64(30), transaction.MerchantAmount)
+	require.Equal(t, float64(75), transaction.Vat)
+	require.Len(t, transaction.FeeDetails, 1)
+
+	err = transactionRepository.ReplaceFeeDetails(transaction.ID.String(), []domain.PartnerFeeDetail{
+		{Transaction: transaction.ID, Contract: "cross-border", Currency: "NGN", Amount: 800, Description: "Cross border fee"},
+		{Transaction: transaction.ID, Contract: "fx", Currency: "NGN", Amount: 200, Description: "FX markup"},
+	})
+	require.NoError(t, err)
+
+	transaction, err = transactionRepository.GetByID(transaction.ID.String())
+	require.NoError(t, err)
+	require.Len(t, transaction.FeeDetails, 2)
+}
+
+func TestGetCategoryTotals(t *testing.T) {
+	transactionRepository := NewTransactionRepository(DBConnection)
+	company := (&utils.Faker{}).RandomUUID()
+
+	transactions := []domain.Transaction{
+		{Debit: 30000, ExpenseCategory: "Lodging"},
+		{Debit: 5000, ExpenseCategory: "Meals"},
+		{Debit: 2000, ExpenseCategory: "Meals", Status: domain.FailedStatus},
+	}
+
+	for i := range transactions {
+		transactions[i].Company = company
+		transactions[i].PartnerCardID = (&utils.Faker{}).RandomObjectID()
+		transactions[i].Note = "debited for transaction"
+		transactions[i].Entry = domain.DebitEntry
+		transactions[i].Channel = domain.PosChannel
+		transactions[i].Type = domain.WithdrawalType
+		if transactions[i].Status == "" {
+			transactions[i].Status = domain.SuccessStatus
+		}
+
+		err := transactionRepository.Persist(&transactions[i])
+		require.NoError(t, err)
+	}
+
+	err := transactionRepository.ReplaceSplits(transactions[0].ID.String(), []domain.TransactionSplit{
+		{Transaction: transactions[0].ID, Amount: 24000, Category: "Lodging"},
+		{Transaction: transactions[0].ID, Amount: 6000, Category: "Meals", Memo: "room service"},
+	})
+	require.NoError(t, err)
+
+	totals, err := transactionRepository.GetCategoryTotalsByCompanyID(company.String())
+	require.NoError(t, err)
+	require.Len(t, totals, 2)
+	require.Equal(t, domain.CategoryTotal{Category: "Lodging", Amount: 24000, Count: 1}, totals[0])
+	require.Equal(t, domain.CategoryTotal{Category: "Meals", Amount: 11000, Count: 2}, totals[1])
+
+	// voided purchases are left out and refunds are netted out, shared between the lines of a split
+	voidedAt := time.Now()
+	voided := &domain.Transaction{Company: company, Debit: 7000, ExpenseCategory: "Meals", VoidedAt: &voidedAt,
+		PartnerCardID: (&utils.Faker{}).RandomObjectID(), Note: "debited for transaction", Entry: domain.DebitEntry,
+		Channel: domain.PosChannel, Type: domain.WithdrawalType, Status: domain.SuccessStatus}
+	require.NoError(t, transactionRepository.Persist(voided))
+
+	for _, refund := range []domain.Transaction{
+		{Debit: 3000, ParentID: transactions[0].ID.String(), Status: domain.SuccessStatus},
+		{Debit: 5000, ParentID: transactions[1].ID.String(), Status: domain.SuccessStatus},
+		{Debit: 1000, ParentID: transactions[0].ID.String(), Status: domain.FailedStatus},
+	} {
+		refund.Company = company
+		refund.PartnerCardID = (&utils.Faker{}).RandomObjectID()
+		refund.Note = "credited for refund"
+		refund.Entry = domain.CreditEntry
+		refund.Channel = domain.PosChannel
+		refund.Type = domain.RefundType
+		require.NoError(t, transactionRepository.Persist(&refund))
+	}
+
+	totals, err = transactionRepository.GetCategoryTotalsByCompanyID(company.String())
+	require.NoError(t, err)
+	require.Len(t, totals, 2)
+	require.Equal(t, domain.CategoryTotal{Category: "Lodging", Amount: 21600, Count: 1}, totals[0])
+	require.Equal(t, domain.CategoryTotal{Category: "Meals", Amount: 5400, Count: 1}, totals[1])
+
+	err = transactionRepository.ReplaceSplits(transactions[0].ID.String(), nil)
+	require.NoError(t, err)
+
+	transaction, err := transactionRepository.GetByID(transactions[0].ID.String())
+	require.NoError(t, err)
+	require.Empty(t, transaction.Splits)
+}
+
+func TestGetForExport(t *testing.T) {
+	transactionRepository := NewTransactionRepository(DBConnection)
+	company := (&utils.Faker{}).RandomUUID()
+
+	transactions := []domain.Transaction{
+		{Debit: 30000, Status: domain.SuccessStatus},
+		{Debit: 2000, Status: domain.FailedStatus},
+	}
+
+	for i := range transactions {
+		transactions[i].Company = company
+		transactions[i].PartnerCardID = (&utils.Faker{}).RandomObjectID()
+		transactions[i].Note = "debited for transaction"
+		transactions[i].Entry = domain.DebitEntry
+		transactions[i].Channel = domain.PosChannel
+		transactions[i].Type = domain.WithdrawalType
+
+		err := transactionRepository.Persist(&transactions[i])
+		require.NoError(t, err)
+	}
+
+	err := transactionRepository.ReplaceSplits(transactions[0].ID.String(), []domain.TransactionSplit{
+		{Transaction: transactions[0].ID, Amount: 24000, Category: "Lodging"},
+		{Transaction: transactions[0].ID, Amount: 6000, Category: "Meals"},
+	})
+	require.NoError(t, err)
+
+	err = transactionRepository.ReplaceFeeDetails(transactions[0].ID.String(), []domain.PartnerFeeDetail{
+		{Transaction: transactions[0].ID, Amount: 150, Currency: "NGN", Description: "cross border fee"},
+	})
+	require.NoError(t, err)
+
+	exported, err := transactionRepository.GetForExport(company.String(), time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, exported, 1)
+	require.Equal(t, transactions[0].ID, exported[0].ID)
+	require.Len(t, exported[0].Splits, 2)
+	require.Len(t, exported[0].FeeDetails, 1)
+
+	exported, err = transactionRepository.GetForExport(company.String(), time.Now().Add(time.Hour), time.Now().Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, exported)
+}
@@ -0,0 +1,120 @@
+package repositories
+
+import (
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"core_business/pkg/utils"
+	"gorm.io/gorm"
+	"time"
+)
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository creates a new instance webhook repository
+func NewWebhookRepository(db *gorm.DB) ports.IWebhookRepository {
+	return &webhookRepository{
+		db: db,
+	}
+}
+
+func (w *webhookRepository) GetByID(id string) (*domain.WebhookSubscription, error) {
+	var subscription domain.WebhookSubscription
+	if err := w.db.Where("id = ?", id).First(&subscription).Error; err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (w *webhookRepository) GetWebhookByCompanyID(id string, pagination *utils.Pagination) (*utils.Pagination, error) {
+	var subscriptions []domain.WebhookSubscription
+	query := w.db.Where("company = ?", id)
+	if err := query.Scopes(utils.Paginate(subscriptions, pagination, query.Session(&gorm.Session{}))).
+		Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+	pagination.Rows = subscriptions
+	return pagination, nil
+}
+
+func (w *webhookRepository) GetActiveByCompany(id string) ([]domain.WebhookSubscription, error) {
+	var subscriptions []domain.WebhookSubscription
+	if err := w.db.Where("company = ? AND active = ?", id, true).Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+func (w *webhookRepository) Persist(subscription *domain.WebhookSubscription) error {
+	if subscription.ID.String() != "" {
+		if err := w.db.Save(subscription).Error; err != nil {
+			return err
+		}
+		return nil
+	}
+	if err := w.db.Create(&subscription).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (w *webhookRepository) Delete(id string) error {
+	if err := w.db.Where("id = ?", id).Delete(&domain.WebhookSubscription{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (w *webhookRepository) DeleteAll() error {
+	if err := w.db.Exec("DELETE FROM webhook_subscriptions").Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (w *webhookRepository) GetDeliveryByID(id string) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	if err := w.db.Where("id = ?", id).First(&delivery).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (w *webhookRepository) GetDeliveriesBySubscriptionID(id string, pagination *utils.Pagination) (*utils.Pagination, error) {
+	var deliveries []domain.WebhookDelivery
+	query := w.db.Where("subscription = ?", id)
+
+	if filter := pagination.GetFilter(); filter != "" {
+		query = query.Where("status = ?", filter)
+	}
+
+	if err := query.Scopes(utils.Paginate(deliveries, pagination, query.Session(&gorm.Session{}))).
+		Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	pagination.Rows = deliveries
+	return pagination, nil
+}
+
+func (w *webhookRepository) GetDueDeliveries(before time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	if err := w.db.Where("status = ? AND next_attempt_at <= ?", domain.DeliveryPending, before).
+		Order("next_attempt_at").
+		Limit(limit).
+		Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (w *webhookRepository) PersistDelivery(delivery *domain.WebhookDelivery) error {
+	if err := w.db.Save(delivery).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (w *webhookRepository) WithTx(tx *gorm.DB) ports.IWebhookRepository {
+	return NewWebhookRepository(tx)
+}
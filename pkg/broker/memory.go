@@ -0,0 +1,57 @@
+package broker
+
+import (
+	"sync"
+)
+
+// Message a message published to a broker
+type Message struct {
+	Topic     string
+	Key       string
+	MessageID string
+	Payload   []byte
+}
+
+// MemoryBroker keeps published messages in memory, used in tests and local development
+type MemoryBroker struct {
+	mu       sync.Mutex
+	messages []Message
+	err      error
+}
+
+// NewMemoryBroker creates an empty in-memory broker
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{}
+}
+
+// Publish stores the message, or returns the error set with FailWith
+func (m *MemoryBroker) Publish(topic string, key string, messageID string, payload []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err != nil {
+		return m.err
+	}
+
+	m.messages = append(m.messages, Message{Topic: topic, Key: key, MessageID: messageID, Payload: payload})
+	return nil
+}
+
+// FailWith makes every following publish fail with err until called with nil
+func (m *MemoryBroker) FailWith(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.err = err
+}
+
+// Messages returns the published messages in order
+func (m *MemoryBroker) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message{}, m.messages...)
+}
+
+// Close does nothing for the in-memory broker
+func (m *MemoryBroker) Close() error {
+	return nil
+}
@@ -0,0 +1,125 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"sync"
+	"time"
+)
+
+// RabbitMQBroker publishes messages to a durable topic exchange, routed by topic. A dropped connection is dialed
+// again on the next publish
+type RabbitMQBroker struct {
+	mu         sync.Mutex
+	url        string
+	exchange   string
+	connection *amqp.Connection
+	channel    *amqp.Channel
+}
+
+// NewRabbitMQBroker connects to RabbitMQ and declares the exchange, publishes wait for the broker confirmation
+func NewRabbitMQBroker(url string, exchange string) (*RabbitMQBroker, error) {
+	r := &RabbitMQBroker{
+		url:      url,
+		exchange: exchange,
+	}
+
+	if err := r.connect(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// connect dials RabbitMQ and opens a confirming channel, the connection is dropped when RabbitMQ closes it so the
+// next publish dials again. The caller holds the lock, except in NewRabbitMQBroker
+func (r *RabbitMQBroker) connect() error {
+	connection, err := amqp.Dial(r.url)
+	if err != nil {
+		return err
+	}
+
+	channel, err := connection.Channel()
+	if err != nil {
+		connection.Close()
+		return err
+	}
+
+	if err = channel.ExchangeDeclare(r.exchange, amqp.ExchangeTopic, true, false, false, false, nil); err != nil {
+		connection.Close()
+		return err
+	}
+
+	if err = channel.Confirm(false); err != nil {
+		connection.Close()
+		return err
+	}
+
+	r.connection = connection
+	r.channel = channel
+
+	closed := connection.NotifyClose(make(chan *amqp.Error, 1))
+	go func() {
+		<-closed
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if r.connection == connection {
+			r.connection = nil
+			r.channel = nil
+		}
+	}()
+	return nil
+}
+
+// Publish sends a persistent message and waits until RabbitMQ acknowledges it, consumers dedupe on the message id
+func (r *RabbitMQBroker) Publish(topic string, key string, messageID string, payload []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.channel == nil || r.channel.IsClosed() {
+		if err := r.connect(); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	confirmation, err := r.channel.PublishWithDeferredConfirmWithContext(ctx, r.exchange, topic, false, false, amqp.Publishing{
+		ContentType:   "application/json",
+		DeliveryMode:  amqp.Persistent,
+		MessageId:     messageID,
+		CorrelationId: key,
+		Timestamp:     time.Now(),
+		Body:          payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !acked {
+		return errors.New("message was not acknowledged by rabbitmq")
+	}
+	return nil
+}
+
+// Close closes the channel and the connection
+func (r *RabbitMQBroker) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.connection == nil {
+		return nil
+	}
+
+	connection := r.connection
+	r.connection = nil
+	r.channel = nil
+	return connection.Close()
+}
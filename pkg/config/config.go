@@ -5,18 +5,21 @@ type Env string
 
 // Config is the configuration struct
 type Config struct {
-	Port          *string `env:"PORT"`
-	JWTSecret     string  `env:"JWT_SECRET"`
-	DatabaseURL   string  `env:"DATABASE_URL"`
-	RedisURL      string  `env:"REDIS_URL"`
-	Env           string  `env:"ENV"`
-	ElasticURL    string  `env:"ELASTIC_URL"`
-	OkraBaseURL   string  `env:"OKRA_BASE_URL"`
-	OkraSecret    string  `env:"OKRA_SECRET"`
-	EveaAPIKey    string  `env:"API_KEY"`
-	FundingSource string  `env:"FUNDING_SOURCE"`
-	SudoAPIKey    string  `env:"SUDO_API_KEY"`
-	SudoBaseURL   string  `env:"SUDO_BASE_URL"`
+	Port             *string `env:"PORT"`
+	JWTSecret        string  `env:"JWT_SECRET"`
+	DatabaseURL      string  `env:"DATABASE_URL"`
+	RedisURL         string  `env:"REDIS_URL"`
+	Env              string  `env:"ENV"`
+	ElasticURL       string  `env:"ELASTIC_URL"`
+	OkraBaseURL      string  `env:"OKRA_BASE_URL"`
+	OkraSecret       string  `env:"OKRA_SECRET"`
+	EveaAPIKey       string  `env:"API_KEY"`
+	FundingSource    string  `env:"FUNDING_SOURCE"`
+	SudoAPIKey       string  `env:"SUDO_API_KEY"`
+	SudoBaseURL      string  `env:"SUDO_BASE_URL"`
+	RabbitMQURL      *string `env:"RABBITMQ_URL"`
+	CardIssuer       *string `env:"CARD_ISSUER"`
+	ExpiryNoticeDays int     `env:"CARD_EXPIRY_NOTICE_DAYS"` // defaults to 30
 }
 
 // GetEnv returns the current environment
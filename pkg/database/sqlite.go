@@ -45,5 +45,30 @@ func (d *sqliteDatastore) MigrateAll(db *gorm.DB) error {
 		&domain.Fee{},
 		&domain.CreditIncrease{},
 		&domain.PAN{},
+		&domain.ReceiptPolicy{},
+		&domain.AmountChange{},
+		&domain.TransactionSplit{},
+		&domain.Tag{},
+		&domain.TransactionComment{},
+		&domain.WebhookSubscription{},
+		&domain.WebhookDelivery{},
+		&domain.Dispute{},
+		&domain.DisputeEvidence{},
+		&domain.OutboxEvent{},
+		&domain.PartnerFeeDetail{},
+		&domain.FraudRule{},
+		&domain.FraudAlert{},
+		&domain.AccountingPeriod{},
+		&domain.PeriodAudit{},
+		&domain.CardStatusChange{},
+		&domain.CardBatch{},
+		&domain.CardBatchRow{},
+		&domain.CardRevealToken{},
+		&domain.CardReveal{},
+		&domain.CardRecurringMerchant{},
+		&domain.CardFulfillment{},
+		&domain.CardFulfillmentEvent{},
+		&domain.CardPolicy{},
+		&domain.JobLease{},
 	)
 }
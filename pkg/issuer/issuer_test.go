@@ -0,0 +1,83 @@
+package issuer
+
+import (
+	"context"
+	"core_business/internals/core/domain"
+	"core_business/pkg/sudo"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestMemoryCardLifecycle(t *testing.T) {
+	memory := NewMemory()
+
+	customer, err := memory.CreateCustomer(context.Background(), domain.IssuerCustomer{FirstName: "Ada", LastName: "Obi", CompanyName: "Evea"})
+	require.NoError(t, err)
+	require.NotEmpty(t, customer.PartnerID)
+
+	issued, err := memory.IssueCard(context.Background(), domain.IssueCardRequest{
+		PartnerCustomerID: customer.PartnerID,
+		Type:              "virtual",
+		Brand:             "Verve",
+		Currency:          "NGN",
+		SpendingControls:  domain.SpendingControls{SpendingLimits: domain.SpendingLimits{Amount: 50000, Interval: "daily"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, string(domain.CardActive), issued.Status)
+	require.Len(t, issued.MaskedPan, 16)
+
+	card := &domain.Card{PartnerCardID: issued.PartnerCardID, Partner: memory.Name()}
+
+	secrets, err := memory.RevealCard(context.Background(), card)
+	require.NoError(t, err)
+	require.Len(t, secrets.Number, 16)
+	require.Len(t, secrets.CVV, 3)
+	require.Equal(t, issued.MaskedPan[:6], secrets.Number[:6])
+	require.Equal(t, issued.MaskedPan[12:], secrets.Number[12:])
+	require.Equal(t, issued.ExpiryYear, secrets.ExpiryYear)
+
+	require.NoError(t, memory.Freeze(context.Background(), card, true))
+	fetched, err := memory.FetchCard(context.Background(), card)
+	require.NoError(t, err)
+	require.Equal(t, string(domain.CardInactive), fetched.Status)
+
+	card.SpendingControls.SpendingLimits = domain.SpendingLimits{Amount: 1000, Interval: "weekly"}
+	require.NoError(t, memory.UpdateControls(context.Background(), card))
+	fetched, err = memory.FetchCard(context.Background(), card)
+	require.NoError(t, err)
+	require.Equal(t, 1000, fetched.SpendingControls.SpendingLimits.Amount)
+
+	require.Error(t, memory.ChangePIN(context.Background(), card, "0000", "4321"))
+	require.NoError(t, memory.ChangePIN(context.Background(), card, "1234", "4321"))
+
+	require.NoError(t, memory.Cancel(context.Background(), card, "lost"))
+	require.Error(t, memory.Freeze(context.Background(), card, false))
+
+	_, err = memory.IssueCard(context.Background(), domain.IssueCardRequest{PartnerCustomerID: "unknown"})
+	require.Error(t, err)
+
+	memory.FailWith(domain.ErrIssuerUnavailable)
+	_, err = memory.FetchCard(context.Background(), card)
+	require.True(t, errors.Is(err, domain.ErrIssuerUnavailable))
+}
+
+func TestRegistrySelectsIssuer(t *testing.T) {
+	memory := NewMemory()
+	registry := NewRegistry(MemoryName, NewSudo(sudo.NewClient("http://localhost", "key", 0), "funding", false), memory)
+
+	issuer, err := registry.ForCompany(&domain.Company{})
+	require.NoError(t, err)
+	require.Equal(t, MemoryName, issuer.Name())
+
+	issuer, err = registry.ForCompany(&domain.Company{CardIssuer: SudoName})
+	require.NoError(t, err)
+	require.Equal(t, SudoName, issuer.Name())
+
+	issuer, err = registry.ForCard(&domain.Card{Partner: SudoName})
+	require.NoError(t, err)
+	require.Equal(t, SudoName, issuer.Name())
+
+	_, err = registry.ForCard(&domain.Card{Partner: "another"})
+	require.True(t, errors.Is(err, domain.ErrUnknownIssuer))
+}
@@ -0,0 +1,214 @@
+package issuer
+
+import (
+	"context"
+	"core_business/internals/core/domain"
+	"errors"
+	"fmt"
+	"github.com/satori/go.uuid"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryName name of the in-memory issuer
+const MemoryName = "memory"
+
+// Memory issues cards in memory, used in tests and to run card flows offline
+type Memory struct {
+	mu        sync.Mutex
+	customers map[string]domain.IssuerCustomer
+	cards     map[string]domain.IssuedCard
+	pins      map[string]string
+	secrets   map[string]domain.CardSecrets
+	err       error
+}
+
+// NewMemory creates an in-memory issuer without customers or cards
+func NewMemory() *Memory {
+	return &Memory{
+		customers: map[string]domain.IssuerCustomer{},
+		cards:     map[string]domain.IssuedCard{},
+		pins:      map[string]string{},
+		secrets:   map[string]domain.CardSecrets{},
+	}
+}
+
+// Name returns the partner name stored on the cards
+func (m *Memory) Name() string {
+	return MemoryName
+}
+
+// CreateCustomer stores the customer under a new partner id
+func (m *Memory) CreateCustomer(ctx context.Context, customer domain.IssuerCustomer) (*domain.IssuerCustomer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	customer.PartnerID = uuid.NewV4().String()
+	m.customers[customer.PartnerID] = customer
+	return &customer, nil
+}
+
+// IssueCard stores a new card for a customer created with CreateCustomer
+func (m *Memory) IssueCard(ctx context.Context, request domain.IssueCardRequest) (*domain.IssuedCard, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	if _, ok := m.customers[request.PartnerCustomerID]; !ok {
+		return nil, errors.New("customer not found on issuer")
+	}
+
+	status := request.Status
+	if status == "" {
+		status = string(domain.CardActive)
+	}
+
+	number := request.Number
+	if number == "" {
+		number = fmt.Sprintf("506321%010d", rand.Int63n(1e10))
+	}
+
+	id := uuid.NewV4().String()
+	expiry := time.Now().AddDate(3, 0, 0)
+	card := domain.IssuedCard{
+		PartnerCardID:    id,
+		Type:             request.Type,
+		Brand:            request.Brand,
+		Currency:         request.Currency,
+		Status:           status,
+		MaskedPan:        maskPan(number),
+		ExpiryMonth:      fmt.Sprintf("%02d", int(expiry.Month())),
+		ExpiryYear:       fmt.Sprintf("%d", expiry.Year()),
+		SpendingControls: request.SpendingControls,
+	}
+
+	m.cards[id] = card
+	m.pins[id] = "1234"
+	m.secrets[id] = domain.CardSecrets{
+		Number:      number,
+		CVV:         fmt.Sprintf("%03d", rand.Intn(1000)),
+		ExpiryMonth: card.ExpiryMonth,
+		ExpiryYear:  card.ExpiryYear,
+	}
+	return &card, nil
+}
+
+// UpdateControls replaces the spending controls of the card
+func (m *Memory) UpdateControls(ctx context.Context, card *domain.Card) error {
+	return m.change(card.PartnerCardID, func(issued *domain.IssuedCard) error {
+		issued.SpendingControls = card.SpendingControls
+		return nil
+	})
+}
+
+// Freeze makes the card inactive, or active again
+func (m *Memory) Freeze(ctx context.Context, card *domain.Card, frozen bool) error {
+	return m.change(card.PartnerCardID, func(issued *domain.IssuedCard) error {
+		if issued.Status == string(domain.CardCanceled) {
+			return errors.New("card is canceled on issuer")
+		}
+
+		issued.Status = string(domain.CardActive)
+		if frozen {
+			issued.Status = string(domain.CardInactive)
+		}
+		return nil
+	})
+}
+
+// Cancel cancels the card for good
+func (m *Memory) Cancel(ctx context.Context, card *domain.Card, reason string) error {
+	return m.change(card.PartnerCardID, func(issued *domain.IssuedCard) error {
+		issued.Status = string(domain.CardCanceled)
+		return nil
+	})
+}
+
+// ChangePIN changes the PIN of the card when the old one matches, new cards have 1234
+func (m *Memory) ChangePIN(ctx context.Context, card *domain.Card, oldPin string, newPin string) error {
+	return m.change(card.PartnerCardID, func(issued *domain.IssuedCard) error {
+		if m.pins[issued.PartnerCardID] != oldPin {
+			return errors.New("invalid card pin")
+		}
+
+		m.pins[issued.PartnerCardID] = newPin
+		return nil
+	})
+}
+
+// FetchCard returns the card as the issuer holds it
+func (m *Memory) FetchCard(ctx context.Context, card *domain.Card) (*domain.IssuedCard, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	issued, ok := m.cards[card.PartnerCardID]
+	if !ok {
+		return nil, errors.New("card not found on issuer")
+	}
+	return &issued, nil
+}
+
+// RevealCard returns the number, CVV and expiry the card was issued with
+func (m *Memory) RevealCard(ctx context.Context, card *domain.Card) (*domain.CardSecrets, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	secrets, ok := m.secrets[card.PartnerCardID]
+	if !ok {
+		return nil, errors.New("card not found on issuer")
+	}
+	return &secrets, nil
+}
+
+// FailWith makes every following call fail with err until called with nil
+func (m *Memory) FailWith(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.err = err
+}
+
+func (m *Memory) change(partnerCardID string, apply func(issued *domain.IssuedCard) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err != nil {
+		return m.err
+	}
+
+	issued, ok := m.cards[partnerCardID]
+	if !ok {
+		return errors.New("card not found on issuer")
+	}
+
+	if err := apply(&issued); err != nil {
+		return err
+	}
+
+	m.cards[partnerCardID] = issued
+	return nil
+}
+
+// maskPan keeps the first six and the last four digits of a PAN
+func maskPan(number string) string {
+	if len(number) < 10 {
+		return number
+	}
+	return number[:6] + strings.Repeat("*", len(number)-10) + number[len(number)-4:]
+}
@@ -0,0 +1,44 @@
+package issuer
+
+import (
+	"core_business/internals/core/domain"
+	"core_business/internals/core/ports"
+	"fmt"
+)
+
+// Registry finds the issuer of a company or a card among the configured issuers
+type Registry struct {
+	issuers  map[string]ports.ICardIssuer
+	fallback string
+}
+
+// NewRegistry creates a registry of the issuers, companies and cards without an issuer use fallback
+func NewRegistry(fallback string, issuers ...ports.ICardIssuer) *Registry {
+	registry := &Registry{issuers: map[string]ports.ICardIssuer{}, fallback: fallback}
+	for _, issuer := range issuers {
+		registry.issuers[issuer.Name()] = issuer
+	}
+	return registry
+}
+
+// ForCompany returns the issuer new cards of the company are issued with
+func (r *Registry) ForCompany(company *domain.Company) (ports.ICardIssuer, error) {
+	return r.get(company.CardIssuer)
+}
+
+// ForCard returns the issuer the card was issued with
+func (r *Registry) ForCard(card *domain.Card) (ports.ICardIssuer, error) {
+	return r.get(card.Partner)
+}
+
+func (r *Registry) get(name string) (ports.ICardIssuer, error) {
+	if name == "" {
+		name = r.fallback
+	}
+
+	issuer, ok := r.issuers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", domain.ErrUnknownIssuer, name)
+	}
+	return issuer, nil
+}
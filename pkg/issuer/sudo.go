@@ -0,0 +1,233 @@
+package issuer
+
+import (
+	"context"
+	"core_business/internals/common"
+	"core_business/internals/core/domain"
+	"core_business/pkg/sudo"
+	"fmt"
+	"strings"
+)
+
+// SudoName name of the Sudo issuer, stored as the partner of its cards
+const SudoName = "sudo"
+
+// Sudo issues cards through the Sudo Africa API
+type Sudo struct {
+	client        *sudo.Client
+	fundingSource string
+	simulator     bool
+}
+
+// NewSudo creates the Sudo issuer, with simulator set physical cards without a PAN get one from the Sudo simulator
+func NewSudo(client *sudo.Client, fundingSource string, simulator bool) *Sudo {
+	return &Sudo{
+		client:        client,
+		fundingSource: fundingSource,
+		simulator:     simulator,
+	}
+}
+
+// Name returns the partner name stored on the cards
+func (s *Sudo) Name() string {
+	return SudoName
+}
+
+// CreateCustomer creates the cardholder on Sudo
+func (s *Sudo) CreateCustomer(ctx context.Context, customer domain.IssuerCustomer) (*domain.IssuerCustomer, error) {
+	created, err := s.client.CreateCustomer(ctx, sudo.CreateCustomerRequest{
+		Type:         common.CustomerType,
+		Name:         fmt.Sprintf("%v %v", customer.FirstName, customer.LastName),
+		Status:       customer.Status,
+		PhoneNumber:  customer.Phone,
+		EmailAddress: customer.Email,
+		Individual: sudo.Individual{
+			FirstName: customer.FirstName,
+			LastName:  customer.LastName,
+		},
+		Company: sudo.Company{
+			Name: customer.CompanyName,
+		},
+		BillingAddress: sudo.BillingAddress{
+			Line1:      customer.Address.Line1,
+			City:       customer.Address.City,
+			State:      customer.Address.State,
+			PostalCode: customer.Address.PostalCode,
+			Country:    customer.Address.Country,
+		},
+	})
+	if err != nil {
+		return nil, issuerError(err)
+	}
+
+	customer.PartnerID = created.ID
+	customer.Address = domain.IssuerAddress{
+		Line1:      created.BillingAddress.Line1,
+		City:       created.BillingAddress.City,
+		State:      created.BillingAddress.State,
+		PostalCode: created.BillingAddress.PostalCode,
+		Country:    created.BillingAddress.Country,
+	}
+	return &customer, nil
+}
+
+// IssueCard creates a card for a customer created with CreateCustomer
+func (s *Sudo) IssueCard(ctx context.Context, request domain.IssueCardRequest) (*domain.IssuedCard, error) {
+	number := request.Number
+	if number == "" && strings.ToLower(request.Type) == "physical" && s.simulator {
+		pan, err := s.client.GeneratePAN(ctx)
+		if err != nil {
+			return nil, issuerError(err)
+		}
+		number = pan.Number
+	}
+
+	card, err := s.client.CreateCard(ctx, sudo.CreateCardRequest{
+		CustomerID:       request.PartnerCustomerID,
+		FundingSourceID:  s.fundingSource,
+		Type:             request.Type,
+		Brand:            request.Brand,
+		Currency:         request.Currency,
+		Status:           request.Status,
+		Number:           number,
+		SpendingControls: sudoControls(request.SpendingControls),
+	})
+	if err != nil {
+		return nil, issuerError(err)
+	}
+
+	return issuedCard(card), nil
+}
+
+// UpdateControls replaces the spending controls of the card on Sudo
+func (s *Sudo) UpdateControls(ctx context.Context, card *domain.Card) error {
+	controls := sudoControls(card.SpendingControls)
+	return s.update(ctx, card, sudo.UpdateCardRequest{SpendingControls: &controls})
+}
+
+// Freeze makes the card inactive on Sudo, or active again
+func (s *Sudo) Freeze(ctx context.Context, card *domain.Card, frozen bool) error {
+	status := domain.CardActive
+	if frozen {
+		status = domain.CardInactive
+	}
+
+	controls := sudoControls(card.SpendingControls)
+	return s.update(ctx, card, sudo.UpdateCardRequest{Status: string(status), SpendingControls: &controls})
+}
+
+// Cancel cancels the card on Sudo for good
+func (s *Sudo) Cancel(ctx context.Context, card *domain.Card, reason string) error {
+	controls := sudoControls(card.SpendingControls)
+	return s.update(ctx, card, sudo.UpdateCardRequest{Status: string(domain.CardCanceled), CancellationReason: reason, SpendingControls: &controls})
+}
+
+// ChangePIN changes the PIN of the card on Sudo
+func (s *Sudo) ChangePIN(ctx context.Context, card *domain.Card, oldPin string, newPin string) error {
+	err := s.client.ChangePIN(ctx, card.PartnerCardID, sudo.ChangePINRequest{OldPin: oldPin, NewPin: newPin})
+	if err != nil {
+		return issuerError(err)
+	}
+	return nil
+}
+
+// FetchCard reads the card as Sudo holds it
+func (s *Sudo) FetchCard(ctx context.Context, card *domain.Card) (*domain.IssuedCard, error) {
+	fetched, err := s.client.GetCard(ctx, card.PartnerCardID)
+	if err != nil {
+		return nil, issuerError(err)
+	}
+	return issuedCard(fetched), nil
+}
+
+// RevealCard reads the full number, CVV and expiry of the card from Sudo
+func (s *Sudo) RevealCard(ctx context.Context, card *domain.Card) (*domain.CardSecrets, error) {
+	revealed, err := s.client.RevealCard(ctx, card.PartnerCardID)
+	if err != nil {
+		return nil, issuerError(err)
+	}
+
+	return &domain.CardSecrets{
+		Number:      revealed.Number,
+		CVV:         revealed.CVV2,
+		ExpiryMonth: revealed.ExpiryMonth,
+		ExpiryYear:  revealed.ExpiryYear,
+	}, nil
+}
+
+func (s *Sudo) update(ctx context.Context, card *domain.Card, request sudo.UpdateCardRequest) error {
+	if _, err := s.client.UpdateCard(ctx, card.PartnerCardID, request); err != nil {
+		return issuerError(err)
+	}
+	return nil
+}
+
+// issuerError marks the Sudo errors worth retrying as domain.ErrIssuerUnavailable
+func issuerError(err error) error {
+	if sudo.IsTemporary(err) {
+		return fmt.Errorf("%w: %v", domain.ErrIssuerUnavailable, err)
+	}
+	return err
+}
+
+func sudoControls(controls domain.SpendingControls) sudo.SpendingControls {
+	allowed := controls.AllowedCategories
+	if allowed == nil {
+		allowed = []string{}
+	}
+
+	blocked := controls.BlockedCategories
+	if blocked == nil {
+		blocked = []string{}
+	}
+
+	return sudo.SpendingControls{
+		AllowedCategories: allowed,
+		BlockedCategories: blocked,
+		Channels: sudo.Channels{
+			Atm:    controls.Channels.Atm,
+			Web:    controls.Channels.Web,
+			Pos:    controls.Channels.Pos,
+			Mobile: controls.Channels.Mobile,
+		},
+		SpendingLimits: []sudo.SpendingLimit{
+			{
+				Amount:   controls.SpendingLimits.Amount,
+				Interval: controls.SpendingLimits.Interval,
+			},
+		},
+	}
+}
+
+func issuedCard(card *sudo.Card) *domain.IssuedCard {
+	issued := &domain.IssuedCard{
+		PartnerCardID: card.ID,
+		Type:          card.Type,
+		Brand:         card.Brand,
+		Currency:      card.Currency,
+		Status:        card.Status,
+		Business:      card.Business,
+		Account:       card.Account,
+		MaskedPan:     card.MaskedPan,
+		ExpiryMonth:   card.ExpiryMonth,
+		ExpiryYear:    card.ExpiryYear,
+		SpendingControls: domain.SpendingControls{
+			Channels: domain.Channels{
+				Pos:    card.SpendingControls.Channels.Pos,
+				Web:    card.SpendingControls.Channels.Web,
+				Atm:    card.SpendingControls.Channels.Atm,
+				Mobile: card.SpendingControls.Channels.Mobile,
+			},
+			AllowedCategories: card.SpendingControls.AllowedCategories,
+			BlockedCategories: card.SpendingControls.BlockedCategories,
+		},
+	}
+
+	if len(card.SpendingControls.SpendingLimits) > 0 {
+		issued.SpendingControls.SpendingLimits = domain.SpendingLimits{
+			Amount:   card.SpendingControls.SpendingLimits[0].Amount,
+			Interval: card.SpendingControls.SpendingLimits[0].Interval,
+		}
+	}
+	return issued
+}
@@ -0,0 +1,214 @@
+package sudo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout how long a call to Sudo may take when the context has no earlier deadline
+const DefaultTimeout = 30 * time.Second
+
+// Error an error answered by Sudo, or a call that never got an answer
+type Error struct {
+	StatusCode int
+	Message    string
+	Code       string
+	Err        error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("sudo: %v", e.Err)
+	}
+	return fmt.Sprintf("sudo: %v %v: %v", e.StatusCode, e.Code, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Temporary reports whether the call may succeed when retried: no answer, rate limited or a server error
+func (e *Error) Temporary() bool {
+	return e.StatusCode == 0 || e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
+// IsTemporary reports whether err is a Sudo error that may succeed when retried
+func IsTemporary(err error) bool {
+	var sudoErr *Error
+	return errors.As(err, &sudoErr) && sudoErr.Temporary()
+}
+
+// envelope every Sudo answer, data is decoded into the typed response of the call
+type envelope struct {
+	StatusCode int             `json:"statusCode"`
+	Message    json.RawMessage `json:"message"`
+	Error      string          `json:"error"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// Client calls the Sudo API, it holds no per-call state and is safe for concurrent use
+type Client struct {
+	baseURL    string
+	apiKey     string
+	timeout    time.Duration
+	httpClient *http.Client
+}
+
+// NewClient creates a client for the Sudo API at baseURL, a zero timeout uses DefaultTimeout
+func NewClient(baseURL string, apiKey string, timeout time.Duration) *Client {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		timeout:    timeout,
+		httpClient: &http.Client{},
+	}
+}
+
+// CreateCustomer creates a cardholder
+func (c *Client) CreateCustomer(ctx context.Context, request CreateCustomerRequest) (*Customer, error) {
+	var customer Customer
+	if err := c.do(ctx, http.MethodPost, "customers", request, &customer); err != nil {
+		return nil, err
+	}
+	return &customer, nil
+}
+
+// CreateCard issues a card to a customer
+func (c *Client) CreateCard(ctx context.Context, request CreateCardRequest) (*Card, error) {
+	var card Card
+	if err := c.do(ctx, http.MethodPost, "cards", request, &card); err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+// GetCard reads a card
+func (c *Client) GetCard(ctx context.Context, id string) (*Card, error) {
+	var card Card
+	if err := c.do(ctx, http.MethodGet, "cards/"+url.PathEscape(id), nil, &card); err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+// RevealCard reads a card with its full number and CVV, the answer must not be stored or logged
+func (c *Client) RevealCard(ctx context.Context, id string) (*RevealedCard, error) {
+	var card RevealedCard
+	if err := c.do(ctx, http.MethodGet, "cards/"+url.PathEscape(id)+"?reveal=true", nil, &card); err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+// UpdateCard changes the status or the spending controls of a card
+func (c *Client) UpdateCard(ctx context.Context, id string, request UpdateCardRequest) (*Card, error) {
+	var card Card
+	if err := c.do(ctx, http.MethodPut, "cards/"+url.PathEscape(id), request, &card); err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+// ChangePIN changes the PIN of a card
+func (c *Client) ChangePIN(ctx context.Context, id string, request ChangePINRequest) error {
+	return c.do(ctx, http.MethodPut, "cards/"+url.PathEscape(id)+"/pin", request, nil)
+}
+
+// GeneratePAN gets a test PAN from the Sudo simulator, only available in the sandbox
+func (c *Client) GeneratePAN(ctx context.Context) (*GeneratedPAN, error) {
+	var pan GeneratedPAN
+	if err := c.do(ctx, http.MethodGet, "cards/simulator/generate", nil, &pan); err != nil {
+		return nil, err
+	}
+	return &pan, nil
+}
+
+// do sends the call and decodes the data of the answer into response, anything but a 2xx answer is an *Error
+func (c *Client) do(ctx context.Context, method string, path string, body interface{}, response interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%v/%v", c.baseURL, path), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return &Error{Err: err}
+	}
+	defer res.Body.Close()
+
+	raw, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return &Error{StatusCode: res.StatusCode, Err: err}
+	}
+
+	var answer envelope
+	if err = json.Unmarshal(raw, &answer); err != nil {
+		// gateways answer outages with html pages
+		if res.StatusCode >= http.StatusInternalServerError || res.StatusCode == http.StatusTooManyRequests {
+			return &Error{StatusCode: res.StatusCode, Message: http.StatusText(res.StatusCode)}
+		}
+		return &Error{StatusCode: res.StatusCode, Err: fmt.Errorf("decoding answer: %w", err)}
+	}
+
+	// Sudo repeats the status in the body, a 200 with an error status is still an error
+	status := res.StatusCode
+	if answer.StatusCode != 0 {
+		status = answer.StatusCode
+	}
+
+	if status < 200 || status > 299 {
+		return &Error{StatusCode: status, Message: message(answer.Message), Code: answer.Error}
+	}
+
+	if response == nil || len(answer.Data) == 0 {
+		return nil
+	}
+
+	if err = json.Unmarshal(answer.Data, response); err != nil {
+		return &Error{StatusCode: status, Err: fmt.Errorf("decoding data: %w", err)}
+	}
+	return nil
+}
+
+// message flattens the message of an answer, validation errors come as a list
+func message(raw json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+
+	var list []string
+	if err := json.Unmarshal(raw, &list); err == nil {
+		return strings.Join(list, ", ")
+	}
+	return string(raw)
+}
@@ -0,0 +1,152 @@
+package sudo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCreateCard(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/cards", r.URL.Path)
+		require.Equal(t, "Bearer sk_test", r.Header.Get("Authorization"))
+
+		var body CreateCardRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, "customer-1", body.CustomerID)
+		require.Equal(t, 50000, body.SpendingControls.SpendingLimits[0].Amount)
+
+		w.Write([]byte(`{"statusCode":200,"message":"Card created successfully.","data":{"_id":"card-1","type":"virtual",
+			"brand":"Verve","currency":"NGN","maskedPan":"506321*******1234","expiryMonth":"09","expiryYear":"2027","status":"active",
+			"spendingControls":{"channels":{"atm":false,"pos":true,"web":true,"mobile":false},"allowedCategories":[],"blockedCategories":[],
+			"spendingLimits":[{"amount":50000,"interval":"daily"}]}}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL+"/", "sk_test", time.Second)
+
+	card, err := client.CreateCard(context.Background(), CreateCardRequest{
+		CustomerID:       "customer-1",
+		Type:             "virtual",
+		SpendingControls: SpendingControls{SpendingLimits: []SpendingLimit{{Amount: 50000, Interval: "daily"}}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "card-1", card.ID)
+	require.Equal(t, "active", card.Status)
+	require.True(t, card.SpendingControls.Channels.Web)
+	require.Equal(t, "daily", card.SpendingControls.SpendingLimits[0].Interval)
+}
+
+func TestRevealCard(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, "/cards/card-1", r.URL.Path)
+		require.Equal(t, "true", r.URL.Query().Get("reveal"))
+
+		w.Write([]byte(`{"statusCode":200,"message":"Card fetched successfully.","data":{"_id":"card-1","maskedPan":"506321******1234",
+			"number":"5063210000001234","cvv2":"123","expiryMonth":"09","expiryYear":"2027","status":"active"}}`))
+	}))
+	defer server.Close()
+
+	card, err := NewClient(server.URL, "sk_test", time.Second).RevealCard(context.Background(), "card-1")
+	require.NoError(t, err)
+	require.Equal(t, "card-1", card.ID)
+	require.Equal(t, "5063210000001234", card.Number)
+	require.Equal(t, "123", card.CVV2)
+	require.Equal(t, "09", card.ExpiryMonth)
+}
+
+func TestPartnerErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		code      int
+		message   string
+		temporary bool
+	}{
+		{"validation", http.StatusBadRequest, `{"statusCode":400,"message":["status must be valid","customerId should not be empty"],"error":"Bad Request"}`, 400, "status must be valid, customerId should not be empty", false},
+		{"not found", http.StatusNotFound, `{"statusCode":404,"message":"Card not found.","error":"Not Found"}`, 404, "Card not found.", false},
+		{"error in a 200", http.StatusOK, `{"statusCode":401,"message":"Unauthorized"}`, 401, "Unauthorized", false},
+		{"rate limited", http.StatusTooManyRequests, `{"statusCode":429,"message":"Too Many Requests"}`, 429, "Too Many Requests", true},
+		{"gateway outage", http.StatusBadGateway, `<html>Bad Gateway</html>`, 502, "Bad Gateway", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.status)
+				w.Write([]byte(test.body))
+			}))
+			defer server.Close()
+
+			_, err := NewClient(server.URL, "sk_test", time.Second).GetCard(context.Background(), "card-1")
+
+			var sudoErr *Error
+			require.True(t, errors.As(err, &sudoErr))
+			require.Equal(t, test.code, sudoErr.StatusCode)
+			require.Equal(t, test.message, sudoErr.Message)
+			require.Equal(t, test.temporary, IsTemporary(err))
+		})
+	}
+}
+
+func TestTimeoutIsTemporary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte(`{"statusCode":200,"data":{}}`))
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "sk_test", 20*time.Millisecond).GetCard(context.Background(), "card-1")
+	require.Error(t, err)
+	require.True(t, IsTemporary(err))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = NewClient(server.URL, "sk_test", time.Second).GetCard(ctx, "card-1")
+	require.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestConcurrentCalls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body UpdateCardRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.Write([]byte(`{"statusCode":200,"data":{"_id":"` + r.URL.Path[len("/cards/"):] + `","status":"` + body.Status + `"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "sk_test", time.Second)
+
+	var wg sync.WaitGroup
+	for _, status := range []string{"active", "inactive", "canceled", "active", "inactive"} {
+		wg.Add(1)
+		go func(status string) {
+			defer wg.Done()
+			card, err := client.UpdateCard(context.Background(), "card-"+status, UpdateCardRequest{Status: status})
+			require.NoError(t, err)
+			require.Equal(t, "card-"+status, card.ID)
+			require.Equal(t, status, card.Status)
+		}(status)
+	}
+	wg.Wait()
+}
+
+func TestChangePIN(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPut, r.Method)
+		require.Equal(t, "/cards/card-1/pin", r.URL.Path)
+		w.Write([]byte(`{"statusCode":200,"message":"Card PIN changed."}`))
+	}))
+	defer server.Close()
+
+	err := NewClient(server.URL, "sk_test", time.Second).ChangePIN(context.Background(), "card-1", ChangePINRequest{OldPin: "1234", NewPin: "4321"})
+	require.NoError(t, err)
+}
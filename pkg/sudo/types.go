@@ -0,0 +1,131 @@
+package sudo
+
+import (
+	"time"
+)
+
+// BillingAddress address of a customer
+type BillingAddress struct {
+	Line1      string `json:"line1"`
+	City       string `json:"city"`
+	State      string `json:"state"`
+	PostalCode string `json:"postalCode"`
+	Country    string `json:"country"`
+}
+
+// Individual names of an individual customer
+type Individual struct {
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+// Company company of a customer
+type Company struct {
+	Name string `json:"name"`
+}
+
+// CreateCustomerRequest body of POST /customers
+type CreateCustomerRequest struct {
+	Type           string         `json:"type"`
+	Name           string         `json:"name"`
+	Status         string         `json:"status"`
+	PhoneNumber    string         `json:"phoneNumber"`
+	EmailAddress   string         `json:"emailAddress"`
+	Individual     Individual     `json:"individual"`
+	Company        Company        `json:"company"`
+	BillingAddress BillingAddress `json:"billingAddress"`
+}
+
+// Customer a customer on Sudo
+type Customer struct {
+	ID             string         `json:"_id"`
+	Business       string         `json:"business"`
+	Type           string         `json:"type"`
+	Name           string         `json:"name"`
+	Status         string         `json:"status"`
+	PhoneNumber    string         `json:"phoneNumber"`
+	EmailAddress   string         `json:"emailAddress"`
+	BillingAddress BillingAddress `json:"billingAddress"`
+	CreatedAt      time.Time      `json:"createdAt"`
+	UpdatedAt      time.Time      `json:"updatedAt"`
+}
+
+// Channels channels a card can be used on
+type Channels struct {
+	Atm    bool `json:"atm"`
+	Pos    bool `json:"pos"`
+	Web    bool `json:"web"`
+	Mobile bool `json:"mobile"`
+}
+
+// SpendingLimit amount a card can spend in an interval
+type SpendingLimit struct {
+	Amount     int      `json:"amount"`
+	Interval   string   `json:"interval"`
+	Categories []string `json:"categories,omitempty"`
+}
+
+// SpendingControls where and how much a card can spend
+type SpendingControls struct {
+	Channels          Channels        `json:"channels"`
+	AllowedCategories []string        `json:"allowedCategories"`
+	BlockedCategories []string        `json:"blockedCategories"`
+	SpendingLimits    []SpendingLimit `json:"spendingLimits"`
+}
+
+// CreateCardRequest body of POST /cards, Number is the PAN of a physical card
+type CreateCardRequest struct {
+	CustomerID       string           `json:"customerId"`
+	FundingSourceID  string           `json:"fundingSourceId"`
+	Type             string           `json:"type"`
+	Brand            string           `json:"brand"`
+	Currency         string           `json:"currency"`
+	Status           string           `json:"status"`
+	Number           string           `json:"number,omitempty"`
+	SpendingControls SpendingControls `json:"spendingControls"`
+}
+
+// UpdateCardRequest body of PUT /cards/{id}, fields left empty are not changed
+type UpdateCardRequest struct {
+	Status             string            `json:"status,omitempty"`
+	CancellationReason string            `json:"cancellationReason,omitempty"`
+	SpendingControls   *SpendingControls `json:"spendingControls,omitempty"`
+}
+
+// ChangePINRequest body of PUT /cards/{id}/pin
+type ChangePINRequest struct {
+	OldPin string `json:"oldPin"`
+	NewPin string `json:"newPin"`
+}
+
+// Card a card on Sudo
+type Card struct {
+	ID               string           `json:"_id"`
+	Business         string           `json:"business"`
+	Customer         string           `json:"customer"`
+	Account          string           `json:"account"`
+	FundingSource    string           `json:"fundingSource"`
+	Type             string           `json:"type"`
+	Brand            string           `json:"brand"`
+	Currency         string           `json:"currency"`
+	MaskedPan        string           `json:"maskedPan"`
+	ExpiryMonth      string           `json:"expiryMonth"`
+	ExpiryYear       string           `json:"expiryYear"`
+	Status           string           `json:"status"`
+	SpendingControls SpendingControls `json:"spendingControls"`
+	CreatedAt        time.Time        `json:"createdAt"`
+	UpdatedAt        time.Time        `json:"updatedAt"`
+}
+
+// RevealedCard a card with its full number and CVV, only answered when the card is revealed
+type RevealedCard struct {
+	Card
+	Number string `json:"number"`
+	CVV2   string `json:"cvv2"`
+}
+
+// GeneratedPAN a test PAN from the Sudo simulator
+type GeneratedPAN struct {
+	Brand  string `json:"brand"`
+	Number string `json:"number"`
+}
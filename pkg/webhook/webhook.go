@@ -0,0 +1,95 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// IDHeader carries the delivery id, receivers use it to drop duplicates
+	IDHeader = "X-Webhook-ID"
+	// EventHeader carries the event type
+	EventHeader = "X-Webhook-Event"
+	// TimestampHeader carries the unix time the payload was signed at
+	TimestampHeader = "X-Webhook-Timestamp"
+	// SignatureHeader carries the hex HMAC-SHA256 of "timestamp.payload" keyed with the subscription secret
+	SignatureHeader = "X-Webhook-Signature"
+
+	baseDelay = 30 * time.Second
+	maxDelay  = 6 * time.Hour
+)
+
+// Sign returns the signature of a payload
+func Sign(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of a payload, receivers should also reject stale timestamps
+func Verify(secret string, signature string, timestamp int64, payload []byte) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, payload)), []byte(signature))
+}
+
+// Backoff returns how long to wait after the given failed attempt, doubling from 30 seconds up to 6 hours
+func Backoff(attempt int) time.Duration {
+	delay := baseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= maxDelay {
+			return maxDelay
+		}
+	}
+	return delay
+}
+
+// Sender delivers signed payloads
+type Sender struct {
+	Client *http.Client
+}
+
+// NewSender creates a sender with a 10 seconds timeout
+func NewSender() *Sender {
+	return &Sender{Client: &http.Client{Timeout: time.Duration(10) * time.Second}}
+}
+
+// Send posts the payload to url and returns the response status code, any status other than 2xx is an error
+func (s *Sender) Send(url, secret, id, event string, payload []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(IDHeader, id)
+	req.Header.Set(EventHeader, event)
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(secret, timestamp, payload))
+
+	response, err := s.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(response.Body)
+
+	_, _ = io.Copy(ioutil.Discard, response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, fmt.Errorf("receiver responded with %v", response.StatusCode)
+	}
+	return response.StatusCode, nil
+}
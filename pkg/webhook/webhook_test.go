@@ -0,0 +1,58 @@
+package webhook
+
+import (
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSendSignedPayload(t *testing.T) {
+	secret := "whsec_test"
+	payload := []byte(`{"type":"card.created"}`)
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		timestamp, err := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+		require.NoError(t, err)
+
+		require.Equal(t, "delivery-1", r.Header.Get(IDHeader))
+		require.Equal(t, "card.created", r.Header.Get(EventHeader))
+		require.True(t, Verify(secret, r.Header.Get(SignatureHeader), timestamp, body))
+		require.False(t, Verify("another secret", r.Header.Get(SignatureHeader), timestamp, body))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	status, err := NewSender().Send(receiver.URL, secret, "delivery-1", "card.created", payload)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, status)
+}
+
+func TestSendFailedDelivery(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	status, err := NewSender().Send(receiver.URL, "secret", "delivery-1", "card.created", []byte(`{}`))
+	require.Error(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, status)
+
+	receiver.Close()
+	status, err = NewSender().Send(receiver.URL, "secret", "delivery-1", "card.created", []byte(`{}`))
+	require.Error(t, err)
+	require.Equal(t, 0, status)
+}
+
+func TestBackoff(t *testing.T) {
+	require.Equal(t, 30*time.Second, Backoff(1))
+	require.Equal(t, time.Minute, Backoff(2))
+	require.Equal(t, 4*time.Minute, Backoff(4))
+	require.Equal(t, 6*time.Hour, Backoff(20))
+}